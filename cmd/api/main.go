@@ -11,6 +11,7 @@ import (
 	"github.com/Parchat/backend/internal/config"
 	"github.com/Parchat/backend/internal/handlers"
 	"github.com/Parchat/backend/internal/middleware"
+	"github.com/Parchat/backend/internal/models"
 	"github.com/Parchat/backend/internal/pkg/websocket"
 	"github.com/Parchat/backend/internal/repositories"
 	"github.com/Parchat/backend/internal/routes"
@@ -50,15 +51,48 @@ func main() {
 			repositories.NewDirectChatRepository,
 			repositories.NewMessageRepository,
 			repositories.NewReportRepository,
+			repositories.NewBookmarkRepository,
+			repositories.NewInviteRepository,
+			repositories.NewJoinRequestRepository,
+			repositories.NewSpaceRepository,
+			repositories.NewRoomStatsRepository,
+			repositories.NewNotificationRepository,
+			repositories.NewKeyRepository,
+			repositories.NewSanctionRepository,
+			repositories.NewAuditRepository,
 			services.NewAuthService,
 			services.NewUserService,
+			services.NewPermissionService,
 			services.NewRoomService,
 			services.NewDirectChatService,
 			services.NewModerationService,
+			services.NewBookmarkService,
+			services.NewInviteService,
+			services.NewJoinRequestService,
+			services.NewSpaceService,
+			services.NewRoomStatsService,
+			services.NewNotificationService,
+			services.NewKeyService,
+			services.NewSanctionService,
+			services.NewAuditService,
+			services.NewContentFilterService,
 			handlers.NewAuthHandler,
 			handlers.NewUserHandler,
 			handlers.NewChatHandler,
 			handlers.NewModerationHandler,
+			handlers.NewBookmarkHandler,
+			handlers.NewRoomAdminHandler,
+			handlers.NewInviteHandler,
+			handlers.NewJoinRequestHandler,
+			handlers.NewSpaceHandler,
+			handlers.NewRoomStatsHandler,
+			handlers.NewNotificationHandler,
+			handlers.NewGroupChatHandler,
+			handlers.NewMessageRequestHandler,
+			handlers.NewKeyHandler,
+			handlers.NewSanctionHandler,
+			handlers.NewAuditHandler,
+			handlers.NewContentFilterHandler,
 			middleware.NewAuthMiddleware,
 
 			// Proveedores de WebSocket
@@ -69,7 +103,7 @@ func main() {
 		),
 		config.SwaggerModule,
 		// Invocadores
-		fx.Invoke(registerHooks, runWebSocketHub),
+		fx.Invoke(registerHooks, runWebSocketHub, migrateRooms, expireSanctions),
 	)
 
 	app.Run()
@@ -119,3 +153,67 @@ func runWebSocketHub(lifecycle fx.Lifecycle, hub *websocket.Hub) {
 		},
 	})
 }
+
+// migrateRooms completa los roles y los datos de descubrimiento de las salas creadas antes de existir,
+// y el estado de los reportes antiguos
+func migrateRooms(lifecycle fx.Lifecycle, roomService *services.RoomService, moderationService *services.ModerationService) {
+	lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				if err := roomService.MigrateRooms(); err != nil {
+					log.Printf("Error migrating rooms: %v", err)
+				}
+				if err := moderationService.MigrateReports(); err != nil {
+					log.Printf("Error migrating reports: %v", err)
+				}
+			}()
+			return nil
+		},
+	})
+}
+
+// sanctionExpiryInterval es cada cuánto se levantan los silencios y baneos caducados
+const sanctionExpiryInterval = time.Minute
+
+// expireSanctions levanta periódicamente los silencios y baneos caducados y avisa a las salas
+func expireSanctions(lifecycle fx.Lifecycle, sanctionService *services.SanctionService, hub *websocket.Hub) {
+	done := make(chan struct{})
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				ticker := time.NewTicker(sanctionExpiryInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-done:
+						return
+					case <-ticker.C:
+						expired, err := sanctionService.ExpireSanctions()
+						if err != nil {
+							log.Printf("Error expiring sanctions: %v", err)
+							continue
+						}
+
+						for _, sanction := range expired {
+							messageType := websocket.MessageTypeMemberUnmuted
+							if sanction.Type == models.SanctionBan {
+								messageType = websocket.MessageTypeMemberUnbanned
+							}
+							hub.BroadcastToRoom(sanction.RoomID, messageType, websocket.SanctionPayload{
+								RoomID: sanction.RoomID,
+								UserID: sanction.UserID,
+							})
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(done)
+			return nil
+		},
+	})
+}
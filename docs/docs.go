@@ -24,14 +24,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/me": {
+        "/admin/audit-log": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Devuelve los datos del usuario autenticado basado en el ID del contexto",
+                "description": "Devuelve las acciones de moderación y administración de todas las salas, de la más reciente a la más antigua. Solo para administradores de la plataforma (PLATFORM_ADMIN_IDS)",
                 "consumes": [
                     "application/json"
                 ],
@@ -39,14 +39,83 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Audit"
+                ],
+                "summary": "Registro de auditoría completo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filtra por sala",
+                        "name": "roomId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra por autor",
+                        "name": "actorId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra por objetivo",
+                        "name": "targetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra por acción",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde esta fecha (RFC3339, incluida)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta esta fecha (RFC3339, excluida)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Entradas por página (máximo 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor de la página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Obtiene el usuario actual",
                 "responses": {
                     "200": {
-                        "description": "Datos del usuario actual",
+                        "description": "Entradas del registro",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.PaginatedAuditLogResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Filtros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No es administrador de la plataforma",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -58,39 +127,98 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/signup": {
-            "post": {
-                "description": "Crea un nuevo usuario en Firebase Authentication y lo guarda en Firestore",
-                "consumes": [
-                    "application/json"
+        "/admin/audit-log/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Descarga de una vez hasta 10000 entradas que cumplen los filtros, en CSV o JSON. En CSV los estados anterior y posterior van como JSON. Solo para administradores de la plataforma (PLATFORM_ADMIN_IDS)",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "Auth"
+                    "Audit"
                 ],
-                "summary": "Registra un nuevo usuario",
+                "summary": "Exporta el registro de auditoría",
                 "parameters": [
                     {
-                        "description": "Datos del usuario a registrar",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.PayloadSignUp"
-                        }
+                        "type": "string",
+                        "default": "csv",
+                        "description": "csv o json",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra por sala",
+                        "name": "roomId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra por autor",
+                        "name": "actorId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra por objetivo",
+                        "name": "targetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra por acción",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde esta fecha (RFC3339, incluida)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta esta fecha (RFC3339, excluida)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10000,
+                        "description": "Máximo de entradas (hasta 10000)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Usuario creado exitosamente",
+                    "200": {
+                        "description": "Entradas del registro",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Solicitud inválida",
+                        "description": "Filtros o formato inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No es administrador de la plataforma",
                         "schema": {
                             "type": "string"
                         }
@@ -104,14 +232,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/direct/me": {
+        "/auth/me": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Devuelve todos los chats directos del usuario autenticado",
+                "description": "Devuelve los datos del usuario autenticado basado en el ID del contexto",
                 "consumes": [
                     "application/json"
                 ],
@@ -119,21 +247,58 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "Auth"
                 ],
-                "summary": "Obtiene chats directos",
+                "summary": "Obtiene el usuario actual",
                 "responses": {
                     "200": {
-                        "description": "Lista de chats directos",
+                        "description": "Datos del usuario actual",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.DirectChat"
-                            }
+                            "$ref": "#/definitions/models.User"
                         }
                     },
-                    "401": {
-                        "description": "No autorizado",
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/signup": {
+            "post": {
+                "description": "Crea un nuevo usuario en Firebase Authentication y lo guarda en Firestore",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Registra un nuevo usuario",
+                "parameters": [
+                    {
+                        "description": "Datos del usuario a registrar",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PayloadSignUp"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Usuario creado exitosamente",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
                         "schema": {
                             "type": "string"
                         }
@@ -147,14 +312,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/direct/{chatId}": {
-            "get": {
+        "/chat/direct/group": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Devuelve los detalles de un chat directo específico",
+                "description": "Crea un chat directo entre el usuario autenticado y varios usuarios (3 a 20 participantes en total). Si ya existe un chat de grupo con exactamente los mismos participantes se devuelve ese",
                 "consumes": [
                     "application/json"
                 ],
@@ -164,31 +329,51 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "Obtiene un chat directo por ID",
+                "summary": "Crea un chat de grupo",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID del chat directo",
-                        "name": "chatId",
-                        "in": "path",
-                        "required": true
+                        "description": "Participantes y título opcional",
+                        "name": "chat",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateGroupDirectChatRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Detalles del chat directo",
+                        "description": "Chat de grupo existente",
+                        "schema": {
+                            "$ref": "#/definitions/models.DirectChat"
+                        }
+                    },
+                    "201": {
+                        "description": "Chat de grupo creado",
                         "schema": {
                             "$ref": "#/definitions/models.DirectChat"
                         }
                     },
+                    "400": {
+                        "description": "Solicitud inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "401": {
                         "description": "No autorizado",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "La privacidad de algún participante o un bloqueo no lo permite",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
-                        "description": "Chat no encontrado",
+                        "description": "Usuario no encontrado",
                         "schema": {
                             "type": "string"
                         }
@@ -202,14 +387,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/direct/{chatId}/messages": {
+        "/chat/direct/me": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Devuelve los mensajes de un chat directo específico",
+                "description": "Devuelve los chats directos del usuario autenticado, con sus preferencias de notificación. Sin archived solo se incluyen los no archivados. Las solicitudes de mensajes recibidas sin aceptar y los chats borrados por el usuario no se incluyen",
                 "consumes": [
                     "application/json"
                 ],
@@ -219,30 +404,22 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "Obtiene mensajes de un chat directo",
+                "summary": "Obtiene chats directos",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID del chat directo",
-                        "name": "chatId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Límite de mensajes a obtener",
-                        "name": "limit",
+                        "type": "boolean",
+                        "description": "Listar solo los chats archivados",
+                        "name": "archived",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Lista de mensajes del chat directo",
+                        "description": "Lista de chats directos",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Message"
+                                "$ref": "#/definitions/models.DirectChat"
                             }
                         }
                     },
@@ -252,18 +429,6 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Acceso prohibido",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Chat no encontrado",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "500": {
                         "description": "Error interno del servidor",
                         "schema": {
@@ -273,14 +438,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/direct/{otherUserId}": {
-            "post": {
+        "/chat/direct/requests": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Crea o encuentra un chat directo entre el usuario autenticado y otro usuario",
+                "description": "Devuelve los chats directos que abrieron usuarios que no son contactos del usuario autenticado y que aún no ha aceptado ni rechazado, y los chats de grupo a los que lo añadió alguien que no es su contacto",
                 "consumes": [
                     "application/json"
                 ],
@@ -290,27 +455,15 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "Crea un chat directo",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID del otro usuario",
-                        "name": "otherUserId",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Solicitudes de mensajes",
                 "responses": {
-                    "201": {
-                        "description": "Chat directo creado o encontrado",
-                        "schema": {
-                            "$ref": "#/definitions/models.DirectChat"
-                        }
-                    },
-                    "400": {
-                        "description": "Solicitud inválida",
+                    "200": {
+                        "description": "Solicitudes pendientes",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DirectChat"
+                            }
                         }
                     },
                     "401": {
@@ -328,14 +481,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/rooms": {
+        "/chat/direct/{chatId}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Devuelve todas las salas ordenadas por fecha de actualización descendente",
+                "description": "Devuelve los detalles de un chat directo específico",
                 "consumes": [
                     "application/json"
                 ],
@@ -345,15 +498,21 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "Obtiene todas las salas",
+                "summary": "Obtiene un chat directo por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del chat directo",
+                        "name": "chatId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Lista de salas",
+                        "description": "Detalles del chat directo",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Room"
-                            }
+                            "$ref": "#/definitions/models.DirectChat"
                         }
                     },
                     "401": {
@@ -362,6 +521,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Chat no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Error interno del servidor",
                         "schema": {
@@ -370,13 +535,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Crea una nueva sala de chat con el usuario actual como propietario",
+                "description": "Cambia el título de un chat de grupo. Cualquier participante puede hacerlo; un título vacío lo quita",
                 "consumes": [
                     "application/json"
                 ],
@@ -386,27 +551,34 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "Crea una nueva sala de chat",
+                "summary": "Cambia un chat de grupo",
                 "parameters": [
                     {
-                        "description": "Detalles de la sala",
-                        "name": "room",
+                        "type": "string",
+                        "description": "ID del chat de grupo",
+                        "name": "chatId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cambios del chat",
+                        "name": "chat",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateRoomRequest"
+                            "$ref": "#/definitions/models.UpdateDirectChatRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Sala creada exitosamente",
+                    "200": {
+                        "description": "Chat actualizado",
                         "schema": {
-                            "$ref": "#/definitions/models.Room"
+                            "$ref": "#/definitions/models.DirectChat"
                         }
                     },
                     "400": {
-                        "description": "Solicitud inválida",
+                        "description": "Solicitud inválida o el chat no es de grupo",
                         "schema": {
                             "type": "string"
                         }
@@ -417,6 +589,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Chat no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Error interno del servidor",
                         "schema": {
@@ -424,16 +602,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/chat/rooms/me": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Devuelve todas las salas a las que pertenece el usuario autenticado",
+                "description": "Oculta el chat y vacía su historial solo para el usuario autenticado; los demás participantes lo conservan. Si llega un mensaje nuevo, el chat reaparece con los mensajes posteriores al borrado",
                 "consumes": [
                     "application/json"
                 ],
@@ -443,15 +619,21 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "Obtiene las salas del usuario",
+                "summary": "Borra un chat directo para el usuario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del chat directo",
+                        "name": "chatId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Lista de salas",
+                        "description": "Chat borrado para el usuario",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Room"
-                            }
+                            "type": "string"
                         }
                     },
                     "401": {
@@ -460,6 +642,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Chat no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Error interno del servidor",
                         "schema": {
@@ -469,14 +657,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/rooms/{roomId}": {
-            "get": {
+        "/chat/direct/{chatId}/accept": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Devuelve los detalles de una sala específica",
+                "description": "El chat pasa a la lista de chats directos del usuario y el remitente recibe un aviso. También se puede aceptar una solicitud rechazada antes. En un chat de grupo el usuario pasa a ser un participante más y se avisa al grupo",
                 "consumes": [
                     "application/json"
                 ],
@@ -486,21 +674,21 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "Obtiene una sala por ID",
+                "summary": "Acepta una solicitud de mensajes",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID de la sala",
-                        "name": "roomId",
+                        "description": "ID del chat directo",
+                        "name": "chatId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Detalles de la sala",
+                        "description": "Solicitud aceptada",
                         "schema": {
-                            "$ref": "#/definitions/models.Room"
+                            "$ref": "#/definitions/models.DirectChat"
                         }
                     },
                     "401": {
@@ -510,7 +698,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Sala no encontrada",
+                        "description": "Solicitud no encontrada",
                         "schema": {
                             "type": "string"
                         }
@@ -524,14 +712,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/rooms/{roomId}/banned-users": {
-            "get": {
+        "/chat/direct/{chatId}/archive": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of users who have been banned in a chat room",
+                "description": "Mueve el chat a los archivados del usuario autenticado. Vuelve a la lista principal al llegar un mensaje nuevo",
                 "consumes": [
                     "application/json"
                 ],
@@ -539,60 +727,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Moderation"
+                    "Chat"
                 ],
-                "summary": "Get banned users in a room",
+                "summary": "Archiva un chat directo",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Room ID",
-                        "name": "roomId",
+                        "description": "ID del chat directo",
+                        "name": "chatId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Banned users",
-                        "schema": {
-                            "$ref": "#/definitions/models.BannedUsersResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "Chat archivado",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "No autorizado",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Not found",
+                        "description": "Chat no encontrado",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error interno del servidor",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/chat/rooms/{roomId}/clear-reports": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Clears all reports for a specific user in a chat room",
+                "description": "Devuelve el chat a la lista principal del usuario autenticado",
                 "consumes": [
                     "application/json"
                 ],
@@ -600,60 +780,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Moderation"
+                    "Chat"
                 ],
-                "summary": "Clear reports for a user",
+                "summary": "Desarchiva un chat directo",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Room ID",
-                        "name": "roomId",
+                        "description": "ID del chat directo",
+                        "name": "chatId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Clear Report Request",
-                        "name": "clearRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ClearReportRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Reports cleared successfully",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request",
+                        "description": "Chat desarchivado",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
+                        "description": "No autorizado",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Not found",
+                        "description": "Chat no encontrado",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error interno del servidor",
                         "schema": {
                             "type": "string"
                         }
@@ -661,14 +820,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/rooms/{roomId}/join": {
+        "/chat/direct/{chatId}/block": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Permite al usuario autenticado unirse a una sala específica",
+                "description": "Rechaza la solicitud sin avisar al remitente y lo añade a los usuarios bloqueados. En un chat de grupo el usuario sale del grupo y se bloquea a quien lo añadió",
                 "consumes": [
                     "application/json"
                 ],
@@ -678,21 +837,21 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "Unirse a una sala",
+                "summary": "Rechaza una solicitud y bloquea al remitente",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID de la sala",
-                        "name": "roomId",
+                        "description": "ID del chat directo",
+                        "name": "chatId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Usuario unido exitosamente",
+                        "description": "Solicitud rechazada y remitente bloqueado",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.DirectChat"
                         }
                     },
                     "401": {
@@ -701,20 +860,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "No permitido unirse a esta sala",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "404": {
-                        "description": "Sala no encontrada",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "409": {
-                        "description": "Usuario ya es miembro de la sala",
+                        "description": "Solicitud no encontrada",
                         "schema": {
                             "type": "string"
                         }
@@ -728,14 +875,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/rooms/{roomId}/messages": {
-            "get": {
+        "/chat/direct/{chatId}/decline": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Devuelve los mensajes de una sala específica sin paginación",
+                "description": "Oculta la solicitud sin avisar al remitente, que ya no podrá enviar mensajes en el chat. En un chat de grupo el usuario sale del grupo",
                 "consumes": [
                     "application/json"
                 ],
@@ -745,31 +892,21 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "Obtiene mensajes de una sala (versión simple)",
+                "summary": "Rechaza una solicitud de mensajes",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID de la sala",
-                        "name": "roomId",
+                        "description": "ID del chat directo",
+                        "name": "chatId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Límite de mensajes a obtener",
-                        "name": "limit",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Lista de mensajes de la sala",
+                        "description": "Solicitud rechazada",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MessageResponse"
-                            }
+                            "$ref": "#/definitions/models.DirectChat"
                         }
                     },
                     "401": {
@@ -779,7 +916,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Sala no encontrada",
+                        "description": "Solicitud no encontrada",
                         "schema": {
                             "type": "string"
                         }
@@ -793,14 +930,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/rooms/{roomId}/messages/paginated": {
-            "get": {
+        "/chat/direct/{chatId}/encryption": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Devuelve los mensajes de una sala específica con soporte para paginación ordernada por fecha de creación descendente",
+                "description": "Activa el cifrado de extremo a extremo de un chat directo. No se puede desactivar. Todos los participantes deben haber publicado claves; desde entonces el chat solo admite mensajes con sobres cifrados y contenido vacío",
                 "consumes": [
                     "application/json"
                 ],
@@ -808,37 +945,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "Encryption"
                 ],
-                "summary": "Obtiene mensajes de una sala",
+                "summary": "Activa el cifrado de un chat directo",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID de la sala",
-                        "name": "roomId",
+                        "description": "ID del chat directo",
+                        "name": "chatId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Límite de mensajes a obtener",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "\"1747441934\"",
-                        "description": "Cursor para paginación (timestamp)",
-                        "name": "cursor",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Mensajes paginados de la sala",
+                        "description": "Chat cifrado",
                         "schema": {
-                            "$ref": "#/definitions/models.PaginatedMessagesResponse"
+                            "$ref": "#/definitions/models.DirectChat"
                         }
                     },
                     "401": {
@@ -848,7 +971,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Sala no encontrada",
+                        "description": "Chat no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Algún participante no ha publicado claves",
                         "schema": {
                             "type": "string"
                         }
@@ -862,14 +991,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/rooms/{roomId}/report": {
+        "/chat/direct/{chatId}/leave": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Reports a message as inappropriate in a chat room",
+                "description": "Saca al usuario autenticado de un chat de grupo. Si era el creador, el siguiente participante pasa a serlo",
                 "consumes": [
                     "application/json"
                 ],
@@ -877,60 +1006,116 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Moderation"
+                    "Chat"
                 ],
-                "summary": "Report an inappropriate message",
+                "summary": "Abandona un chat de grupo",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Room ID",
-                        "name": "roomId",
+                        "description": "ID del chat de grupo",
+                        "name": "chatId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Report Request",
-                        "name": "report",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ReportRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Message reported successfully",
+                        "description": "Chat actualizado",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.DirectChat"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "El chat no es de grupo",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Chat no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/direct/{chatId}/messages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve los mensajes de un chat directo específico",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Obtiene mensajes de un chat directo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del chat directo",
+                        "name": "chatId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Límite de mensajes a obtener",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lista de mensajes del chat directo",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Message"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "403": {
-                        "description": "Forbidden",
+                        "description": "Acceso prohibido",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Not found",
+                        "description": "Chat no encontrado",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error interno del servidor",
                         "schema": {
                             "type": "string"
                         }
@@ -938,9 +1123,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/ws": {
+        "/chat/direct/{chatId}/notifications": {
             "get": {
-                "description": "Establece una conexión WebSocket para mensajería en tiempo real",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve las preferencias del usuario para el chat, o las de por defecto si no las ha configurado",
                 "consumes": [
                     "application/json"
                 ],
@@ -948,21 +1138,89 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "Notifications"
                 ],
-                "summary": "Conexión WebSocket para chat en tiempo real",
+                "summary": "Preferencias de notificación de un chat directo",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Firebase Auth Token",
-                        "name": "token",
-                        "in": "query",
+                        "description": "ID del chat directo",
+                        "name": "chatId",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols a WebSocket",
+                    "200": {
+                        "description": "Preferencias del chat",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreference"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Chat no encontrado o el usuario no participa",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Silencia el chat indefinidamente (muted) o hasta una fecha (mutedUntil), activa el modo solo menciones o cambia sonido y push. Solo se actualizan los campos enviados",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Cambia las preferencias de notificación de un chat directo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del chat directo",
+                        "name": "chatId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preferencias a cambiar",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateNotificationPreferenceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preferencias actualizadas",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreference"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
                         "schema": {
                             "type": "string"
                         }
@@ -972,18 +1230,30 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "Chat no encontrado o el usuario no participa",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/user/create": {
+        "/chat/direct/{chatId}/participants": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Verifica si el usuario autenticado existe en la base de datos, si no, lo crea con los datos de autenticación",
+                "description": "Añade un usuario a un chat de grupo. Cualquier participante puede hacerlo",
                 "consumes": [
                     "application/json"
                 ],
@@ -991,14 +1261,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Chat"
+                ],
+                "summary": "Añade un participante",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del chat de grupo",
+                        "name": "chatId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Usuario a añadir",
+                        "name": "participant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AddDirectChatParticipantRequest"
+                        }
+                    }
                 ],
-                "summary": "Asegura que el usuario exista en la base de datos",
                 "responses": {
                     "200": {
-                        "description": "Datos del usuario",
+                        "description": "Chat actualizado",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.DirectChat"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida, el chat no es de grupo o está lleno",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "La privacidad del usuario o un bloqueo no lo permite",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Chat o usuario no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "El usuario ya participa en el chat",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -1009,260 +1327,6939 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.PayloadSignUp": {
-            "type": "object",
-            "properties": {
-                "displayName": {
-                    "type": "string"
+        },
+        "/chat/direct/{chatId}/participants/{userId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Quita a un usuario de un chat de grupo. Solo el creador del grupo puede quitar a otros; quitarse a uno mismo equivale a abandonar el chat",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Quita un participante",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del chat de grupo",
+                        "name": "chatId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del participante",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Chat actualizado",
+                        "schema": {
+                            "$ref": "#/definitions/models.DirectChat"
+                        }
+                    },
+                    "400": {
+                        "description": "El chat no es de grupo",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Solo el creador puede quitar participantes",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Chat o participante no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/direct/{otherUserId}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crea o encuentra un chat directo entre el usuario autenticado y otro usuario. Si el usuario autenticado no es contacto del otro (no está en su lista de contactos ni comparten sala), el chat nuevo llega como solicitud de mensajes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Crea un chat directo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del otro usuario",
+                        "name": "otherUserId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Chat directo creado o encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/models.DirectChat"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Uno de los dos usuarios bloqueó al otro o la privacidad del otro usuario no lo permite",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Usuario no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve salas públicas no eliminadas, con búsqueda por nombre y descripción, filtro por categoría y orden por actividad o número de miembros",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Descubre salas públicas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Texto a buscar en el nombre y la descripción",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Categoría de la sala",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "activity",
+                            "members"
+                        ],
+                        "type": "string",
+                        "default": "activity",
+                        "description": "Orden de los resultados",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Número de salas por página (máximo 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto por la página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Página de salas",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedRoomsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crea una nueva sala de chat con el usuario actual como propietario y único miembro. Los demás usuarios entran uniéndose, con una invitación o con una solicitud aprobada",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Crea una nueva sala de chat",
+                "parameters": [
+                    {
+                        "description": "Detalles de la sala",
+                        "name": "room",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateRoomRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Sala creada exitosamente",
+                        "schema": {
+                            "$ref": "#/definitions/models.Room"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve todas las salas a las que pertenece el usuario autenticado, con sus preferencias de notificación",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Obtiene las salas del usuario",
+                "responses": {
+                    "200": {
+                        "description": "Lista de salas",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Room"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve los detalles de una sala específica. Las salas privadas solo son visibles para sus miembros, y los usuarios baneados no pueden verla",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Obtiene una sala por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Detalles de la sala",
+                        "schema": {
+                            "$ref": "#/definitions/models.Room"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Sala privada de la que el usuario no es miembro, o usuario baneado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Actualiza el nombre, descripción, imagen, privacidad, categorías, modo lento, umbral de reportes (reportThreshold) o ventana de reportes (reportWindowHours) de una sala. Requiere la capacidad manage_room",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Actualiza una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campos a actualizar",
+                        "name": "room",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateRoomRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sala actualizada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Room"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido actualizar la sala",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marca una sala como eliminada. Solo el propietario puede hacerlo",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Elimina una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sala eliminada exitosamente",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Solo el propietario puede eliminar la sala",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/admins/{userId}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Asigna el rol \"admin\" a un miembro de la sala. Requiere la capacidad manage_roles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Promueve a un miembro a admin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del miembro",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Miembro promovido a admin",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido gestionar roles",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada o usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "El usuario ya es admin",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve a un admin al rol \"member\". Requiere la capacidad manage_roles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Quita el rol de admin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del admin",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Admin degradado a miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido gestionar roles",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada o usuario no es admin",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/audit-log": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve las acciones de moderación y administración de la sala, de la más reciente a la más antigua, con su autor, objetivo, estado anterior y posterior y motivo. Requiere la capacidad manage_room",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Registro de auditoría de una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra por autor",
+                        "name": "actorId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra por objetivo",
+                        "name": "targetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra por acción",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde esta fecha (RFC3339, incluida)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta esta fecha (RFC3339, excluida)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Entradas por página (máximo 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor de la página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entradas del registro",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedAuditLogResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Filtros inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido ver el registro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/banned-users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the users whose weighted reports within the room's window reach its report threshold",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Get banned users in a room",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Banned users",
+                        "schema": {
+                            "$ref": "#/definitions/models.BannedUsersResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/bans/{userId}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Saca al usuario de la sala y le impide volver a entrar por cualquier vía, para siempre (durationSeconds 0) o durante un tiempo. Con deleteMessagesHours borra también sus mensajes de las últimas horas (máximo 7 días). Requiere la capacidad moderate_members y solo el propietario puede banear a otros moderadores",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Banea a un usuario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del usuario",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Duración, motivo y mensajes a borrar",
+                        "name": "ban",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BanMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usuario baneado",
+                        "schema": {
+                            "$ref": "#/definitions/models.RoomSanction"
+                        }
+                    },
+                    "400": {
+                        "description": "Duración o ventana de borrado inválidas",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido banear a este usuario",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala o usuario no encontrados",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permite de nuevo al usuario entrar en la sala. No lo vuelve a añadir como miembro. Requiere la capacidad moderate_members",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Levanta el baneo de un usuario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del usuario",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Baneo levantado",
+                        "schema": {
+                            "$ref": "#/definitions/models.RoomSanction"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido moderar miembros",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada o el usuario no está baneado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/clear-reports": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Dismisses all open reports for a specific user in a chat room and clears their report score. The dismissed reports count against their reporters' track record",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Clear reports for a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Clear Report Request",
+                        "name": "clearRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ClearReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reports cleared successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/content-filter": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve la acción de la lista de palabrotas por defecto y las reglas propias de la sala. Requiere la capacidad manage_room",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Filtro de contenido de una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Filtro de la sala",
+                        "schema": {
+                            "$ref": "#/definitions/models.ContentFilterSettings"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido gestionar el filtro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reemplaza la acción de la lista de palabrotas por defecto (reject, mask, flag o vacío para desactivarla) y las reglas propias, como mucho 200. Cada regla es una palabra o frase (word, con \"*\" final para cualquier terminación), una expresión regular RE2 (regex) o un dominio de enlaces con sus subdominios (domain), con la acción reject (el mensaje no se envía), mask (se ocultan las coincidencias) o flag (se envía y se reporta para revisión). Requiere la capacidad manage_room",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Actualiza el filtro de contenido de una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nueva configuración del filtro",
+                        "name": "filter",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateContentFilterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Filtro actualizado",
+                        "schema": {
+                            "$ref": "#/definitions/models.ContentFilterSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Regla o acción inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido gestionar el filtro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/invites": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve las invitaciones no revocadas de la sala. Requiere la capacidad invite_members",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Lista las invitaciones de una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invitaciones de la sala",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Invite"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido gestionar invitaciones",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Genera un código y un enlace de invitación con expiración y número de usos opcionales. Requiere la capacidad invite_members",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Crea una invitación",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Configuración de la invitación",
+                        "name": "invite",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateInviteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Invitación creada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invite"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido gestionar invitaciones",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/invites/{code}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invalida una invitación para que no pueda volver a usarse. Requiere la capacidad invite_members",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Revoca una invitación",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Código de la invitación",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invitación revocada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido gestionar invitaciones",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala o invitación no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/join": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permite al usuario autenticado unirse a una sala pública. A las salas privadas solo se entra con una invitación",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Unirse a una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usuario unido exitosamente",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido unirse a esta sala o usuario baneado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Usuario ya es miembro de la sala",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/join-requests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve las solicitudes pendientes de la sala, de la más antigua a la más reciente. Requiere la capacidad invite_members",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Cola de solicitudes para unirse",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Solicitudes pendientes",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.JoinRequest"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido revisar solicitudes",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crea una solicitud con un mensaje opcional. Los usuarios que pueden aprobarla reciben un evento JOIN_REQUEST_RECEIVED",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Solicita unirse a una sala privada",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Mensaje para los admins",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateJoinRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Solicitud creada",
+                        "schema": {
+                            "$ref": "#/definitions/models.JoinRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida o sala pública",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Usuario baneado de la sala, o canal de un espacio del que no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Ya es miembro o ya tiene una solicitud pendiente",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/join-requests/me": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Elimina la solicitud pendiente del usuario autenticado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Cancela una solicitud para unirse",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Solicitud cancelada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Solicitud no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/join-requests/{userId}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Añade al solicitante como miembro y le envía un evento JOIN_REQUEST_REVIEWED. Requiere la capacidad invite_members",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Aprueba una solicitud para unirse",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del solicitante",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Solicitud aprobada",
+                        "schema": {
+                            "$ref": "#/definitions/models.JoinRequest"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido revisar solicitudes, o el usuario ya no es miembro del espacio del canal",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala o solicitud no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/join-requests/{userId}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rechaza la solicitud y envía al solicitante un evento JOIN_REQUEST_REVIEWED. Requiere la capacidad invite_members",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Rechaza una solicitud para unirse",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del solicitante",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Solicitud rechazada",
+                        "schema": {
+                            "$ref": "#/definitions/models.JoinRequest"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido revisar solicitudes",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala o solicitud no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/leave": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permite al usuario autenticado abandonar una sala. El propietario no puede abandonar su sala",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Abandonar una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usuario salió de la sala exitosamente",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "El propietario no puede abandonar la sala, ni los admins del espacio sus canales",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada o usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve los miembros con su nombre, foto, rol, fecha de unión, conexión y restricción por reportes, ordenados por fecha de unión. El número y el peso de los reportes de cada miembro solo se incluyen para quienes tienen manage_reports. Las salas privadas solo son visibles para sus miembros",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Directorio de miembros de una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra por rol (owner, admin, member o un rol propio)",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Número de miembros por página (máximo 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto por la página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Página de miembros",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedRoomMembersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Cursor inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "La sala es privada y el usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala o rol no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/members/{userId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Expulsa a un miembro de la sala. Requiere la capacidad remove_members y solo el propietario puede expulsar a quienes también la tienen",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Expulsar a un miembro",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del usuario a expulsar",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Miembro expulsado exitosamente",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido expulsar a este usuario",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada o usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/members/{userId}/mute": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Impide a un miembro escribir en la sala durante durationSeconds (máximo 30 días). Un nuevo silencio reemplaza al anterior. Requiere la capacidad moderate_members y solo el propietario puede silenciar a otros moderadores",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Silencia a un miembro",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del miembro",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Duración y motivo",
+                        "name": "mute",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MuteMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Miembro silenciado",
+                        "schema": {
+                            "$ref": "#/definitions/models.RoomSanction"
+                        }
+                    },
+                    "400": {
+                        "description": "Duración inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido silenciar a este usuario",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada o el usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permite de nuevo escribir a un miembro silenciado. Requiere la capacidad moderate_members",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Levanta el silencio de un miembro",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del miembro",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Silencio levantado",
+                        "schema": {
+                            "$ref": "#/definitions/models.RoomSanction"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido moderar miembros",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada o el usuario no está silenciado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/members/{userId}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cambia el rol de un miembro. Requiere la capacidad manage_roles y no se pueden otorgar capacidades que el usuario no tenga",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Asigna un rol a un miembro",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del miembro",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rol a asignar",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AssignRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rol asignado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido gestionar este rol",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala, rol o miembro no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "El usuario ya tiene ese rol",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/messages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve los mensajes de una sala específica sin paginación",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Obtiene mensajes de una sala (versión simple)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Límite de mensajes a obtener",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lista de mensajes de la sala",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MessageResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "La sala es privada y el usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/messages/paginated": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve los mensajes de una sala específica con soporte para paginación ordernada por fecha de creación descendente",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Obtiene mensajes de una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Límite de mensajes a obtener",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "\"1747441934\"",
+                        "description": "Cursor para paginación (timestamp)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Mensajes paginados de la sala",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedMessagesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "La sala es privada y el usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/messages/{messageId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "El autor puede eliminar sus propios mensajes; eliminar los de otros requiere la capacidad delete_messages",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Elimina un mensaje",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del mensaje",
+                        "name": "messageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Mensaje eliminado exitosamente",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido eliminar el mensaje",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala o mensaje no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/messages/{messageId}/thread": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve el mensaje raíz y sus respuestas, de la más antigua a la más reciente",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Obtiene un hilo de mensajes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del mensaje raíz",
+                        "name": "messageId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Límite de respuestas a obtener",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Hilo del mensaje",
+                        "schema": {
+                            "$ref": "#/definitions/models.ThreadResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "La sala es privada y el usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala o mensaje no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/moderation-actions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the latest 100 moderation actions of a room, newest first, each with the IDs of the reports it resolved. Requires manage_reports",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Moderation actions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Moderation actions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ModerationAction"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Room not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve las preferencias del usuario para la sala, o las de por defecto si no las ha configurado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Preferencias de notificación de una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preferencias de la sala",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreference"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada o el usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Silencia la sala indefinidamente (muted) o hasta una fecha (mutedUntil), activa el modo solo menciones o cambia sonido y push. Solo se actualizan los campos enviados",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Cambia las preferencias de notificación de una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preferencias a cambiar",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateNotificationPreferenceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preferencias actualizadas",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreference"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada o el usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/ownership/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "El destinatario confirma la transferencia y pasa a ser el propietario de la sala. El propietario anterior queda como admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Acepta una transferencia de propiedad",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Propiedad transferida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "No hay transferencia pendiente para el usuario",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "La transferencia expiró",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/ownership/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "El propietario propone a otro miembro como nuevo propietario. La transferencia se completa cuando el destinatario la acepta",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Inicia una transferencia de propiedad",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nuevo propietario",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferOwnershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transferencia pendiente de confirmación",
+                        "schema": {
+                            "$ref": "#/definitions/models.OwnershipTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Solo el propietario puede transferir la sala",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada o usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "El propietario cancela la transferencia pendiente o el destinatario la rechaza",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Cancela una transferencia de propiedad",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transferencia cancelada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido cancelar la transferencia",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "No hay transferencia pendiente",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/report": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reports a message as inappropriate in a chat room. The report is weighted by the reporter's account age and track record, and counts toward the sender's score for the room's report window",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Report an inappropriate message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Report Request",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message reported successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/reports": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the individual reports of a room with a given status, oldest first, with the reported message, the reporter, the reason and the resolution. Requires manage_reports",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Moderation queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "open",
+                        "description": "open, dismissed or actioned",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Reports per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reports",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedReportsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Room not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/reports/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Resolves open reports against the same user with dismiss, delete_message, warn, mute or ban. Dismissed reports count against their reporters' track record and the rest are marked as actioned; either way they stop counting toward the user's score. The action is saved with the IDs of the reports it resolved, and each report links back to it. Requires manage_reports, plus delete_messages to delete the message and moderate_members to mute or ban",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Resolve reports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reports and action",
+                        "name": "resolution",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResolveReportsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Moderation action",
+                        "schema": {
+                            "$ref": "#/definitions/models.ModerationAction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Room, report or message not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Report already resolved",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/roles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve los roles predefinidos y propios de la sala con sus capacidades. Solo para miembros",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Obtiene los roles de una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Roles de la sala",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RoomRole"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada o usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/roles/{roleName}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define las capacidades de un rol propio de la sala o redefine \"admin\" y \"member\". Requiere la capacidad manage_roles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Crea o actualiza un rol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nombre del rol",
+                        "name": "roleName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Capacidades del rol",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RoomRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rol guardado",
+                        "schema": {
+                            "$ref": "#/definitions/models.RoomRole"
+                        }
+                    },
+                    "400": {
+                        "description": "Nombre o capacidad inválidos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido gestionar este rol",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Elimina un rol propio de la sala; sus miembros pasan a ser \"member\". En \"admin\" y \"member\" restablece la definición por defecto",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Elimina un rol",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nombre del rol",
+                        "name": "roleName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rol eliminado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido gestionar este rol",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala o rol no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/sanctions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve los últimos 100 silencios y baneos de la sala, activos o no, del más reciente al más antiguo. Requiere la capacidad moderate_members",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Historial de sanciones",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sanciones de la sala",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RoomSanction"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido moderar miembros",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/rooms/{roomId}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve mensajes y remitentes activos por día, usuarios más activos, horas pico (UTC), crecimiento de miembros y reportes. Se calculan a partir de contadores diarios. Requiere la capacidad manage_room",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Estadísticas de una sala",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sala",
+                        "name": "roomId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 30,
+                        "description": "Número de días hasta hoy (máximo 90)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Estadísticas de la sala",
+                        "schema": {
+                            "$ref": "#/definitions/models.RoomStatsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido ver las estadísticas",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/spaces": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve los espacios con más miembros",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spaces"
+                ],
+                "summary": "Lista los espacios",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Número de espacios (máximo 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Espacios",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SpaceSummary"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crea un espacio que agrupa canales con miembros y admins compartidos. El creador es su propietario",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spaces"
+                ],
+                "summary": "Crea un espacio",
+                "parameters": [
+                    {
+                        "description": "Datos del espacio",
+                        "name": "space",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSpaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Espacio creado",
+                        "schema": {
+                            "$ref": "#/definitions/models.Space"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/spaces/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve los espacios a los que pertenece el usuario autenticado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spaces"
+                ],
+                "summary": "Espacios del usuario",
+                "responses": {
+                    "200": {
+                        "description": "Espacios del usuario",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SpaceSummary"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/spaces/{spaceId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve el espacio, el rol del usuario en él y los canales que puede ver: los públicos y los privados a los que tiene acceso",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spaces"
+                ],
+                "summary": "Obtiene un espacio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del espacio",
+                        "name": "spaceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Espacio y canales",
+                        "schema": {
+                            "$ref": "#/definitions/models.SpaceResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Espacio no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/spaces/{spaceId}/channels": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crea una sala dentro del espacio. Los canales públicos incluyen a todos los miembros del espacio; los privados solo a los usuarios indicados. Solo los admins del espacio pueden crear canales",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spaces"
+                ],
+                "summary": "Crea un canal en un espacio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del espacio",
+                        "name": "spaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Datos del canal",
+                        "name": "channel",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateRoomRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Canal creado",
+                        "schema": {
+                            "$ref": "#/definitions/models.Room"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "No permitido gestionar el espacio",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Espacio no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/spaces/{spaceId}/join": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Une al usuario autenticado al espacio y a todos sus canales públicos",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spaces"
+                ],
+                "summary": "Unirse a un espacio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del espacio",
+                        "name": "spaceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usuario unido al espacio",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Espacio no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "El usuario ya es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/spaces/{spaceId}/leave": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Saca al usuario autenticado del espacio y de todos sus canales. El propietario no puede abandonarlo",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spaces"
+                ],
+                "summary": "Abandonar un espacio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del espacio",
+                        "name": "spaceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usuario salió del espacio",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "El propietario no puede abandonar el espacio",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Espacio no encontrado o el usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/spaces/{spaceId}/members/{userId}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Asigna el rol \"admin\" o \"member\". Los admins del espacio son admins en todos sus canales. Solo el propietario puede cambiar roles. El cambio se anota en el registro de auditoría de cada canal",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spaces"
+                ],
+                "summary": "Cambia el rol de un miembro del espacio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del espacio",
+                        "name": "spaceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del miembro",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rol a asignar",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AssignRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rol actualizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Rol inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Solo el propietario puede cambiar roles",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Espacio no encontrado o el usuario no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chat/ws": {
+            "get": {
+                "description": "Establece una conexión WebSocket para mensajería en tiempo real",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Conexión WebSocket para chat en tiempo real",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Firebase Auth Token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols a WebSocket",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/invites/{code}": {
+            "get": {
+                "description": "Devuelve el nombre y el número de miembros de la sala de una invitación válida. No requiere autenticación",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Vista previa de una invitación",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Código de la invitación",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Vista previa de la sala",
+                        "schema": {
+                            "$ref": "#/definitions/models.InvitePreview"
+                        }
+                    },
+                    "404": {
+                        "description": "Invitación no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Invitación expirada, revocada o agotada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/invites/{code}/join": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Une al usuario autenticado a la sala de la invitación, incluidas las salas privadas",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Unirse con una invitación",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Código de la invitación",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usuario unido exitosamente",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Usuario baneado de la sala, o canal de un espacio del que no es miembro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invitación o sala no encontrada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Usuario ya es miembro de la sala",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Invitación expirada, revocada o agotada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/blocks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve los usuarios que el usuario autenticado ha bloqueado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Usuarios bloqueados",
+                "responses": {
+                    "200": {
+                        "description": "Usuarios bloqueados",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BlockedUser"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/blocks/{userId}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "El usuario bloqueado no puede abrir chats directos con el usuario actual ni enviarle mensajes directos, y sus mensajes en las salas dejan de mostrarse al usuario actual, tanto en el historial como en tiempo real",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Bloquea a un usuario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del usuario a bloquear",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usuario bloqueado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "No puedes bloquearte a ti mismo",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Usuario no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Quita a un usuario de la lista de bloqueados del usuario actual",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Desbloquea a un usuario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del usuario a desbloquear",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usuario desbloqueado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/bookmarks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve los mensajes guardados del usuario autenticado, del más reciente al más antiguo. Los mensajes eliminados o de conversaciones que el usuario abandonó se devuelven como no disponibles y sin contenido",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Obtiene los mensajes guardados",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Límite de mensajes guardados a obtener",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor para paginación",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Mensajes guardados paginados",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedBookmarksResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Guarda un mensaje de una sala o chat directo al que pertenece el usuario, con una nota privada opcional",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Guarda un mensaje",
+                "parameters": [
+                    {
+                        "description": "Mensaje a guardar",
+                        "name": "bookmark",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateBookmarkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Mensaje guardado exitosamente",
+                        "schema": {
+                            "$ref": "#/definitions/models.Bookmark"
+                        }
+                    },
+                    "400": {
+                        "description": "Solicitud inválida",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "El usuario no pertenece a la conversación",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Mensaje no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "El mensaje ya está guardado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/bookmarks/{bookmarkId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Elimina un mensaje de la lista de guardados del usuario autenticado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Elimina un mensaje guardado",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del mensaje guardado",
+                        "name": "bookmarkId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Mensaje guardado eliminado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Mensaje guardado no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/contacts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve los contactos del usuario autenticado. Sus contactos pueden abrirle chats directos sin pasar por las solicitudes de mensajes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Contactos",
+                "responses": {
+                    "200": {
+                        "description": "Contactos",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Contact"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/contacts/{userId}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Añade un usuario a los contactos del usuario autenticado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Añade un contacto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del usuario",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Contacto añadido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "No puedes añadirte a ti mismo",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Usuario no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Quita un usuario de los contactos del usuario autenticado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Quita un contacto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del usuario",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Contacto eliminado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Verifica si el usuario autenticado existe en la base de datos, si no, lo crea con los datos de autenticación",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Asegura que el usuario exista en la base de datos",
+                "responses": {
+                    "200": {
+                        "description": "Datos del usuario",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/devices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista los dispositivos con claves publicadas del usuario autenticado y cuántas claves de un solo uso les quedan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encryption"
+                ],
+                "summary": "Dispositivos del usuario",
+                "responses": {
+                    "200": {
+                        "description": "Dispositivos del usuario",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DeviceKeys"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/devices/{deviceId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Borra las claves de un dispositivo del usuario y avisa con KEYS_CHANGED a quienes comparten un chat directo con él",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encryption"
+                ],
+                "summary": "Borra un dispositivo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del dispositivo",
+                        "name": "deviceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dispositivo borrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Dispositivo no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/devices/{deviceId}/keys": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Publica o reemplaza la clave de identidad y la clave previa firmada de un dispositivo, y opcionalmente añade claves de un solo uso. Si cambia la clave de identidad se borran las claves de un solo uso anteriores. Si el dispositivo es nuevo o cambia su clave de identidad se avisa con KEYS_CHANGED a quienes comparten un chat directo con el usuario",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encryption"
+                ],
+                "summary": "Publica las claves de un dispositivo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del dispositivo, elegido por el cliente",
+                        "name": "deviceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Claves públicas del dispositivo",
+                        "name": "keys",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PublishDeviceKeysRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Claves publicadas",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeviceKeys"
+                        }
+                    },
+                    "400": {
+                        "description": "Claves inválidas o demasiados dispositivos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/devices/{deviceId}/prekeys": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Añade claves previas de un solo uso a un dispositivo ya publicado. Cada una se entrega como mucho una vez; los clientes deben reponerlas cuando remainingPreKeys baje",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encryption"
+                ],
+                "summary": "Añade claves de un solo uso",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del dispositivo",
+                        "name": "deviceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Claves de un solo uso (máximo 100)",
+                        "name": "keys",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UploadPreKeysRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dispositivo actualizado",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeviceKeys"
+                        }
+                    },
+                    "400": {
+                        "description": "Claves inválidas",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Dispositivo no encontrado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/keys/{userId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve las claves públicas de todos los dispositivos de un usuario para iniciar sesiones cifradas con ellos. Cada llamada consume una clave de un solo uso por dispositivo, si le quedan. Cada usuario puede pedir como mucho 10 paquetes de otro por hora",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encryption"
+                ],
+                "summary": "Paquete de claves de un usuario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del usuario",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paquetes de claves",
+                        "schema": {
+                            "$ref": "#/definitions/models.KeyBundleResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Uno de los dos usuarios ha bloqueado al otro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Usuario no encontrado o sin claves publicadas",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones del paquete de claves de este usuario",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Traspasa la propiedad de las salas del usuario autenticado al admin más antiguo de cada una, marca su cuenta como eliminada y deshabilita su acceso, en ese orden para que se pueda reintentar si algún paso falla",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Elimina la cuenta del usuario",
+                "responses": {
+                    "200": {
+                        "description": "Cuenta eliminada",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor. La petición se puede repetir",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/notification-preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve las preferencias de todas las conversaciones que el usuario ha configurado. Las demás usan los valores por defecto",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Preferencias de notificación del usuario",
+                "responses": {
+                    "200": {
+                        "description": "Preferencias configuradas",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NotificationPreference"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/privacy": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve quién puede abrir chats directos con el usuario autenticado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Ajustes de privacidad",
+                "responses": {
+                    "200": {
+                        "description": "Ajustes de privacidad",
+                        "schema": {
+                            "$ref": "#/definitions/models.PrivacySettings"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Elige quién puede abrir chats directos con el usuario: everyone, shared_rooms (quien comparte alguna sala o es contacto), contacts o nobody. Los chats existentes siguen siendo legibles, pero los nuevos envíos se validan con el ajuste nuevo",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Cambia los ajustes de privacidad",
+                "parameters": [
+                    {
+                        "description": "Ajustes de privacidad",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatePrivacySettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ajustes actualizados",
+                        "schema": {
+                            "$ref": "#/definitions/models.PrivacySettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Ajuste inválido",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "No autorizado",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.PayloadSignUp": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AddDirectChatParticipantRequest": {
+            "type": "object",
+            "properties": {
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AssignRoleRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actorId": {
+                    "description": "Vacío si la acción la hizo el sistema",
+                    "type": "string"
+                },
+                "after": {
+                    "description": "Estado posterior de lo que cambió",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "before": {
+                    "description": "Estado anterior de lo que cambió",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "roomId": {
+                    "type": "string"
+                },
+                "targetId": {
+                    "type": "string"
+                },
+                "targetType": {
+                    "description": "\"user\", \"message\", \"role\", \"room\" o \"invite\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BanMemberRequest": {
+            "type": "object",
+            "properties": {
+                "deleteMessagesHours": {
+                    "description": "DeleteMessagesHours deletes the user's messages from the last hours. 0 keeps them",
+                    "type": "integer"
+                },
+                "durationSeconds": {
+                    "description": "0 bans permanently",
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BannedUserResponse": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "reportCount": {
+                    "type": "integer"
+                },
+                "reportScore": {
+                    "description": "ReportScore is the sum of the weights of the reports within the room's window",
+                    "type": "number"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BannedUsersResponse": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BannedUserResponse"
+                    }
+                }
+            }
+        },
+        "models.BlockedUser": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "photoUrl": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Bookmark": {
+            "type": "object",
+            "properties": {
+                "chatId": {
+                    "description": "ID de la sala o del chat directo",
+                    "type": "string"
+                },
+                "chatType": {
+                    "description": "\"room\" o \"direct\"",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Copia del mensaje al momento de guardarlo",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    ]
+                },
+                "messageId": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BookmarkResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "false si el mensaje fue eliminado o el usuario ya no pertenece a la conversación",
+                    "type": "boolean"
+                },
+                "chatId": {
+                    "description": "ID de la sala o del chat directo",
+                    "type": "string"
+                },
+                "chatType": {
+                    "description": "\"room\" o \"direct\"",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Copia del mensaje al momento de guardarlo",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    ]
+                },
+                "messageId": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Capability": {
+            "type": "string",
+            "enum": [
+                "send_messages",
+                "delete_messages",
+                "pin_messages",
+                "invite_members",
+                "remove_members",
+                "manage_reports",
+                "manage_roles",
+                "manage_room",
+                "bypass_slow_mode",
+                "post_announcements",
+                "moderate_members"
+            ],
+            "x-enum-comments": {
+                "CapBypassSlowMode": "Escribir sin esperar el intervalo del modo lento",
+                "CapDeleteMessages": "Eliminar mensajes de otros usuarios",
+                "CapManageRoom": "Editar la configuración de la sala"
+            },
+            "x-enum-varnames": [
+                "CapSendMessages",
+                "CapDeleteMessages",
+                "CapPinMessages",
+                "CapInviteMembers",
+                "CapRemoveMembers",
+                "CapManageReports",
+                "CapManageRoles",
+                "CapManageRoom",
+                "CapBypassSlowMode",
+                "CapPostAnnouncements",
+                "CapModerateMembers"
+            ]
+        },
+        "models.ClearReportRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Contact": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "photoUrl": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ContentFilterRule": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "\"reject\", \"mask\" o \"flag\"",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "\"word\", \"regex\" o \"domain\"",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Palabra o frase, expresión regular o dominio",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ContentFilterSettings": {
+            "type": "object",
+            "properties": {
+                "defaultListAction": {
+                    "description": "DefaultListAction es la acción para la lista de palabrotas por defecto. Vacío la desactiva",
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ContentFilterRule"
+                    }
+                }
+            }
+        },
+        "models.CreateBookmarkRequest": {
+            "type": "object",
+            "properties": {
+                "chatId": {
+                    "type": "string"
+                },
+                "chatType": {
+                    "description": "\"room\" o \"direct\"",
+                    "type": "string"
+                },
+                "messageId": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateGroupDirectChatRequest": {
+            "type": "object",
+            "properties": {
+                "title": {
+                    "description": "Optional title of the group",
+                    "type": "string"
+                },
+                "userIds": {
+                    "description": "IDs of the other participants; the creator is added automatically",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CreateInviteRequest": {
+            "type": "object",
+            "properties": {
+                "expiresInHours": {
+                    "description": "0 significa que no expira",
+                    "type": "integer"
+                },
+                "maxUses": {
+                    "description": "0 significa usos ilimitados",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateJoinRequestRequest": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateRoomRequest": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "description": "Tags used to discover the room",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "isPrivate": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "userIds": {
+                    "description": "IDs of users to be added to the room",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CreateSpaceRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DailyCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeviceKeyBundle": {
+            "type": "object",
+            "properties": {
+                "deviceId": {
+                    "type": "string"
+                },
+                "identityChangedAt": {
+                    "type": "string"
+                },
+                "identityKey": {
+                    "type": "string"
+                },
+                "oneTimePreKey": {
+                    "description": "Vacía si el dispositivo se quedó sin claves de un solo uso",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.OneTimePreKey"
+                        }
+                    ]
+                },
+                "signedPreKey": {
+                    "$ref": "#/definitions/models.SignedPreKey"
+                }
+            }
+        },
+        "models.DeviceKeys": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deviceId": {
+                    "type": "string"
+                },
+                "identityChangedAt": {
+                    "description": "IdentityChangedAt es la última vez que cambió la clave de identidad, para que los demás vuelvan a verificarla",
+                    "type": "string"
+                },
+                "identityKey": {
+                    "description": "Base64",
+                    "type": "string"
+                },
+                "remainingPreKeys": {
+                    "description": "RemainingPreKeys es el número de claves de un solo uso que quedan; solo se muestra al propietario",
+                    "type": "integer"
+                },
+                "signedPreKey": {
+                    "$ref": "#/definitions/models.SignedPreKey"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DirectChat": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "description": "Archived indica si el usuario que consulta sus chats ha archivado este",
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "description": "Quien abrió el chat; en los de grupo puede quitar participantes",
+                    "type": "string"
+                },
+                "displayNames": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "encrypted": {
+                    "description": "Encrypted indica que el chat usa cifrado de extremo a extremo; una vez activado no se puede desactivar",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isDeleted": {
+                    "type": "boolean"
+                },
+                "isGroup": {
+                    "type": "boolean"
+                },
+                "lastMessage": {
+                    "$ref": "#/definitions/models.Message"
+                },
+                "notificationPreferences": {
+                    "description": "NotificationPreferences son las preferencias del usuario que consulta sus chats",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NotificationPreference"
+                        }
+                    ]
+                },
+                "pendingInvites": {
+                    "description": "PendingInvites son los participantes de un chat de grupo que deben aceptar la invitación porque quien\nlos añadió no era su contacto, con el ID de quien los añadió",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "requestStatus": {
+                    "description": "RequestStatus es el estado de la solicitud si el chat lo abrió alguien que no era contacto del otro usuario",
+                    "type": "string"
+                },
+                "requestedBy": {
+                    "description": "Usuario que envió la solicitud",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "Solo en chats de grupo",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.EncryptedEnvelope": {
+            "type": "object",
+            "properties": {
+                "ciphertext": {
+                    "description": "Base64",
+                    "type": "string"
+                },
+                "deviceId": {
+                    "type": "string"
+                },
+                "recipientId": {
+                    "type": "string"
+                },
+                "senderDeviceId": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Tipo de mensaje del protocolo, definido por los clientes",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HourCount": {
+            "type": "object",
+            "properties": {
+                "hour": {
+                    "type": "integer"
+                },
+                "messages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Invite": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "Sin fecha no expira",
+                    "type": "string"
+                },
+                "link": {
+                    "description": "Enlace para compartir, no se guarda en Firestore",
+                    "type": "string"
+                },
+                "maxUses": {
+                    "description": "0 significa usos ilimitados",
+                    "type": "integer"
+                },
+                "revoked": {
+                    "type": "boolean"
+                },
+                "roomId": {
+                    "type": "string"
+                },
+                "uses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InvitePreview": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "memberCount": {
+                    "type": "integer"
+                },
+                "roomId": {
+                    "type": "string"
+                },
+                "roomName": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JoinRequest": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "description": "Excluido de Firestore",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "reviewedAt": {
+                    "type": "string"
+                },
+                "reviewedBy": {
+                    "type": "string"
+                },
+                "roomId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KeyBundleResponse": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DeviceKeyBundle"
+                    }
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MemberGrowthPoint": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "joined": {
+                    "type": "integer"
+                },
+                "left": {
+                    "type": "integer"
+                },
+                "members": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Message": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "description": "Excluido de Firestore",
+                    "type": "string"
+                },
+                "encrypted": {
+                    "description": "Encrypted indica que el mensaje es de un chat cifrado: Content va vacío y el texto viaja en Envelopes",
+                    "type": "boolean"
+                },
+                "envelopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EncryptedEnvelope"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isDeleted": {
+                    "type": "boolean"
+                },
+                "mentions": {
+                    "description": "IDs de los usuarios mencionados",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "roomId": {
+                    "type": "string"
+                },
+                "threadId": {
+                    "description": "Mensaje raíz si es una respuesta en un hilo",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "encrypted": {
+                    "description": "Encrypted indica que el mensaje es de un chat cifrado: Content va vacío y el texto viaja en Envelopes",
+                    "type": "boolean"
+                },
+                "envelopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EncryptedEnvelope"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isDeleted": {
+                    "type": "boolean"
+                },
+                "mentions": {
+                    "description": "IDs de los usuarios mencionados",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "roomId": {
+                    "type": "string"
+                },
+                "threadId": {
+                    "description": "Mensaje raíz si es una respuesta en un hilo",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModerationAction": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedMessageIds": {
+                    "description": "DeletedMessageIDs are the messages removed by delete_message or by a ban",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reportIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "roomId": {
+                    "type": "string"
+                },
+                "sanctionId": {
+                    "description": "SanctionID is the mute or ban issued by the action",
+                    "type": "string"
+                },
+                "targetId": {
+                    "description": "ID of the reported user",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MuteMemberRequest": {
+            "type": "object",
+            "properties": {
+                "durationSeconds": {
+                    "description": "Between 1 and MaxMuteSeconds",
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NotificationPreference": {
+            "type": "object",
+            "properties": {
+                "conversationId": {
+                    "type": "string"
+                },
+                "conversationType": {
+                    "type": "string"
+                },
+                "mentionsOnly": {
+                    "type": "boolean"
+                },
+                "muted": {
+                    "type": "boolean"
+                },
+                "mutedUntil": {
+                    "description": "Vacío si se silenció sin fecha de fin",
+                    "type": "string"
+                },
+                "push": {
+                    "type": "boolean"
+                },
+                "sound": {
+                    "type": "boolean"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OneTimePreKey": {
+            "type": "object",
+            "properties": {
+                "keyId": {
+                    "type": "integer"
+                },
+                "publicKey": {
+                    "description": "Base64",
+                    "type": "string"
+                }
+            }
+        },
+        "models.OwnershipTransfer": {
+            "type": "object",
+            "properties": {
+                "fromUserId": {
+                    "type": "string"
+                },
+                "requestedAt": {
+                    "type": "string"
+                },
+                "toUserId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaginatedAuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditEntry"
+                    }
+                },
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "nextCursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaginatedBookmarksResponse": {
+            "type": "object",
+            "properties": {
+                "bookmarks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BookmarkResponse"
+                    }
+                },
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "nextCursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaginatedMessagesResponse": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MessageResponse"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaginatedReportsResponse": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Report"
+                    }
+                }
+            }
+        },
+        "models.PaginatedRoomMembersResponse": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RoomMember"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaginatedRoomsResponse": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "rooms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RoomSummary"
+                    }
+                }
+            }
+        },
+        "models.PrivacySettings": {
+            "type": "object",
+            "properties": {
+                "dmPrivacy": {
+                    "description": "everyone, shared_rooms, contacts o nobody",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PublishDeviceKeysRequest": {
+            "type": "object",
+            "properties": {
+                "identityKey": {
+                    "type": "string"
+                },
+                "oneTimePreKeys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OneTimePreKey"
+                    }
+                },
+                "signedPreKey": {
+                    "$ref": "#/definitions/models.SignedPreKey"
+                }
+            }
+        },
+        "models.Report": {
+            "type": "object",
+            "properties": {
+                "actionId": {
+                    "description": "Moderation action that resolved the report",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "messageContent": {
+                    "description": "MessageContent is a copy of the reported message, kept for review even if the message is deleted",
+                    "type": "string"
+                },
+                "messageId": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reportedId": {
+                    "description": "ID of the user being reported",
+                    "type": "string"
+                },
+                "reportedName": {
+                    "type": "string"
+                },
+                "reporterId": {
+                    "description": "ID of the user making the report, empty for system reports",
+                    "type": "string"
+                },
+                "reporterName": {
+                    "type": "string"
+                },
+                "resolvedAt": {
+                    "type": "string"
+                },
+                "resolvedBy": {
+                    "type": "string"
+                },
+                "roomId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "system": {
+                    "description": "System is true for reports filed automatically by the content filter",
+                    "type": "boolean"
+                },
+                "weight": {
+                    "description": "Weight is how much the report counts toward the reported user's score, based on the reporter",
+                    "type": "number"
+                }
+            }
+        },
+        "models.ReportRequest": {
+            "type": "object",
+            "properties": {
+                "messageId": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ResolveReportsRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "dismiss, delete_message, warn, mute or ban",
+                    "type": "string"
+                },
+                "deleteMessagesHours": {
+                    "description": "Ban only: also delete the user's recent messages",
+                    "type": "integer"
+                },
+                "durationSeconds": {
+                    "description": "Mute or ban duration. 0 bans permanently",
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reportIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Room": {
+            "type": "object",
+            "properties": {
+                "adminSince": {
+                    "description": "AdminSince guarda cuándo cada admin obtuvo el rol, para elegir al admin más antiguo como sucesor",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "admins": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "allowThreadReplies": {
+                    "description": "AllowThreadReplies permite a los demás miembros responder en hilos de una sala de solo anuncios",
+                    "type": "boolean"
+                },
+                "announcementOnly": {
+                    "description": "AnnouncementOnly limita el timeline principal a quienes tienen la capacidad post_announcements",
+                    "type": "boolean"
+                },
+                "categories": {
+                    "description": "Categories son las etiquetas con las que se descubre la sala",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "isDeleted": {
+                    "type": "boolean"
+                },
+                "isPrivate": {
+                    "type": "boolean"
+                },
+                "joinedAt": {
+                    "description": "JoinedAt guarda cuándo se unió cada miembro. Las salas anteriores no lo tienen para sus miembros iniciales",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "lastMessage": {
+                    "$ref": "#/definitions/models.Message"
+                },
+                "memberCount": {
+                    "description": "MemberCount se mantiene junto a Members para ordenar las salas sin leer sus listas de miembros",
+                    "type": "integer"
+                },
+                "memberRoles": {
+                    "description": "MemberRoles asigna a cada miembro el nombre de su rol",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notificationPreferences": {
+                    "description": "NotificationPreferences son las preferencias del usuario que consulta sus salas",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NotificationPreference"
+                        }
+                    ]
+                },
+                "ownerId": {
+                    "type": "string"
+                },
+                "ownershipTransfer": {
+                    "$ref": "#/definitions/models.OwnershipTransfer"
+                },
+                "reportThreshold": {
+                    "description": "ReportThreshold es la puntuación de reportes a partir de la cual un miembro no puede escribir. 0 usa el valor por defecto",
+                    "type": "number"
+                },
+                "reportWindowHours": {
+                    "description": "ReportWindowHours es cuántas horas cuenta cada reporte en la puntuación. 0 usa el valor por defecto",
+                    "type": "integer"
+                },
+                "roles": {
+                    "description": "Roles contiene los roles propios de la sala y las redefiniciones de \"admin\" y \"member\"",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.RoomRole"
+                    }
+                },
+                "slowModeSeconds": {
+                    "description": "SlowModeSeconds es el intervalo mínimo entre mensajes de un mismo miembro. 0 desactiva el modo lento",
+                    "type": "integer"
+                },
+                "spaceAdmins": {
+                    "description": "SpaceAdmins replica los admins del espacio, que tienen el rol de admin en todos sus canales",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "spaceId": {
+                    "description": "SpaceID es el espacio al que pertenece la sala como canal, vacío si no pertenece a ninguno",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RoomMember": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "joinedAt": {
+                    "description": "Vacío para los miembros de salas anteriores al registro de fechas",
+                    "type": "string"
+                },
+                "online": {
+                    "type": "boolean"
+                },
+                "photoUrl": {
+                    "type": "string"
+                },
+                "reportCount": {
+                    "description": "Reportes dentro de la ventana de la sala. Solo para quienes gestionan reportes",
+                    "type": "integer"
+                },
+                "reportScore": {
+                    "description": "Suma de los pesos de esos reportes. Solo para quienes gestionan reportes",
+                    "type": "number"
+                },
+                "restricted": {
+                    "description": "Alcanzó el límite de reportes y no puede escribir en la sala",
+                    "type": "boolean"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RoomRole": {
+            "type": "object",
+            "properties": {
+                "capabilities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Capability"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RoomRoleRequest": {
+            "type": "object",
+            "properties": {
+                "capabilities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Capability"
+                    }
+                }
+            }
+        },
+        "models.RoomSanction": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Active es false cuando la sanción caducó o un moderador la levantó",
+                    "type": "boolean"
+                },
+                "actorId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "Vacío si el baneo es permanente",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "liftedAt": {
+                    "type": "string"
+                },
+                "liftedBy": {
+                    "description": "Vacío si caducó",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "roomId": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "\"mute\" o \"ban\"",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RoomStatsResponse": {
+            "type": "object",
+            "properties": {
+                "activeSendersPerDay": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DailyCount"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "generatedAt": {
+                    "type": "string"
+                },
+                "memberGrowth": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MemberGrowthPoint"
+                    }
+                },
+                "messagesPerDay": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DailyCount"
+                    }
+                },
+                "peakHours": {
+                    "description": "Ordenadas de más a menos mensajes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HourCount"
+                    }
+                },
+                "reportsPerDay": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DailyCount"
+                    }
                 },
-                "email": {
+                "roomId": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.BannedUserResponse": {
-            "type": "object",
-            "properties": {
-                "displayName": {
+                "to": {
                     "type": "string"
                 },
-                "reportCount": {
-                    "type": "integer"
+                "topPosters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TopPoster"
+                    }
                 },
-                "userId": {
-                    "type": "string"
+                "totalReports": {
+                    "description": "Reportes pendientes de la sala en la colección reports",
+                    "type": "integer"
                 }
             }
         },
-        "models.BannedUsersResponse": {
+        "models.RoomSummary": {
             "type": "object",
             "properties": {
-                "users": {
+                "categories": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.BannedUserResponse"
+                        "type": "string"
                     }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "memberCount": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
                 }
             }
         },
-        "models.ClearReportRequest": {
+        "models.SignedPreKey": {
             "type": "object",
             "properties": {
-                "userId": {
+                "keyId": {
+                    "type": "integer"
+                },
+                "publicKey": {
+                    "description": "Base64",
+                    "type": "string"
+                },
+                "signature": {
+                    "description": "Base64",
                     "type": "string"
                 }
             }
         },
-        "models.CreateRoomRequest": {
+        "models.Space": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "isPrivate": {
-                    "type": "boolean"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "userIds": {
-                    "description": "IDs of users to be added to the room",
+                "admins": {
+                    "description": "Incluye al propietario",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
-                }
-            }
-        },
-        "models.DirectChat": {
-            "type": "object",
-            "properties": {
-                "createdAt": {
-                    "type": "string"
                 },
-                "displayNames": {
+                "channels": {
+                    "description": "IDs de las salas del espacio",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "imageUrl": {
+                    "type": "string"
+                },
                 "isDeleted": {
                     "type": "boolean"
                 },
-                "lastMessage": {
-                    "$ref": "#/definitions/models.Message"
-                },
-                "updatedAt": {
-                    "type": "string"
+                "memberCount": {
+                    "type": "integer"
                 },
-                "userIds": {
+                "members": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
                 }
             }
         },
-        "models.Message": {
+        "models.SpaceResponse": {
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string"
+                "admins": {
+                    "description": "Incluye al propietario",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "channelRooms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RoomSummary"
+                    }
+                },
+                "channels": {
+                    "description": "IDs de las salas del espacio",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "createdAt": {
                     "type": "string"
                 },
-                "displayName": {
-                    "description": "Excluido de Firestore",
+                "description": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
+                "imageUrl": {
+                    "type": "string"
+                },
                 "isDeleted": {
                     "type": "boolean"
                 },
-                "roomId": {
+                "memberCount": {
+                    "type": "integer"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
                     "type": "string"
                 },
-                "updatedAt": {
+                "ownerId": {
                     "type": "string"
                 },
-                "userId": {
+                "role": {
+                    "description": "Rol del usuario en el espacio, vacío si no es miembro",
+                    "type": "string"
+                },
+                "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "models.MessageResponse": {
+        "models.SpaceSummary": {
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string"
+                "channelCount": {
+                    "type": "integer"
                 },
                 "createdAt": {
                     "type": "string"
                 },
-                "displayName": {
+                "description": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "isDeleted": {
-                    "type": "boolean"
-                },
-                "roomId": {
+                "imageUrl": {
                     "type": "string"
                 },
-                "updatedAt": {
+                "memberCount": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
                 },
-                "userId": {
+                "ownerId": {
                     "type": "string"
                 }
             }
         },
-        "models.PaginatedMessagesResponse": {
+        "models.ThreadResponse": {
             "type": "object",
             "properties": {
-                "hasMore": {
-                    "type": "boolean"
-                },
-                "messages": {
+                "replies": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.MessageResponse"
+                        "$ref": "#/definitions/models.Message"
                     }
                 },
-                "nextCursor": {
-                    "type": "string"
+                "root": {
+                    "$ref": "#/definitions/models.Message"
                 }
             }
         },
-        "models.ReportRequest": {
+        "models.TopPoster": {
             "type": "object",
             "properties": {
-                "messageId": {
+                "displayName": {
                     "type": "string"
                 },
-                "reason": {
+                "messages": {
+                    "type": "integer"
+                },
+                "userId": {
                     "type": "string"
                 }
             }
         },
-        "models.Room": {
+        "models.TransferOwnershipRequest": {
             "type": "object",
             "properties": {
-                "admins": {
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateContentFilterRequest": {
+            "type": "object",
+            "properties": {
+                "defaultListAction": {
+                    "description": "\"reject\", \"mask\", \"flag\" or empty to disable the default list",
+                    "type": "string"
+                },
+                "rules": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.ContentFilterRule"
                     }
-                },
-                "createdAt": {
-                    "type": "string"
-                },
-                "description": {
+                }
+            }
+        },
+        "models.UpdateDirectChatRequest": {
+            "type": "object",
+            "properties": {
+                "title": {
+                    "description": "An empty title removes it",
                     "type": "string"
+                }
+            }
+        },
+        "models.UpdateNotificationPreferenceRequest": {
+            "type": "object",
+            "properties": {
+                "mentionsOnly": {
+                    "type": "boolean"
                 },
-                "id": {
-                    "type": "string"
+                "muted": {
+                    "type": "boolean"
                 },
-                "imageUrl": {
+                "mutedUntil": {
+                    "description": "Silencia hasta esa fecha; implica muted",
                     "type": "string"
                 },
-                "isDeleted": {
+                "push": {
                     "type": "boolean"
                 },
-                "isPrivate": {
+                "sound": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.UpdatePrivacySettingsRequest": {
+            "type": "object",
+            "properties": {
+                "dmPrivacy": {
+                    "description": "everyone, shared_rooms, contacts or nobody",
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateRoomRequest": {
+            "type": "object",
+            "properties": {
+                "allowThreadReplies": {
                     "type": "boolean"
                 },
-                "lastMessage": {
-                    "$ref": "#/definitions/models.Message"
+                "announcementOnly": {
+                    "type": "boolean"
                 },
-                "members": {
+                "categories": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "name": {
+                "description": {
                     "type": "string"
                 },
-                "ownerId": {
+                "imageUrl": {
                     "type": "string"
                 },
-                "reportedUsers": {
-                    "description": "Map of userID to report count",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
+                "isPrivate": {
+                    "type": "boolean"
                 },
-                "updatedAt": {
+                "name": {
                     "type": "string"
+                },
+                "reportThreshold": {
+                    "description": "ReportThreshold entre MinReportThreshold y MaxReportThreshold",
+                    "type": "number"
+                },
+                "reportWindowHours": {
+                    "description": "ReportWindowHours entre 1 y MaxReportWindowHours",
+                    "type": "integer"
+                },
+                "slowModeSeconds": {
+                    "description": "SlowModeSeconds entre 0 (desactivado) y MaxSlowModeSeconds",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UploadPreKeysRequest": {
+            "type": "object",
+            "properties": {
+                "oneTimePreKeys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OneTimePreKey"
+                    }
                 }
             }
         },
         "models.User": {
             "type": "object",
             "properties": {
+                "blockedUsers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "contacts": {
+                    "description": "Usuarios que pueden abrirle chats directos sin solicitud",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "createdAt": {
-                    "description": "BlockedUsers []string  ` + "`" + `json:\"blockedUsers\" firestore:\"blockedUsers\"` + "`" + `",
                     "type": "string"
                 },
                 "displayName": {
                     "type": "string"
                 },
+                "dmPrivacy": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
@@ -14,6 +14,7 @@ require (
 	github.com/swaggo/swag v1.16.4
 	go.uber.org/fx v1.23.0
 	google.golang.org/api v0.215.0
+	google.golang.org/grpc v1.67.3
 )
 
 require (
@@ -75,7 +76,6 @@ require (
 	google.golang.org/genproto v0.0.0-20241118233622-e639e219e697 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20241209162323-e6fa225c2576 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20241223144023-3abc09e42ca8 // indirect
-	google.golang.org/grpc v1.67.3 // indirect
 	google.golang.org/protobuf v1.36.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +15,10 @@ type Config struct {
 	FirebaseCredFile string
 	Environment      string
 	ServerURL        string
+	PlatformAdminIDs []string // UIDs de los administradores de la plataforma
+	// DirectChatFilterAction es la acción de la lista de palabrotas por defecto en los chats directos:
+	// "reject", "mask" o vacío para no filtrarlos
+	DirectChatFilterAction string
 }
 
 // NewConfig crea una nueva instancia de Config
@@ -28,6 +33,9 @@ func NewConfig() *Config {
 		FirebaseCredFile: getEnv("FIREBASE_CREDENTIALS", "./firebase-credentials.json"),
 		Environment:      getEnv("ENVIRONMENT", "development"),
 		ServerURL:        getEnv("SERVER_URL", "http://localhost:8080"),
+		PlatformAdminIDs: getEnvList("PLATFORM_ADMIN_IDS"),
+		// Los chats directos no tienen moderadores que revisen mensajes marcados, así que no admiten "flag"
+		DirectChatFilterAction: getEnv("DIRECT_CHAT_FILTER_ACTION", "mask"),
 	}
 }
 
@@ -39,6 +47,27 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvList obtiene una variable de entorno con valores separados por comas
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// IsPlatformAdmin comprueba si un usuario es administrador de la plataforma
+func (c *Config) IsPlatformAdmin(userID string) bool {
+	for _, adminID := range c.PlatformAdminIDs {
+		if adminID == userID {
+			return true
+		}
+	}
+	return false
+}
+
 // GetFirebaseCredentialsPath devuelve la ruta absoluta al archivo de credenciales de Firebase
 func (c *Config) GetFirebaseCredentialsPath() string {
 	if filepath.IsAbs(c.FirebaseCredFile) {
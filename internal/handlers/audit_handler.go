@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// AuditHandler maneja las consultas del registro de auditoría
+type AuditHandler struct {
+	AuditService *services.AuditService
+}
+
+// NewAuditHandler crea una nueva instancia de AuditHandler
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		AuditService: auditService,
+	}
+}
+
+// GetRoomAuditLog devuelve el registro de auditoría de una sala
+//
+//	@Summary		Registro de auditoría de una sala
+//	@Description	Devuelve las acciones de moderación y administración de la sala, de la más reciente a la más antigua, con su autor, objetivo, estado anterior y posterior y motivo. Requiere la capacidad manage_room
+//	@Tags			Audit
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId		path		string								true	"ID de la sala"
+//	@Param			actorId		query		string								false	"Filtra por autor"
+//	@Param			targetId	query		string								false	"Filtra por objetivo"
+//	@Param			action		query		string								false	"Filtra por acción"
+//	@Param			from		query		string								false	"Desde esta fecha (RFC3339, incluida)"
+//	@Param			to			query		string								false	"Hasta esta fecha (RFC3339, excluida)"
+//	@Param			limit		query		int									false	"Entradas por página (máximo 200)"	default(50)
+//	@Param			cursor		query		string								false	"Cursor de la página anterior"
+//	@Success		200			{object}	models.PaginatedAuditLogResponse	"Entradas del registro"
+//	@Failure		400			{string}	string								"Filtros inválidos"
+//	@Failure		401			{string}	string								"No autorizado"
+//	@Failure		403			{string}	string								"No permitido ver el registro"
+//	@Failure		404			{string}	string								"Sala no encontrada"
+//	@Failure		500			{string}	string								"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/audit-log [get]
+func (h *AuditHandler) GetRoomAuditLog(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	query, err := parseAuditLogQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.AuditService.GetRoomAuditLog(roomID, userID, query)
+	if err != nil {
+		writeAuditError(w, "Error getting audit log: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(page)
+}
+
+// GetAuditLog devuelve el registro de auditoría de toda la plataforma
+//
+//	@Summary		Registro de auditoría completo
+//	@Description	Devuelve las acciones de moderación y administración de todas las salas, de la más reciente a la más antigua. Solo para administradores de la plataforma (PLATFORM_ADMIN_IDS)
+//	@Tags			Audit
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId		query		string								false	"Filtra por sala"
+//	@Param			actorId		query		string								false	"Filtra por autor"
+//	@Param			targetId	query		string								false	"Filtra por objetivo"
+//	@Param			action		query		string								false	"Filtra por acción"
+//	@Param			from		query		string								false	"Desde esta fecha (RFC3339, incluida)"
+//	@Param			to			query		string								false	"Hasta esta fecha (RFC3339, excluida)"
+//	@Param			limit		query		int									false	"Entradas por página (máximo 200)"	default(50)
+//	@Param			cursor		query		string								false	"Cursor de la página anterior"
+//	@Success		200			{object}	models.PaginatedAuditLogResponse	"Entradas del registro"
+//	@Failure		400			{string}	string								"Filtros inválidos"
+//	@Failure		401			{string}	string								"No autorizado"
+//	@Failure		403			{string}	string								"No es administrador de la plataforma"
+//	@Failure		500			{string}	string								"Error interno del servidor"
+//	@Router			/admin/audit-log [get]
+func (h *AuditHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	query, err := parseAuditLogQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query.RoomID = r.URL.Query().Get("roomId")
+
+	page, err := h.AuditService.GetAuditLog(userID, query)
+	if err != nil {
+		writeAuditError(w, "Error getting audit log: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(page)
+}
+
+// ExportAuditLog exporta el registro de auditoría de toda la plataforma
+//
+//	@Summary		Exporta el registro de auditoría
+//	@Description	Descarga de una vez hasta 10000 entradas que cumplen los filtros, en CSV o JSON. En CSV los estados anterior y posterior van como JSON. Solo para administradores de la plataforma (PLATFORM_ADMIN_IDS)
+//	@Tags			Audit
+//	@Produce		json
+//	@Produce		text/csv
+//	@Security		BearerAuth
+//	@Param			format		query		string				false	"csv o json"	default(csv)
+//	@Param			roomId		query		string				false	"Filtra por sala"
+//	@Param			actorId		query		string				false	"Filtra por autor"
+//	@Param			targetId	query		string				false	"Filtra por objetivo"
+//	@Param			action		query		string				false	"Filtra por acción"
+//	@Param			from		query		string				false	"Desde esta fecha (RFC3339, incluida)"
+//	@Param			to			query		string				false	"Hasta esta fecha (RFC3339, excluida)"
+//	@Param			limit		query		int					false	"Máximo de entradas (hasta 10000)"	default(10000)
+//	@Success		200			{array}		models.AuditEntry	"Entradas del registro"
+//	@Failure		400			{string}	string				"Filtros o formato inválidos"
+//	@Failure		401			{string}	string				"No autorizado"
+//	@Failure		403			{string}	string				"No es administrador de la plataforma"
+//	@Failure		500			{string}	string				"Error interno del servidor"
+//	@Router			/admin/audit-log/export [get]
+func (h *AuditHandler) ExportAuditLog(w http.ResponseWriter, r *http.Request) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		http.Error(w, "Invalid export format", http.StatusBadRequest)
+		return
+	}
+
+	query, err := parseAuditLogQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query.RoomID = r.URL.Query().Get("roomId")
+	query.Cursor = ""
+
+	entries, err := h.AuditService.ExportAuditLog(userID, query)
+	if err != nil {
+		writeAuditError(w, "Error exporting audit log: ", err)
+		return
+	}
+
+	filename := fmt.Sprintf("audit-log-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entries)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "createdAt", "roomId", "action", "actorId", "targetType", "targetId", "before", "after", "reason"})
+	for _, entry := range entries {
+		before, _ := json.Marshal(entry.Before)
+		after, _ := json.Marshal(entry.After)
+		writer.Write([]string{
+			entry.ID,
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			entry.RoomID,
+			entry.Action,
+			entry.ActorID,
+			entry.TargetType,
+			entry.TargetID,
+			string(before),
+			string(after),
+			entry.Reason,
+		})
+	}
+	writer.Flush()
+}
+
+// parseAuditLogQuery lee los filtros comunes del registro de auditoría de la URL
+func parseAuditLogQuery(r *http.Request) (models.AuditLogQuery, error) {
+	params := r.URL.Query()
+	query := models.AuditLogQuery{
+		ActorID:  params.Get("actorId"),
+		TargetID: params.Get("targetId"),
+		Action:   params.Get("action"),
+		Cursor:   params.Get("cursor"),
+	}
+
+	if from := params.Get("from"); from != "" {
+		parsed, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return query, fmt.Errorf("invalid from date")
+		}
+		query.From = parsed
+	}
+	if to := params.Get("to"); to != "" {
+		parsed, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return query, fmt.Errorf("invalid to date")
+		}
+		query.To = parsed
+	}
+
+	if limitStr := params.Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil {
+			query.Limit = parsedLimit
+		}
+	}
+
+	return query, nil
+}
+
+// writeAuditError traduce los errores del registro de auditoría a su código HTTP
+func writeAuditError(w http.ResponseWriter, prefix string, err error) {
+	switch err.Error() {
+	case "room not found":
+		http.Error(w, prefix+err.Error(), http.StatusNotFound)
+	case "user is not allowed to view the audit log", "user is not a platform admin":
+		http.Error(w, prefix+err.Error(), http.StatusForbidden)
+	case "invalid date range":
+		http.Error(w, prefix+err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
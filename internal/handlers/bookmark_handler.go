@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// BookmarkHandler maneja las peticiones relacionadas con los mensajes guardados
+type BookmarkHandler struct {
+	BookmarkService *services.BookmarkService
+}
+
+// NewBookmarkHandler crea una nueva instancia de BookmarkHandler
+func NewBookmarkHandler(bookmarkService *services.BookmarkService) *BookmarkHandler {
+	return &BookmarkHandler{
+		BookmarkService: bookmarkService,
+	}
+}
+
+// AddBookmark guarda un mensaje para el usuario actual
+//
+//	@Summary		Guarda un mensaje
+//	@Description	Guarda un mensaje de una sala o chat directo al que pertenece el usuario, con una nota privada opcional
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			bookmark	body		models.CreateBookmarkRequest	true	"Mensaje a guardar"
+//	@Success		201			{object}	models.Bookmark					"Mensaje guardado exitosamente"
+//	@Failure		400			{string}	string							"Solicitud inválida"
+//	@Failure		401			{string}	string							"No autorizado"
+//	@Failure		403			{string}	string							"El usuario no pertenece a la conversación"
+//	@Failure		404			{string}	string							"Mensaje no encontrado"
+//	@Failure		409			{string}	string							"El mensaje ya está guardado"
+//	@Failure		500			{string}	string							"Error interno del servidor"
+//	@Router			/user/bookmarks [post]
+func (h *BookmarkHandler) AddBookmark(w http.ResponseWriter, r *http.Request) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req models.CreateBookmarkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if req.MessageID == "" || req.ChatID == "" {
+		http.Error(w, "Message ID and chat ID are required", http.StatusBadRequest)
+		return
+	}
+
+	if req.ChatType != models.BookmarkChatTypeRoom && req.ChatType != models.BookmarkChatTypeDirect {
+		http.Error(w, "Chat type must be 'room' or 'direct'", http.StatusBadRequest)
+		return
+	}
+
+	bookmark, err := h.BookmarkService.AddBookmark(userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "user does not belong to this conversation":
+			http.Error(w, "Error adding bookmark: "+err.Error(), http.StatusForbidden)
+		case "message not found":
+			http.Error(w, "Error adding bookmark: "+err.Error(), http.StatusNotFound)
+		case "message is already bookmarked":
+			http.Error(w, "Error adding bookmark: "+err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Error adding bookmark: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(bookmark)
+}
+
+// RemoveBookmark elimina un mensaje guardado del usuario actual
+//
+//	@Summary		Elimina un mensaje guardado
+//	@Description	Elimina un mensaje de la lista de guardados del usuario autenticado
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			bookmarkId	path		string	true	"ID del mensaje guardado"
+//	@Success		200			{string}	string	"Mensaje guardado eliminado"
+//	@Failure		401			{string}	string	"No autorizado"
+//	@Failure		404			{string}	string	"Mensaje guardado no encontrado"
+//	@Failure		500			{string}	string	"Error interno del servidor"
+//	@Router			/user/bookmarks/{bookmarkId} [delete]
+func (h *BookmarkHandler) RemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	bookmarkID := chi.URLParam(r, "bookmarkId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.BookmarkService.RemoveBookmark(userID, bookmarkID); err != nil {
+		if err.Error() == "bookmark not found" {
+			http.Error(w, "Error removing bookmark: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error removing bookmark: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Bookmark removed successfully",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetBookmarks obtiene los mensajes guardados del usuario actual con paginación
+//
+//	@Summary		Obtiene los mensajes guardados
+//	@Description	Devuelve los mensajes guardados del usuario autenticado, del más reciente al más antiguo. Los mensajes eliminados o de conversaciones que el usuario abandonó se devuelven como no disponibles y sin contenido
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			limit	query		int									false	"Límite de mensajes guardados a obtener"	default(20)
+//	@Param			cursor	query		string								false	"Cursor para paginación"
+//	@Success		200		{object}	models.PaginatedBookmarksResponse	"Mensajes guardados paginados"
+//	@Failure		401		{string}	string								"No autorizado"
+//	@Failure		500		{string}	string								"Error interno del servidor"
+//	@Router			/user/bookmarks [get]
+func (h *BookmarkHandler) GetBookmarks(w http.ResponseWriter, r *http.Request) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	limitStr := r.URL.Query().Get("limit")
+	limit := 20 // valor por defecto
+
+	if limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	cursor := r.URL.Query().Get("cursor")
+
+	response, err := h.BookmarkService.GetUserBookmarks(userID, limit, cursor)
+	if err != nil {
+		http.Error(w, "Error getting bookmarks: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -6,28 +6,38 @@ import (
 	"strconv"
 
 	"github.com/Parchat/backend/internal/models"
+	pws "github.com/Parchat/backend/internal/pkg/websocket"
 	"github.com/Parchat/backend/internal/services"
 	"github.com/go-chi/chi/v5"
 )
 
 // ChatHandler maneja las peticiones relacionadas con chats
 type ChatHandler struct {
-	RoomService       *services.RoomService
-	DirectChatService *services.DirectChatService
+	RoomService         *services.RoomService
+	DirectChatService   *services.DirectChatService
+	NotificationService *services.NotificationService
+	Hub                 *pws.Hub
 }
 
 // NewChatHandler crea una nueva instancia de ChatHandler
-func NewChatHandler(roomService *services.RoomService, directChatService *services.DirectChatService) *ChatHandler {
+func NewChatHandler(
+	roomService *services.RoomService,
+	directChatService *services.DirectChatService,
+	notificationService *services.NotificationService,
+	hub *pws.Hub,
+) *ChatHandler {
 	return &ChatHandler{
-		RoomService:       roomService,
-		DirectChatService: directChatService,
+		RoomService:         roomService,
+		DirectChatService:   directChatService,
+		NotificationService: notificationService,
+		Hub:                 hub,
 	}
 }
 
 // CreateRoom crea una nueva sala de chat
 //
 //	@Summary		Crea una nueva sala de chat
-//	@Description	Crea una nueva sala de chat con el usuario actual como propietario
+//	@Description	Crea una nueva sala de chat con el usuario actual como propietario y único miembro. Los demás usuarios entran uniéndose, con una invitación o con una solicitud aprobada
 //	@Tags			Chat
 //	@Accept			json
 //	@Produce		json
@@ -52,11 +62,26 @@ func (h *ChatHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Asignar el creador como propietario
+	// Asignar el creador como propietario. Los canales de un espacio se crean desde el espacio
 	room.OwnerID = userID
+	room.SpaceID = ""
+	room.SpaceAdmins = nil
+
+	// El creador es el único miembro inicial: los demás entran por su cuenta (uniéndose, con una invitación
+	// o con una solicitud aprobada), así nadie puede meter a otro en una sala para pasar por su contacto
+	room.ID = ""
+	room.Members = nil
+	room.Admins = nil
+	room.IsDeleted = false
+	room.LastMessage = nil
 
 	if err := h.RoomService.CreateRoom(&room); err != nil {
-		http.Error(w, "Error creating room: "+err.Error(), http.StatusInternalServerError)
+		switch err.Error() {
+		case "category is too long", "too many categories", "invalid report threshold", "invalid report window", "invalid slow mode interval":
+			http.Error(w, "Error creating room: "+err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Error creating room: "+err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -67,7 +92,7 @@ func (h *ChatHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 // GetRoom obtiene una sala de chat por ID
 //
 //	@Summary		Obtiene una sala por ID
-//	@Description	Devuelve los detalles de una sala específica
+//	@Description	Devuelve los detalles de una sala específica. Las salas privadas solo son visibles para sus miembros, y los usuarios baneados no pueden verla
 //	@Tags			Chat
 //	@Accept			json
 //	@Produce		json
@@ -75,14 +100,36 @@ func (h *ChatHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 //	@Param			roomId	path		string		true	"ID de la sala"
 //	@Success		200		{object}	models.Room	"Detalles de la sala"
 //	@Failure		401		{string}	string		"No autorizado"
+//	@Failure		403		{string}	string		"Sala privada de la que el usuario no es miembro, o usuario baneado"
 //	@Failure		404		{string}	string		"Sala no encontrada"
 //	@Failure		500		{string}	string		"Error interno del servidor"
 //	@Router			/chat/rooms/{roomId} [get]
 func (h *ChatHandler) GetRoom(w http.ResponseWriter, r *http.Request) {
 	roomID := chi.URLParam(r, "roomId")
 
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	// Los miembros y roles de una sala privada solo los ven sus miembros
+	if err := h.RoomService.CanViewRoom(roomID, userID); err != nil {
+		if err.Error() == "room not found" {
+			http.Error(w, "Error getting room: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error getting room: "+err.Error(), http.StatusForbidden)
+		return
+	}
+
 	room, err := h.RoomService.GetRoom(roomID)
 	if err != nil {
+		if err.Error() == "room not found" {
+			http.Error(w, "Error getting room: "+err.Error(), http.StatusNotFound)
+			return
+		}
 		http.Error(w, "Error getting room: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -90,10 +137,118 @@ func (h *ChatHandler) GetRoom(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(room)
 }
 
+// UpdateRoom actualiza la configuración de una sala de chat
+//
+//	@Summary		Actualiza una sala
+//	@Description	Actualiza el nombre, descripción, imagen, privacidad, categorías, modo lento, umbral de reportes (reportThreshold) o ventana de reportes (reportWindowHours) de una sala. Requiere la capacidad manage_room
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string						true	"ID de la sala"
+//	@Param			room	body		models.UpdateRoomRequest	true	"Campos a actualizar"
+//	@Success		200		{object}	models.Room					"Sala actualizada"
+//	@Failure		400		{string}	string						"Solicitud inválida"
+//	@Failure		401		{string}	string						"No autorizado"
+//	@Failure		403		{string}	string						"No permitido actualizar la sala"
+//	@Failure		404		{string}	string						"Sala no encontrada"
+//	@Failure		500		{string}	string						"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId} [put]
+func (h *ChatHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req models.UpdateRoomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if req.Name != nil && *req.Name == "" {
+		http.Error(w, "Room name cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	room, err := h.RoomService.UpdateRoom(roomID, userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "room not found":
+			http.Error(w, "Error updating room: "+err.Error(), http.StatusNotFound)
+		case "user is not allowed to update the room":
+			http.Error(w, "Error updating room: "+err.Error(), http.StatusForbidden)
+		case "category is too long", "too many categories", "invalid slow mode interval",
+			"invalid report threshold", "invalid report window":
+			http.Error(w, "Error updating room: "+err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Error updating room: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Notificar a los clientes conectados a la sala
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeRoomUpdated, room)
+
+	json.NewEncoder(w).Encode(room)
+}
+
+// DeleteRoom elimina una sala de chat
+//
+//	@Summary		Elimina una sala
+//	@Description	Marca una sala como eliminada. Solo el propietario puede hacerlo
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string	true	"ID de la sala"
+//	@Success		200		{string}	string	"Sala eliminada exitosamente"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		403		{string}	string	"Solo el propietario puede eliminar la sala"
+//	@Failure		404		{string}	string	"Sala no encontrada"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId} [delete]
+func (h *ChatHandler) DeleteRoom(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.RoomService.DeleteRoom(roomID, userID); err != nil {
+		switch err.Error() {
+		case "room not found":
+			http.Error(w, "Error deleting room: "+err.Error(), http.StatusNotFound)
+		case "only the room owner can delete the room":
+			http.Error(w, "Error deleting room: "+err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, "Error deleting room: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Notificar a los clientes conectados a la sala
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeRoomDeleted, map[string]string{"roomId": roomID})
+
+	response := map[string]string{
+		"message": "Room deleted successfully",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
 // GetUserRooms obtiene todas las salas a las que pertenece un usuario
 //
 //	@Summary		Obtiene las salas del usuario
-//	@Description	Devuelve todas las salas a las que pertenece el usuario autenticado
+//	@Description	Devuelve todas las salas a las que pertenece el usuario autenticado, con sus preferencias de notificación
 //	@Tags			Chat
 //	@Accept			json
 //	@Produce		json
@@ -116,6 +271,12 @@ func (h *ChatHandler) GetUserRooms(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Incluir las preferencias de notificación del usuario en cada sala
+	if err := h.NotificationService.AttachRoomPreferences(userID, rooms); err != nil {
+		http.Error(w, "Error getting rooms: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	json.NewEncoder(w).Encode(rooms)
 }
 
@@ -132,12 +293,30 @@ func (h *ChatHandler) GetUserRooms(w http.ResponseWriter, r *http.Request) {
 //	@Param			cursor	query		string								false	"Cursor para paginación (timestamp)"	default("1747441934")
 //	@Success		200		{object}	models.PaginatedMessagesResponse	"Mensajes paginados de la sala"
 //	@Failure		401		{string}	string								"No autorizado"
+//	@Failure		403		{string}	string								"La sala es privada y el usuario no es miembro"
 //	@Failure		404		{string}	string								"Sala no encontrada"
 //	@Failure		500		{string}	string								"Error interno del servidor"
 //	@Router			/chat/rooms/{roomId}/messages/paginated [get]
 func (h *ChatHandler) GetRoomMessages(w http.ResponseWriter, r *http.Request) {
 	roomID := chi.URLParam(r, "roomId")
 
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	// Los mensajes de las salas privadas solo son visibles para sus miembros
+	if err := h.RoomService.CanViewRoom(roomID, userID); err != nil {
+		if err.Error() == "room not found" {
+			http.Error(w, "Error getting messages: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error getting messages: "+err.Error(), http.StatusForbidden)
+		return
+	}
+
 	limitStr := r.URL.Query().Get("limit")
 	limit := 50 // valor por defecto
 
@@ -150,7 +329,7 @@ func (h *ChatHandler) GetRoomMessages(w http.ResponseWriter, r *http.Request) {
 
 	cursor := r.URL.Query().Get("cursor")
 
-	messages, nextCursor, err := h.RoomService.GetRoomMessages(roomID, limit, cursor)
+	messages, nextCursor, err := h.RoomService.GetRoomMessages(roomID, userID, limit, cursor)
 	if err != nil {
 		http.Error(w, "Error getting messages: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -168,7 +347,7 @@ func (h *ChatHandler) GetRoomMessages(w http.ResponseWriter, r *http.Request) {
 // CreateDirectChat crea o encuentra un chat directo entre dos usuarios
 //
 //	@Summary		Crea un chat directo
-//	@Description	Crea o encuentra un chat directo entre el usuario autenticado y otro usuario
+//	@Description	Crea o encuentra un chat directo entre el usuario autenticado y otro usuario. Si el usuario autenticado no es contacto del otro (no está en su lista de contactos ni comparten sala), el chat nuevo llega como solicitud de mensajes
 //	@Tags			Chat
 //	@Accept			json
 //	@Produce		json
@@ -177,6 +356,8 @@ func (h *ChatHandler) GetRoomMessages(w http.ResponseWriter, r *http.Request) {
 //	@Success		201			{object}	models.DirectChat	"Chat directo creado o encontrado"
 //	@Failure		400			{string}	string				"Solicitud inválida"
 //	@Failure		401			{string}	string				"No autorizado"
+//	@Failure		403			{string}	string				"Uno de los dos usuarios bloqueó al otro o la privacidad del otro usuario no lo permite"
+//	@Failure		404			{string}	string				"Usuario no encontrado"
 //	@Failure		500			{string}	string				"Error interno del servidor"
 //	@Router			/chat/direct/{otherUserId} [post]
 func (h *ChatHandler) CreateDirectChat(w http.ResponseWriter, r *http.Request) {
@@ -194,12 +375,24 @@ func (h *ChatHandler) CreateDirectChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	chat, err := h.DirectChatService.FindOrCreateDirectChat(userID, otherUserID)
+	chat, created, err := h.DirectChatService.FindOrCreateDirectChat(userID, otherUserID)
 	if err != nil {
-		http.Error(w, "Error creating direct chat: "+err.Error(), http.StatusInternalServerError)
+		switch err.Error() {
+		case "user is blocked", "user does not accept direct messages":
+			http.Error(w, "Error creating direct chat: "+err.Error(), http.StatusForbidden)
+		case "user not found":
+			http.Error(w, "Error creating direct chat: "+err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "Error creating direct chat: "+err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 
+	// Avisar al destinatario de que tiene una nueva solicitud de mensajes
+	if created && chat.RequestStatus == models.MessageRequestPending {
+		h.Hub.SendToUsers([]string{otherUserID}, pws.MessageTypeMessageRequestReceived, chat)
+	}
+
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(chat)
 }
@@ -207,14 +400,15 @@ func (h *ChatHandler) CreateDirectChat(w http.ResponseWriter, r *http.Request) {
 // GetUserDirectChats obtiene todos los chats directos del usuario
 //
 //	@Summary		Obtiene chats directos
-//	@Description	Devuelve todos los chats directos del usuario autenticado
+//	@Description	Devuelve los chats directos del usuario autenticado, con sus preferencias de notificación. Sin archived solo se incluyen los no archivados. Las solicitudes de mensajes recibidas sin aceptar y los chats borrados por el usuario no se incluyen
 //	@Tags			Chat
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Success		200	{array}		models.DirectChat	"Lista de chats directos"
-//	@Failure		401	{string}	string				"No autorizado"
-//	@Failure		500	{string}	string				"Error interno del servidor"
+//	@Param			archived	query		bool				false	"Listar solo los chats archivados"
+//	@Success		200			{array}		models.DirectChat	"Lista de chats directos"
+//	@Failure		401			{string}	string				"No autorizado"
+//	@Failure		500			{string}	string				"Error interno del servidor"
 //	@Router			/chat/direct/me [get]
 func (h *ChatHandler) GetUserDirectChats(w http.ResponseWriter, r *http.Request) {
 	// Obtener el ID del usuario del contexto
@@ -224,15 +418,130 @@ func (h *ChatHandler) GetUserDirectChats(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	chats, err := h.DirectChatService.GetUserDirectChatsWithSenderNames(userID)
+	archived := r.URL.Query().Get("archived") == "true"
+
+	chats, err := h.DirectChatService.GetUserDirectChatsWithSenderNames(userID, archived)
 	if err != nil {
 		http.Error(w, "Error getting direct chats: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	// Incluir las preferencias de notificación del usuario en cada chat
+	if err := h.NotificationService.AttachDirectChatPreferences(userID, chats); err != nil {
+		http.Error(w, "Error getting direct chats: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	json.NewEncoder(w).Encode(chats)
 }
 
+// ArchiveDirectChat archiva un chat directo para el usuario
+//
+//	@Summary		Archiva un chat directo
+//	@Description	Mueve el chat a los archivados del usuario autenticado. Vuelve a la lista principal al llegar un mensaje nuevo
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId	path		string	true	"ID del chat directo"
+//	@Success		200		{string}	string	"Chat archivado"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		404		{string}	string	"Chat no encontrado"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/direct/{chatId}/archive [post]
+func (h *ChatHandler) ArchiveDirectChat(w http.ResponseWriter, r *http.Request) {
+	h.setDirectChatArchived(w, r, true)
+}
+
+// UnarchiveDirectChat devuelve un chat directo archivado a la lista principal del usuario
+//
+//	@Summary		Desarchiva un chat directo
+//	@Description	Devuelve el chat a la lista principal del usuario autenticado
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId	path		string	true	"ID del chat directo"
+//	@Success		200		{string}	string	"Chat desarchivado"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		404		{string}	string	"Chat no encontrado"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/direct/{chatId}/archive [delete]
+func (h *ChatHandler) UnarchiveDirectChat(w http.ResponseWriter, r *http.Request) {
+	h.setDirectChatArchived(w, r, false)
+}
+
+// setDirectChatArchived archiva o desarchiva un chat directo para el usuario
+func (h *ChatHandler) setDirectChatArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	chatID := chi.URLParam(r, "chatId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.DirectChatService.ArchiveDirectChat(chatID, userID, archived); err != nil {
+		if err.Error() == "direct chat not found" {
+			http.Error(w, "Error archiving direct chat: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error archiving direct chat: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	message := "Direct chat archived successfully"
+	if !archived {
+		message = "Direct chat unarchived successfully"
+	}
+	response := map[string]string{
+		"message": message,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeleteDirectChat borra un chat directo solo para el usuario
+//
+//	@Summary		Borra un chat directo para el usuario
+//	@Description	Oculta el chat y vacía su historial solo para el usuario autenticado; los demás participantes lo conservan. Si llega un mensaje nuevo, el chat reaparece con los mensajes posteriores al borrado
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId	path		string	true	"ID del chat directo"
+//	@Success		200		{string}	string	"Chat borrado para el usuario"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		404		{string}	string	"Chat no encontrado"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/direct/{chatId} [delete]
+func (h *ChatHandler) DeleteDirectChat(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "chatId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.DirectChatService.DeleteDirectChatForUser(chatID, userID); err != nil {
+		if err.Error() == "direct chat not found" {
+			http.Error(w, "Error deleting direct chat: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error deleting direct chat: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Direct chat deleted successfully",
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
 // GetDirectChatMessages obtiene los mensajes de un chat directo
 //
 //	@Summary		Obtiene mensajes de un chat directo
@@ -275,7 +584,7 @@ func (h *ChatHandler) GetDirectChatMessages(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
-	messages, err := h.DirectChatService.GetDirectChatMessages(chatID, limit)
+	messages, err := h.DirectChatService.GetDirectChatMessages(chatID, userID, limit)
 	if err != nil {
 		http.Error(w, "Error getting messages: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -284,32 +593,57 @@ func (h *ChatHandler) GetDirectChatMessages(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(messages)
 }
 
-// GetAllRooms obtiene todas las salas ordenadas por updatedAt
+// DiscoverRooms busca salas públicas con paginación por cursor
 //
-//	@Summary		Obtiene todas las salas
-//	@Description	Devuelve todas las salas ordenadas por fecha de actualización descendente
+//	@Summary		Descubre salas públicas
+//	@Description	Devuelve salas públicas no eliminadas, con búsqueda por nombre y descripción, filtro por categoría y orden por actividad o número de miembros
 //	@Tags			Chat
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Success		200	{array}		models.Room	"Lista de salas"
-//	@Failure		401	{string}	string		"No autorizado"
-//	@Failure		500	{string}	string		"Error interno del servidor"
+//	@Param			q			query		string							false	"Texto a buscar en el nombre y la descripción"
+//	@Param			category	query		string							false	"Categoría de la sala"
+//	@Param			sort		query		string							false	"Orden de los resultados"					Enums(activity, members)	default(activity)
+//	@Param			limit		query		int								false	"Número de salas por página (máximo 50)"	default(20)
+//	@Param			cursor		query		string							false	"Cursor devuelto por la página anterior"
+//	@Success		200			{object}	models.PaginatedRoomsResponse	"Página de salas"
+//	@Failure		400			{string}	string							"Parámetros inválidos"
+//	@Failure		401			{string}	string							"No autorizado"
+//	@Failure		500			{string}	string							"Error interno del servidor"
 //	@Router			/chat/rooms [get]
-func (h *ChatHandler) GetAllRooms(w http.ResponseWriter, r *http.Request) {
-	rooms, err := h.RoomService.GetAllRooms()
+func (h *ChatHandler) DiscoverRooms(w http.ResponseWriter, r *http.Request) {
+	query := models.RoomDiscoveryQuery{
+		Search:   r.URL.Query().Get("q"),
+		Category: r.URL.Query().Get("category"),
+		Sort:     r.URL.Query().Get("sort"),
+		Cursor:   r.URL.Query().Get("cursor"),
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err == nil && parsedLimit > 0 {
+			query.Limit = parsedLimit
+		}
+	}
+
+	response, err := h.RoomService.DiscoverRooms(query)
 	if err != nil {
-		http.Error(w, "Error getting rooms: "+err.Error(), http.StatusInternalServerError)
+		switch err.Error() {
+		case "invalid sort", "invalid cursor":
+			http.Error(w, "Error getting rooms: "+err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Error getting rooms: "+err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 
-	json.NewEncoder(w).Encode(rooms)
+	json.NewEncoder(w).Encode(response)
 }
 
 // JoinRoom permite a un usuario unirse a una sala
 //
 //	@Summary		Unirse a una sala
-//	@Description	Permite al usuario autenticado unirse a una sala específica
+//	@Description	Permite al usuario autenticado unirse a una sala pública. A las salas privadas solo se entra con una invitación
 //	@Tags			Chat
 //	@Accept			json
 //	@Produce		json
@@ -317,7 +651,7 @@ func (h *ChatHandler) GetAllRooms(w http.ResponseWriter, r *http.Request) {
 //	@Param			roomId	path		string	true	"ID de la sala"
 //	@Success		200		{string}	string	"Usuario unido exitosamente"
 //	@Failure		401		{string}	string	"No autorizado"
-//	@Failure		403		{string}	string	"No permitido unirse a esta sala"
+//	@Failure		403		{string}	string	"No permitido unirse a esta sala o usuario baneado"
 //	@Failure		404		{string}	string	"Sala no encontrada"
 //	@Failure		409		{string}	string	"Usuario ya es miembro de la sala"
 //	@Failure		500		{string}	string	"Error interno del servidor"
@@ -334,7 +668,7 @@ func (h *ChatHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 
 	err := h.RoomService.JoinRoom(roomID, userID)
 	if err != nil {
-		if err.Error() == "user is not allowed to join this room" {
+		if err.Error() == "user is not allowed to join this room" || err.Error() == "user is banned from the room" {
 			http.Error(w, "Error joining room: "+err.Error(), http.StatusForbidden)
 			return
 		}
@@ -342,6 +676,10 @@ func (h *ChatHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Error joining room: "+err.Error(), http.StatusConflict)
 			return
 		}
+		if err.Error() == "room not found" {
+			http.Error(w, "Error joining room: "+err.Error(), http.StatusNotFound)
+			return
+		}
 		http.Error(w, "Error joining room: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -354,6 +692,297 @@ func (h *ChatHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// LeaveRoom permite a un usuario abandonar una sala
+//
+//	@Summary		Abandonar una sala
+//	@Description	Permite al usuario autenticado abandonar una sala. El propietario no puede abandonar su sala
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string	true	"ID de la sala"
+//	@Success		200		{string}	string	"Usuario salió de la sala exitosamente"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		403		{string}	string	"El propietario no puede abandonar la sala, ni los admins del espacio sus canales"
+//	@Failure		404		{string}	string	"Sala no encontrada o usuario no es miembro"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/leave [post]
+func (h *ChatHandler) LeaveRoom(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.RoomService.LeaveRoom(roomID, userID); err != nil {
+		switch err.Error() {
+		case "room not found", "user is not a member of the room":
+			http.Error(w, "Error leaving room: "+err.Error(), http.StatusNotFound)
+		case "room owner cannot leave the room", "space admins cannot leave channels of their space":
+			http.Error(w, "Error leaving room: "+err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, "Error leaving room: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Avisar al resto de miembros y dejar de enviar mensajes de la sala al usuario
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeUserLeave, pws.UserLeavePayload{
+		RoomID: roomID,
+		UserID: userID,
+		Reason: "left",
+	})
+	h.Hub.RemoveUserFromRoom(userID, roomID)
+
+	response := map[string]string{
+		"message": "Successfully left the room",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// RemoveMember expulsa a un miembro de una sala
+//
+//	@Summary		Expulsar a un miembro
+//	@Description	Expulsa a un miembro de la sala. Requiere la capacidad remove_members y solo el propietario puede expulsar a quienes también la tienen
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string	true	"ID de la sala"
+//	@Param			userId	path		string	true	"ID del usuario a expulsar"
+//	@Success		200		{string}	string	"Miembro expulsado exitosamente"
+//	@Failure		400		{string}	string	"Solicitud inválida"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		403		{string}	string	"No permitido expulsar a este usuario"
+//	@Failure		404		{string}	string	"Sala no encontrada o usuario no es miembro"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/members/{userId} [delete]
+func (h *ChatHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	targetID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.RoomService.RemoveMember(roomID, userID, targetID); err != nil {
+		switch err.Error() {
+		case "use leave to exit the room":
+			http.Error(w, "Error removing member: "+err.Error(), http.StatusBadRequest)
+		case "room not found", "user is not a member of the room":
+			http.Error(w, "Error removing member: "+err.Error(), http.StatusNotFound)
+		case "user is not allowed to remove members",
+			"the room owner cannot be removed",
+			"only the room owner can remove moderators":
+			http.Error(w, "Error removing member: "+err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, "Error removing member: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Avisar a la sala (incluido el expulsado) antes de cancelar sus suscripciones
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeUserLeave, pws.UserLeavePayload{
+		RoomID:    roomID,
+		UserID:    targetID,
+		Reason:    "removed",
+		RemovedBy: userID,
+	})
+	h.Hub.RemoveUserFromRoom(targetID, roomID)
+
+	response := map[string]string{
+		"message": "Member removed successfully",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetRoomMembers obtiene el directorio de miembros de una sala
+//
+//	@Summary		Directorio de miembros de una sala
+//	@Description	Devuelve los miembros con su nombre, foto, rol, fecha de unión, conexión y restricción por reportes, ordenados por fecha de unión. El número y el peso de los reportes de cada miembro solo se incluyen para quienes tienen manage_reports. Las salas privadas solo son visibles para sus miembros
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string								true	"ID de la sala"
+//	@Param			role	query		string								false	"Filtra por rol (owner, admin, member o un rol propio)"
+//	@Param			limit	query		int									false	"Número de miembros por página (máximo 100)"	default(50)
+//	@Param			cursor	query		string								false	"Cursor devuelto por la página anterior"
+//	@Success		200		{object}	models.PaginatedRoomMembersResponse	"Página de miembros"
+//	@Failure		400		{string}	string								"Cursor inválido"
+//	@Failure		401		{string}	string								"No autorizado"
+//	@Failure		403		{string}	string								"La sala es privada y el usuario no es miembro"
+//	@Failure		404		{string}	string								"Sala o rol no encontrado"
+//	@Failure		500		{string}	string								"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/members [get]
+func (h *ChatHandler) GetRoomMembers(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	response, err := h.RoomService.GetRoomMembers(roomID, userID, r.URL.Query().Get("role"), limit, r.URL.Query().Get("cursor"))
+	if err != nil {
+		switch err.Error() {
+		case "room not found", "role not found":
+			http.Error(w, "Error getting members: "+err.Error(), http.StatusNotFound)
+		case "user is not a member of the room":
+			http.Error(w, "Error getting members: "+err.Error(), http.StatusForbidden)
+		case "invalid cursor":
+			http.Error(w, "Error getting members: "+err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Error getting members: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// La presencia se obtiene de las conexiones WebSocket abiertas
+	for i := range response.Members {
+		response.Members[i].Online = h.Hub.IsOnline(response.Members[i].UserID)
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeleteMessage elimina un mensaje de una sala
+//
+//	@Summary		Elimina un mensaje
+//	@Description	El autor puede eliminar sus propios mensajes; eliminar los de otros requiere la capacidad delete_messages
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId		path		string	true	"ID de la sala"
+//	@Param			messageId	path		string	true	"ID del mensaje"
+//	@Success		200			{string}	string	"Mensaje eliminado exitosamente"
+//	@Failure		401			{string}	string	"No autorizado"
+//	@Failure		403			{string}	string	"No permitido eliminar el mensaje"
+//	@Failure		404			{string}	string	"Sala o mensaje no encontrado"
+//	@Failure		500			{string}	string	"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/messages/{messageId} [delete]
+func (h *ChatHandler) DeleteMessage(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	messageID := chi.URLParam(r, "messageId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.RoomService.DeleteMessage(roomID, userID, messageID); err != nil {
+		switch err.Error() {
+		case "room not found", "message not found":
+			http.Error(w, "Error deleting message: "+err.Error(), http.StatusNotFound)
+		case "user is not allowed to delete this message":
+			http.Error(w, "Error deleting message: "+err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, "Error deleting message: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeMessageDeleted, pws.MessageDeletedPayload{
+		RoomID:    roomID,
+		MessageID: messageID,
+		DeletedBy: userID,
+	})
+
+	response := map[string]string{
+		"message": "Message deleted successfully",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetThread obtiene un mensaje de una sala y las respuestas de su hilo
+//
+//	@Summary		Obtiene un hilo de mensajes
+//	@Description	Devuelve el mensaje raíz y sus respuestas, de la más antigua a la más reciente
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId		path		string					true	"ID de la sala"
+//	@Param			messageId	path		string					true	"ID del mensaje raíz"
+//	@Param			limit		query		int						false	"Límite de respuestas a obtener"	default(50)
+//	@Success		200			{object}	models.ThreadResponse	"Hilo del mensaje"
+//	@Failure		401			{string}	string					"No autorizado"
+//	@Failure		403			{string}	string					"La sala es privada y el usuario no es miembro"
+//	@Failure		404			{string}	string					"Sala o mensaje no encontrado"
+//	@Failure		500			{string}	string					"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/messages/{messageId}/thread [get]
+func (h *ChatHandler) GetThread(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	messageID := chi.URLParam(r, "messageId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	// Los hilos de las salas privadas solo son visibles para sus miembros
+	if err := h.RoomService.CanViewRoom(roomID, userID); err != nil {
+		if err.Error() == "room not found" {
+			http.Error(w, "Error getting thread: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error getting thread: "+err.Error(), http.StatusForbidden)
+		return
+	}
+
+	limitStr := r.URL.Query().Get("limit")
+	limit := 50 // valor por defecto
+
+	if limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	root, replies, err := h.RoomService.GetThreadReplies(roomID, messageID, userID, limit)
+	if err != nil {
+		if err.Error() == "message not found" {
+			http.Error(w, "Error getting thread: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error getting thread: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(models.ThreadResponse{
+		Root:    *root,
+		Replies: replies,
+	})
+}
+
 // GetRoomMessagesSimple obtiene los mensajes de una sala sin paginación
 //
 //	@Summary		Obtiene mensajes de una sala (versión simple)
@@ -366,12 +995,30 @@ func (h *ChatHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 //	@Param			limit	query		int						false	"Límite de mensajes a obtener"	default(50)
 //	@Success		200		{array}		models.MessageResponse	"Lista de mensajes de la sala"
 //	@Failure		401		{string}	string					"No autorizado"
+//	@Failure		403		{string}	string					"La sala es privada y el usuario no es miembro"
 //	@Failure		404		{string}	string					"Sala no encontrada"
 //	@Failure		500		{string}	string					"Error interno del servidor"
 //	@Router			/chat/rooms/{roomId}/messages [get]
 func (h *ChatHandler) GetRoomMessagesSimple(w http.ResponseWriter, r *http.Request) {
 	roomID := chi.URLParam(r, "roomId")
 
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	// Los mensajes de las salas privadas solo son visibles para sus miembros
+	if err := h.RoomService.CanViewRoom(roomID, userID); err != nil {
+		if err.Error() == "room not found" {
+			http.Error(w, "Error getting messages: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error getting messages: "+err.Error(), http.StatusForbidden)
+		return
+	}
+
 	limitStr := r.URL.Query().Get("limit")
 	limit := 50 // valor por defecto
 
@@ -382,7 +1029,7 @@ func (h *ChatHandler) GetRoomMessagesSimple(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
-	messages, err := h.RoomService.GetRoomMessagesSimple(roomID, limit)
+	messages, err := h.RoomService.GetRoomMessagesSimple(roomID, userID, limit)
 	if err != nil {
 		http.Error(w, "Error getting messages: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// ContentFilterHandler maneja la configuración del filtro de contenido de las salas
+type ContentFilterHandler struct {
+	ContentFilterService *services.ContentFilterService
+}
+
+// NewContentFilterHandler crea una nueva instancia de ContentFilterHandler
+func NewContentFilterHandler(contentFilterService *services.ContentFilterService) *ContentFilterHandler {
+	return &ContentFilterHandler{
+		ContentFilterService: contentFilterService,
+	}
+}
+
+// GetContentFilter devuelve el filtro de contenido de una sala
+//
+//	@Summary		Filtro de contenido de una sala
+//	@Description	Devuelve la acción de la lista de palabrotas por defecto y las reglas propias de la sala. Requiere la capacidad manage_room
+//	@Tags			Moderation
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string							true	"ID de la sala"
+//	@Success		200		{object}	models.ContentFilterSettings	"Filtro de la sala"
+//	@Failure		401		{string}	string							"No autorizado"
+//	@Failure		403		{string}	string							"No permitido gestionar el filtro"
+//	@Failure		404		{string}	string							"Sala no encontrada"
+//	@Failure		500		{string}	string							"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/content-filter [get]
+func (h *ContentFilterHandler) GetContentFilter(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	settings, err := h.ContentFilterService.GetRoomContentFilter(roomID, userID)
+	if err != nil {
+		writeContentFilterError(w, "Error getting content filter: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateContentFilter reemplaza el filtro de contenido de una sala
+//
+//	@Summary		Actualiza el filtro de contenido de una sala
+//	@Description	Reemplaza la acción de la lista de palabrotas por defecto (reject, mask, flag o vacío para desactivarla) y las reglas propias, como mucho 200. Cada regla es una palabra o frase (word, con "*" final para cualquier terminación), una expresión regular RE2 (regex) o un dominio de enlaces con sus subdominios (domain), con la acción reject (el mensaje no se envía), mask (se ocultan las coincidencias) o flag (se envía y se reporta para revisión). Requiere la capacidad manage_room
+//	@Tags			Moderation
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string								true	"ID de la sala"
+//	@Param			filter	body		models.UpdateContentFilterRequest	true	"Nueva configuración del filtro"
+//	@Success		200		{object}	models.ContentFilterSettings		"Filtro actualizado"
+//	@Failure		400		{string}	string								"Regla o acción inválida"
+//	@Failure		401		{string}	string								"No autorizado"
+//	@Failure		403		{string}	string								"No permitido gestionar el filtro"
+//	@Failure		404		{string}	string								"Sala no encontrada"
+//	@Failure		500		{string}	string								"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/content-filter [put]
+func (h *ContentFilterHandler) UpdateContentFilter(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req models.UpdateContentFilterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.ContentFilterService.UpdateRoomContentFilter(roomID, userID, &req)
+	if err != nil {
+		writeContentFilterError(w, "Error updating content filter: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(settings)
+}
+
+// writeContentFilterError traduce los errores del filtro de contenido a su código HTTP
+func writeContentFilterError(w http.ResponseWriter, prefix string, err error) {
+	switch err.Error() {
+	case "room not found":
+		http.Error(w, prefix+err.Error(), http.StatusNotFound)
+	case "user is not allowed to manage the content filter":
+		http.Error(w, prefix+err.Error(), http.StatusForbidden)
+	case "invalid content filter action", "too many content filter rules",
+		"invalid content filter rule", "invalid content filter pattern":
+		http.Error(w, prefix+err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
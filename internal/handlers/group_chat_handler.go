@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Parchat/backend/internal/models"
+	pws "github.com/Parchat/backend/internal/pkg/websocket"
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// GroupChatHandler maneja las peticiones de los chats directos de grupo
+type GroupChatHandler struct {
+	DirectChatService *services.DirectChatService
+	Hub               *pws.Hub
+}
+
+// NewGroupChatHandler crea una nueva instancia de GroupChatHandler
+func NewGroupChatHandler(directChatService *services.DirectChatService, hub *pws.Hub) *GroupChatHandler {
+	return &GroupChatHandler{
+		DirectChatService: directChatService,
+		Hub:               hub,
+	}
+}
+
+// CreateGroupDirectChat crea o encuentra un chat de grupo
+//
+//	@Summary		Crea un chat de grupo
+//	@Description	Crea un chat directo entre el usuario autenticado y varios usuarios (3 a 20 participantes en total). Si ya existe un chat de grupo con exactamente los mismos participantes se devuelve ese
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chat	body		models.CreateGroupDirectChatRequest	true	"Participantes y título opcional"
+//	@Success		201		{object}	models.DirectChat					"Chat de grupo creado"
+//	@Success		200		{object}	models.DirectChat					"Chat de grupo existente"
+//	@Failure		400		{string}	string								"Solicitud inválida"
+//	@Failure		401		{string}	string								"No autorizado"
+//	@Failure		403		{string}	string								"La privacidad de algún participante o un bloqueo no lo permite"
+//	@Failure		404		{string}	string								"Usuario no encontrado"
+//	@Failure		500		{string}	string								"Error interno del servidor"
+//	@Router			/chat/direct/group [post]
+func (h *GroupChatHandler) CreateGroupDirectChat(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateGroupDirectChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	chat, created, err := h.DirectChatService.CreateGroupDirectChat(userID, &req)
+	if err != nil {
+		writeGroupChatError(w, "Error creating group chat: ", err)
+		return
+	}
+
+	if created {
+		h.notifyParticipants(chat)
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(chat)
+}
+
+// UpdateDirectChat cambia el título de un chat de grupo
+//
+//	@Summary		Cambia un chat de grupo
+//	@Description	Cambia el título de un chat de grupo. Cualquier participante puede hacerlo; un título vacío lo quita
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId	path		string							true	"ID del chat de grupo"
+//	@Param			chat	body		models.UpdateDirectChatRequest	true	"Cambios del chat"
+//	@Success		200		{object}	models.DirectChat				"Chat actualizado"
+//	@Failure		400		{string}	string							"Solicitud inválida o el chat no es de grupo"
+//	@Failure		401		{string}	string							"No autorizado"
+//	@Failure		404		{string}	string							"Chat no encontrado"
+//	@Failure		500		{string}	string							"Error interno del servidor"
+//	@Router			/chat/direct/{chatId} [put]
+func (h *GroupChatHandler) UpdateDirectChat(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "chatId")
+
+	var req models.UpdateDirectChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	chat, err := h.DirectChatService.UpdateDirectChat(chatID, userID, &req)
+	if err != nil {
+		writeGroupChatError(w, "Error updating group chat: ", err)
+		return
+	}
+
+	h.Hub.SendToUsers(chat.UserIDs, pws.MessageTypeDirectChatUpdated, chat)
+
+	json.NewEncoder(w).Encode(chat)
+}
+
+// AddParticipant añade un usuario a un chat de grupo
+//
+//	@Summary		Añade un participante
+//	@Description	Añade un usuario a un chat de grupo. Cualquier participante puede hacerlo
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId		path		string									true	"ID del chat de grupo"
+//	@Param			participant	body		models.AddDirectChatParticipantRequest	true	"Usuario a añadir"
+//	@Success		200			{object}	models.DirectChat						"Chat actualizado"
+//	@Failure		400			{string}	string									"Solicitud inválida, el chat no es de grupo o está lleno"
+//	@Failure		401			{string}	string									"No autorizado"
+//	@Failure		403			{string}	string									"La privacidad del usuario o un bloqueo no lo permite"
+//	@Failure		404			{string}	string									"Chat o usuario no encontrado"
+//	@Failure		409			{string}	string									"El usuario ya participa en el chat"
+//	@Failure		500			{string}	string									"Error interno del servidor"
+//	@Router			/chat/direct/{chatId}/participants [post]
+func (h *GroupChatHandler) AddParticipant(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "chatId")
+
+	var req models.AddDirectChatParticipantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == "" {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	chat, err := h.DirectChatService.AddParticipant(chatID, userID, req.UserID)
+	if err != nil {
+		writeGroupChatError(w, "Error adding participant: ", err)
+		return
+	}
+
+	h.notifyParticipants(chat)
+
+	json.NewEncoder(w).Encode(chat)
+}
+
+// RemoveParticipant quita a un usuario de un chat de grupo
+//
+//	@Summary		Quita un participante
+//	@Description	Quita a un usuario de un chat de grupo. Solo el creador del grupo puede quitar a otros; quitarse a uno mismo equivale a abandonar el chat
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId	path		string				true	"ID del chat de grupo"
+//	@Param			userId	path		string				true	"ID del participante"
+//	@Success		200		{object}	models.DirectChat	"Chat actualizado"
+//	@Failure		400		{string}	string				"El chat no es de grupo"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		403		{string}	string				"Solo el creador puede quitar participantes"
+//	@Failure		404		{string}	string				"Chat o participante no encontrado"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/direct/{chatId}/participants/{userId} [delete]
+func (h *GroupChatHandler) RemoveParticipant(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "chatId")
+	targetID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	chat, err := h.DirectChatService.RemoveParticipant(chatID, userID, targetID)
+	if err != nil {
+		writeGroupChatError(w, "Error removing participant: ", err)
+		return
+	}
+
+	h.notifyParticipantRemoved(chat, targetID)
+
+	json.NewEncoder(w).Encode(chat)
+}
+
+// LeaveDirectChat saca al usuario de un chat de grupo
+//
+//	@Summary		Abandona un chat de grupo
+//	@Description	Saca al usuario autenticado de un chat de grupo. Si era el creador, el siguiente participante pasa a serlo
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId	path		string				true	"ID del chat de grupo"
+//	@Success		200		{object}	models.DirectChat	"Chat actualizado"
+//	@Failure		400		{string}	string				"El chat no es de grupo"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		404		{string}	string				"Chat no encontrado"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/direct/{chatId}/leave [post]
+func (h *GroupChatHandler) LeaveDirectChat(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "chatId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	chat, err := h.DirectChatService.LeaveDirectChat(chatID, userID)
+	if err != nil {
+		writeGroupChatError(w, "Error leaving group chat: ", err)
+		return
+	}
+
+	h.notifyParticipantRemoved(chat, userID)
+
+	json.NewEncoder(w).Encode(chat)
+}
+
+// notifyParticipants avisa del cambio a los participantes. Quienes tienen la invitación pendiente
+// la reciben como solicitud de mensajes
+func (h *GroupChatHandler) notifyParticipants(chat *models.DirectChat) {
+	h.Hub.SendToUsers(chat.AcceptedUserIDs(), pws.MessageTypeDirectChatUpdated, chat)
+
+	var pendingIDs []string
+	for userID := range chat.PendingInvites {
+		pendingIDs = append(pendingIDs, userID)
+	}
+	if len(pendingIDs) > 0 {
+		h.Hub.SendToUsers(pendingIDs, pws.MessageTypeMessageRequestReceived, chat)
+	}
+}
+
+// notifyParticipantRemoved avisa del cambio a los participantes y al usuario que salió,
+// y deja de enviarle los mensajes del chat
+func (h *GroupChatHandler) notifyParticipantRemoved(chat *models.DirectChat, removedID string) {
+	h.Hub.SendToUsers(append(chat.UserIDs, removedID), pws.MessageTypeDirectChatUpdated, chat)
+	h.Hub.RemoveUserFromDirectChat(removedID, chat.ID)
+}
+
+// writeGroupChatError traduce los errores de los chats de grupo a su código HTTP
+func writeGroupChatError(w http.ResponseWriter, prefix string, err error) {
+	switch err.Error() {
+	case "direct chat not found", "user not found", "user is not a participant":
+		http.Error(w, prefix+err.Error(), http.StatusNotFound)
+	case "only the group creator can remove participants", "user does not accept direct messages", "user is blocked":
+		http.Error(w, prefix+err.Error(), http.StatusForbidden)
+	case "user is already a participant":
+		http.Error(w, prefix+err.Error(), http.StatusConflict)
+	case "direct chat is not a group", "group direct chat is full", "title is too long",
+		"not enough participants for a group direct chat", "too many participants for a group direct chat":
+		http.Error(w, prefix+err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// InviteHandler maneja las peticiones relacionadas con las invitaciones a salas
+type InviteHandler struct {
+	InviteService *services.InviteService
+}
+
+// NewInviteHandler crea una nueva instancia de InviteHandler
+func NewInviteHandler(inviteService *services.InviteService) *InviteHandler {
+	return &InviteHandler{
+		InviteService: inviteService,
+	}
+}
+
+// CreateInvite genera una invitación para una sala
+//
+//	@Summary		Crea una invitación
+//	@Description	Genera un código y un enlace de invitación con expiración y número de usos opcionales. Requiere la capacidad invite_members
+//	@Tags			Invites
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string						true	"ID de la sala"
+//	@Param			invite	body		models.CreateInviteRequest	false	"Configuración de la invitación"
+//	@Success		201		{object}	models.Invite				"Invitación creada"
+//	@Failure		400		{string}	string						"Solicitud inválida"
+//	@Failure		401		{string}	string						"No autorizado"
+//	@Failure		403		{string}	string						"No permitido gestionar invitaciones"
+//	@Failure		404		{string}	string						"Sala no encontrada"
+//	@Failure		500		{string}	string						"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/invites [post]
+func (h *InviteHandler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	// El cuerpo es opcional: sin él la invitación no expira ni tiene límite de usos
+	var req models.CreateInviteRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+	}
+
+	invite, err := h.InviteService.CreateInvite(roomID, userID, &req)
+	if err != nil {
+		writeInviteError(w, "Error creating invite: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(invite)
+}
+
+// GetRoomInvites lista las invitaciones activas de una sala
+//
+//	@Summary		Lista las invitaciones de una sala
+//	@Description	Devuelve las invitaciones no revocadas de la sala. Requiere la capacidad invite_members
+//	@Tags			Invites
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string			true	"ID de la sala"
+//	@Success		200		{array}		models.Invite	"Invitaciones de la sala"
+//	@Failure		401		{string}	string			"No autorizado"
+//	@Failure		403		{string}	string			"No permitido gestionar invitaciones"
+//	@Failure		404		{string}	string			"Sala no encontrada"
+//	@Failure		500		{string}	string			"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/invites [get]
+func (h *InviteHandler) GetRoomInvites(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	invites, err := h.InviteService.GetRoomInvites(roomID, userID)
+	if err != nil {
+		writeInviteError(w, "Error getting invites: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(invites)
+}
+
+// RevokeInvite revoca una invitación de una sala
+//
+//	@Summary		Revoca una invitación
+//	@Description	Invalida una invitación para que no pueda volver a usarse. Requiere la capacidad invite_members
+//	@Tags			Invites
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string	true	"ID de la sala"
+//	@Param			code	path		string	true	"Código de la invitación"
+//	@Success		200		{string}	string	"Invitación revocada"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		403		{string}	string	"No permitido gestionar invitaciones"
+//	@Failure		404		{string}	string	"Sala o invitación no encontrada"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/invites/{code} [delete]
+func (h *InviteHandler) RevokeInvite(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	code := chi.URLParam(r, "code")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.InviteService.RevokeInvite(roomID, userID, code); err != nil {
+		writeInviteError(w, "Error revoking invite: ", err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Invite revoked successfully",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// PreviewInvite muestra la sala de una invitación sin necesidad de autenticarse
+//
+//	@Summary		Vista previa de una invitación
+//	@Description	Devuelve el nombre y el número de miembros de la sala de una invitación válida. No requiere autenticación
+//	@Tags			Invites
+//	@Accept			json
+//	@Produce		json
+//	@Param			code	path		string					true	"Código de la invitación"
+//	@Success		200		{object}	models.InvitePreview	"Vista previa de la sala"
+//	@Failure		404		{string}	string					"Invitación no encontrada"
+//	@Failure		410		{string}	string					"Invitación expirada, revocada o agotada"
+//	@Failure		500		{string}	string					"Error interno del servidor"
+//	@Router			/invites/{code} [get]
+func (h *InviteHandler) PreviewInvite(w http.ResponseWriter, r *http.Request) {
+	code := chi.URLParam(r, "code")
+
+	preview, err := h.InviteService.PreviewInvite(code)
+	if err != nil {
+		writeInviteError(w, "Error getting invite: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(preview)
+}
+
+// JoinWithInvite une al usuario actual a la sala de una invitación
+//
+//	@Summary		Unirse con una invitación
+//	@Description	Une al usuario autenticado a la sala de la invitación, incluidas las salas privadas
+//	@Tags			Invites
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			code	path		string	true	"Código de la invitación"
+//	@Success		200		{string}	string	"Usuario unido exitosamente"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		403		{string}	string	"Usuario baneado de la sala, o canal de un espacio del que no es miembro"
+//	@Failure		404		{string}	string	"Invitación o sala no encontrada"
+//	@Failure		409		{string}	string	"Usuario ya es miembro de la sala"
+//	@Failure		410		{string}	string	"Invitación expirada, revocada o agotada"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/invites/{code}/join [post]
+func (h *InviteHandler) JoinWithInvite(w http.ResponseWriter, r *http.Request) {
+	code := chi.URLParam(r, "code")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	invite, err := h.InviteService.JoinWithInvite(code, userID)
+	if err != nil {
+		writeInviteError(w, "Error joining room: ", err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Successfully joined the room",
+		"roomId":  invite.RoomID,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeInviteError traduce los errores de las invitaciones a su código HTTP
+func writeInviteError(w http.ResponseWriter, prefix string, err error) {
+	switch err.Error() {
+	case "room not found", "invite not found":
+		http.Error(w, prefix+err.Error(), http.StatusNotFound)
+	case "user is not allowed to manage invites", "user is banned from the room", "user is not a member of the space":
+		http.Error(w, prefix+err.Error(), http.StatusForbidden)
+	case "user is already a member of the room":
+		http.Error(w, prefix+err.Error(), http.StatusConflict)
+	case "invite has expired", "invite has been revoked", "invite has reached its maximum uses":
+		http.Error(w, prefix+err.Error(), http.StatusGone)
+	case "invalid invite settings":
+		http.Error(w, prefix+err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Parchat/backend/internal/models"
+	pws "github.com/Parchat/backend/internal/pkg/websocket"
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// JoinRequestHandler maneja las peticiones relacionadas con las solicitudes para unirse a salas privadas
+type JoinRequestHandler struct {
+	JoinRequestService *services.JoinRequestService
+	Hub                *pws.Hub
+}
+
+// NewJoinRequestHandler crea una nueva instancia de JoinRequestHandler
+func NewJoinRequestHandler(joinRequestService *services.JoinRequestService, hub *pws.Hub) *JoinRequestHandler {
+	return &JoinRequestHandler{
+		JoinRequestService: joinRequestService,
+		Hub:                hub,
+	}
+}
+
+// RequestToJoin envía una solicitud para unirse a una sala privada
+//
+//	@Summary		Solicita unirse a una sala privada
+//	@Description	Crea una solicitud con un mensaje opcional. Los usuarios que pueden aprobarla reciben un evento JOIN_REQUEST_RECEIVED
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string							true	"ID de la sala"
+//	@Param			request	body		models.CreateJoinRequestRequest	false	"Mensaje para los admins"
+//	@Success		201		{object}	models.JoinRequest				"Solicitud creada"
+//	@Failure		400		{string}	string							"Solicitud inválida o sala pública"
+//	@Failure		401		{string}	string							"No autorizado"
+//	@Failure		403		{string}	string							"Usuario baneado de la sala, o canal de un espacio del que no es miembro"
+//	@Failure		404		{string}	string							"Sala no encontrada"
+//	@Failure		409		{string}	string							"Ya es miembro o ya tiene una solicitud pendiente"
+//	@Failure		500		{string}	string							"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/join-requests [post]
+func (h *JoinRequestHandler) RequestToJoin(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	// El mensaje es opcional
+	var req models.CreateJoinRequestRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+	}
+
+	request, reviewers, err := h.JoinRequestService.RequestToJoin(roomID, userID, req.Message)
+	if err != nil {
+		writeJoinRequestError(w, "Error requesting to join: ", err)
+		return
+	}
+
+	// Avisar a quienes pueden aprobar la solicitud, salvo a quienes silenciaron la sala
+	h.Hub.NotifyUsers(models.ConversationTypeRoom, roomID, reviewers, pws.MessageTypeJoinRequestReceived, request)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(request)
+}
+
+// CancelJoinRequest cancela la solicitud pendiente del usuario actual
+//
+//	@Summary		Cancela una solicitud para unirse
+//	@Description	Elimina la solicitud pendiente del usuario autenticado
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string	true	"ID de la sala"
+//	@Success		200		{string}	string	"Solicitud cancelada"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		404		{string}	string	"Solicitud no encontrada"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/join-requests/me [delete]
+func (h *JoinRequestHandler) CancelJoinRequest(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.JoinRequestService.CancelJoinRequest(roomID, userID); err != nil {
+		writeJoinRequestError(w, "Error cancelling join request: ", err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Join request cancelled",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetPendingJoinRequests obtiene la cola de solicitudes pendientes de una sala
+//
+//	@Summary		Cola de solicitudes para unirse
+//	@Description	Devuelve las solicitudes pendientes de la sala, de la más antigua a la más reciente. Requiere la capacidad invite_members
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string				true	"ID de la sala"
+//	@Success		200		{array}		models.JoinRequest	"Solicitudes pendientes"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		403		{string}	string				"No permitido revisar solicitudes"
+//	@Failure		404		{string}	string				"Sala no encontrada"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/join-requests [get]
+func (h *JoinRequestHandler) GetPendingJoinRequests(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	requests, err := h.JoinRequestService.GetPendingJoinRequests(roomID, userID)
+	if err != nil {
+		writeJoinRequestError(w, "Error getting join requests: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(requests)
+}
+
+// ApproveJoinRequest aprueba una solicitud y añade al usuario a la sala
+//
+//	@Summary		Aprueba una solicitud para unirse
+//	@Description	Añade al solicitante como miembro y le envía un evento JOIN_REQUEST_REVIEWED. Requiere la capacidad invite_members
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string				true	"ID de la sala"
+//	@Param			userId	path		string				true	"ID del solicitante"
+//	@Success		200		{object}	models.JoinRequest	"Solicitud aprobada"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		403		{string}	string				"No permitido revisar solicitudes, o el usuario ya no es miembro del espacio del canal"
+//	@Failure		404		{string}	string				"Sala o solicitud no encontrada"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/join-requests/{userId}/approve [post]
+func (h *JoinRequestHandler) ApproveJoinRequest(w http.ResponseWriter, r *http.Request) {
+	h.reviewJoinRequest(w, r, h.JoinRequestService.ApproveJoinRequest)
+}
+
+// RejectJoinRequest rechaza una solicitud para unirse a la sala
+//
+//	@Summary		Rechaza una solicitud para unirse
+//	@Description	Rechaza la solicitud y envía al solicitante un evento JOIN_REQUEST_REVIEWED. Requiere la capacidad invite_members
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string				true	"ID de la sala"
+//	@Param			userId	path		string				true	"ID del solicitante"
+//	@Success		200		{object}	models.JoinRequest	"Solicitud rechazada"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		403		{string}	string				"No permitido revisar solicitudes"
+//	@Failure		404		{string}	string				"Sala o solicitud no encontrada"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/join-requests/{userId}/reject [post]
+func (h *JoinRequestHandler) RejectJoinRequest(w http.ResponseWriter, r *http.Request) {
+	h.reviewJoinRequest(w, r, h.JoinRequestService.RejectJoinRequest)
+}
+
+// reviewJoinRequest aplica una decisión sobre una solicitud y avisa al solicitante
+func (h *JoinRequestHandler) reviewJoinRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+	decide func(roomID, actorID, userID string) (*models.JoinRequest, error),
+) {
+	roomID := chi.URLParam(r, "roomId")
+	targetID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	request, err := decide(roomID, userID, targetID)
+	if err != nil {
+		writeJoinRequestError(w, "Error reviewing join request: ", err)
+		return
+	}
+
+	h.Hub.NotifyUsers(models.ConversationTypeRoom, roomID, []string{targetID}, pws.MessageTypeJoinRequestReviewed, request)
+
+	json.NewEncoder(w).Encode(request)
+}
+
+// writeJoinRequestError traduce los errores de las solicitudes a su código HTTP
+func writeJoinRequestError(w http.ResponseWriter, prefix string, err error) {
+	switch err.Error() {
+	case "room not found", "join request not found":
+		http.Error(w, prefix+err.Error(), http.StatusNotFound)
+	case "user is not allowed to review join requests", "user is banned from the room", "user is not a member of the space":
+		http.Error(w, prefix+err.Error(), http.StatusForbidden)
+	case "user is already a member of the room", "join request is already pending":
+		http.Error(w, prefix+err.Error(), http.StatusConflict)
+	case "room is public, join it directly", "join request message is too long":
+		http.Error(w, prefix+err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
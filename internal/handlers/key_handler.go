@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/Parchat/backend/internal/models"
+	pws "github.com/Parchat/backend/internal/pkg/websocket"
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// KeyHandler maneja las peticiones de claves de cifrado de extremo a extremo
+type KeyHandler struct {
+	KeyService *services.KeyService
+	Hub        *pws.Hub
+}
+
+// NewKeyHandler crea una nueva instancia de KeyHandler
+func NewKeyHandler(keyService *services.KeyService, hub *pws.Hub) *KeyHandler {
+	return &KeyHandler{
+		KeyService: keyService,
+		Hub:        hub,
+	}
+}
+
+// PublishDeviceKeys publica las claves públicas de un dispositivo del usuario
+//
+//	@Summary		Publica las claves de un dispositivo
+//	@Description	Publica o reemplaza la clave de identidad y la clave previa firmada de un dispositivo, y opcionalmente añade claves de un solo uso. Si cambia la clave de identidad se borran las claves de un solo uso anteriores. Si el dispositivo es nuevo o cambia su clave de identidad se avisa con KEYS_CHANGED a quienes comparten un chat directo con el usuario
+//	@Tags			Encryption
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			deviceId	path		string							true	"ID del dispositivo, elegido por el cliente"
+//	@Param			keys		body		models.PublishDeviceKeysRequest	true	"Claves públicas del dispositivo"
+//	@Success		200			{object}	models.DeviceKeys				"Claves publicadas"
+//	@Failure		400			{string}	string							"Claves inválidas o demasiados dispositivos"
+//	@Failure		401			{string}	string							"No autorizado"
+//	@Failure		500			{string}	string							"Error interno del servidor"
+//	@Router			/user/devices/{deviceId}/keys [put]
+func (h *KeyHandler) PublishDeviceKeys(w http.ResponseWriter, r *http.Request) {
+	deviceID := chi.URLParam(r, "deviceId")
+
+	var req models.PublishDeviceKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	device, reason, err := h.KeyService.PublishDeviceKeys(userID, deviceID, &req)
+	if err != nil {
+		writeKeyError(w, "Error publishing device keys: ", err)
+		return
+	}
+
+	if reason != "" {
+		h.notifyKeysChanged(userID, deviceID, reason)
+	}
+
+	json.NewEncoder(w).Encode(device)
+}
+
+// UploadPreKeys añade claves de un solo uso a un dispositivo
+//
+//	@Summary		Añade claves de un solo uso
+//	@Description	Añade claves previas de un solo uso a un dispositivo ya publicado. Cada una se entrega como mucho una vez; los clientes deben reponerlas cuando remainingPreKeys baje
+//	@Tags			Encryption
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			deviceId	path		string						true	"ID del dispositivo"
+//	@Param			keys		body		models.UploadPreKeysRequest	true	"Claves de un solo uso (máximo 100)"
+//	@Success		200			{object}	models.DeviceKeys			"Dispositivo actualizado"
+//	@Failure		400			{string}	string						"Claves inválidas"
+//	@Failure		401			{string}	string						"No autorizado"
+//	@Failure		404			{string}	string						"Dispositivo no encontrado"
+//	@Failure		500			{string}	string						"Error interno del servidor"
+//	@Router			/user/devices/{deviceId}/prekeys [post]
+func (h *KeyHandler) UploadPreKeys(w http.ResponseWriter, r *http.Request) {
+	deviceID := chi.URLParam(r, "deviceId")
+
+	var req models.UploadPreKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	device, err := h.KeyService.UploadPreKeys(userID, deviceID, &req)
+	if err != nil {
+		writeKeyError(w, "Error uploading prekeys: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(device)
+}
+
+// GetDevices lista los dispositivos del usuario
+//
+//	@Summary		Dispositivos del usuario
+//	@Description	Lista los dispositivos con claves publicadas del usuario autenticado y cuántas claves de un solo uso les quedan
+//	@Tags			Encryption
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		models.DeviceKeys	"Dispositivos del usuario"
+//	@Failure		401	{string}	string				"No autorizado"
+//	@Failure		500	{string}	string				"Error interno del servidor"
+//	@Router			/user/devices [get]
+func (h *KeyHandler) GetDevices(w http.ResponseWriter, r *http.Request) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	devices, err := h.KeyService.GetUserDevices(userID)
+	if err != nil {
+		http.Error(w, "Error getting devices: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(devices)
+}
+
+// DeleteDevice borra las claves de un dispositivo
+//
+//	@Summary		Borra un dispositivo
+//	@Description	Borra las claves de un dispositivo del usuario y avisa con KEYS_CHANGED a quienes comparten un chat directo con él
+//	@Tags			Encryption
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			deviceId	path		string	true	"ID del dispositivo"
+//	@Success		200			{string}	string	"Dispositivo borrado"
+//	@Failure		401			{string}	string	"No autorizado"
+//	@Failure		404			{string}	string	"Dispositivo no encontrado"
+//	@Failure		500			{string}	string	"Error interno del servidor"
+//	@Router			/user/devices/{deviceId} [delete]
+func (h *KeyHandler) DeleteDevice(w http.ResponseWriter, r *http.Request) {
+	deviceID := chi.URLParam(r, "deviceId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.KeyService.DeleteDevice(userID, deviceID); err != nil {
+		writeKeyError(w, "Error deleting device: ", err)
+		return
+	}
+
+	h.notifyKeysChanged(userID, deviceID, models.KeyChangeDeviceRemoved)
+
+	response := map[string]string{
+		"message": "Device deleted successfully",
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetKeyBundle obtiene los paquetes de claves de otro usuario
+//
+//	@Summary		Paquete de claves de un usuario
+//	@Description	Devuelve las claves públicas de todos los dispositivos de un usuario para iniciar sesiones cifradas con ellos. Cada llamada consume una clave de un solo uso por dispositivo, si le quedan. Cada usuario puede pedir como mucho 10 paquetes de otro por hora
+//	@Tags			Encryption
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			userId	path		string						true	"ID del usuario"
+//	@Success		200		{object}	models.KeyBundleResponse	"Paquetes de claves"
+//	@Failure		401		{string}	string						"No autorizado"
+//	@Failure		403		{string}	string						"Uno de los dos usuarios ha bloqueado al otro"
+//	@Failure		404		{string}	string						"Usuario no encontrado o sin claves publicadas"
+//	@Failure		429		{string}	string						"Demasiadas peticiones del paquete de claves de este usuario"
+//	@Failure		500		{string}	string						"Error interno del servidor"
+//	@Router			/user/keys/{userId} [get]
+func (h *KeyHandler) GetKeyBundle(w http.ResponseWriter, r *http.Request) {
+	targetID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	bundle, err := h.KeyService.GetKeyBundle(userID, targetID)
+	if err != nil {
+		writeKeyError(w, "Error getting key bundle: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(bundle)
+}
+
+// EnableDirectChatEncryption activa el cifrado de extremo a extremo de un chat directo
+//
+//	@Summary		Activa el cifrado de un chat directo
+//	@Description	Activa el cifrado de extremo a extremo de un chat directo. No se puede desactivar. Todos los participantes deben haber publicado claves; desde entonces el chat solo admite mensajes con sobres cifrados y contenido vacío
+//	@Tags			Encryption
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId	path		string				true	"ID del chat directo"
+//	@Success		200		{object}	models.DirectChat	"Chat cifrado"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		404		{string}	string				"Chat no encontrado"
+//	@Failure		409		{string}	string				"Algún participante no ha publicado claves"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/direct/{chatId}/encryption [post]
+func (h *KeyHandler) EnableDirectChatEncryption(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "chatId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	chat, changed, err := h.KeyService.EnableDirectChatEncryption(chatID, userID)
+	if err != nil {
+		writeKeyError(w, "Error enabling encryption: ", err)
+		return
+	}
+
+	if changed {
+		h.Hub.SendToUsers(chat.UserIDs, pws.MessageTypeDirectChatUpdated, chat)
+	}
+
+	json.NewEncoder(w).Encode(chat)
+}
+
+// notifyKeysChanged avisa a quienes comparten un chat directo con el usuario, y a sus otros dispositivos,
+// de que sus claves han cambiado
+func (h *KeyHandler) notifyKeysChanged(userID, deviceID, reason string) {
+	peers, err := h.KeyService.GetKeyChangePeers(userID)
+	if err != nil {
+		log.Printf("Error getting key change peers for %s: %v", userID, err)
+		return
+	}
+
+	h.Hub.SendToUsers(append(peers, userID), pws.MessageTypeKeysChanged, pws.KeysChangedPayload{
+		UserID:   userID,
+		DeviceID: deviceID,
+		Reason:   reason,
+	})
+}
+
+// writeKeyError traduce los errores de las claves de cifrado a su código HTTP
+func writeKeyError(w http.ResponseWriter, prefix string, err error) {
+	switch err.Error() {
+	case "device not found", "user not found", "user has no device keys", "direct chat not found":
+		http.Error(w, prefix+err.Error(), http.StatusNotFound)
+	case "invalid device keys", "too many prekeys", "too many devices":
+		http.Error(w, prefix+err.Error(), http.StatusBadRequest)
+	case "user is blocked":
+		http.Error(w, prefix+err.Error(), http.StatusForbidden)
+	case "all participants must publish device keys first":
+		http.Error(w, prefix+err.Error(), http.StatusConflict)
+	case "too many key bundle requests":
+		http.Error(w, prefix+err.Error(), http.StatusTooManyRequests)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
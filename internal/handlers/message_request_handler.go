@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/Parchat/backend/internal/models"
+	pws "github.com/Parchat/backend/internal/pkg/websocket"
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// MessageRequestHandler maneja las solicitudes de mensajes: chats directos abiertos por usuarios
+// que no son contactos del destinatario
+type MessageRequestHandler struct {
+	DirectChatService *services.DirectChatService
+	UserService       *services.UserService
+	Hub               *pws.Hub
+}
+
+// NewMessageRequestHandler crea una nueva instancia de MessageRequestHandler
+func NewMessageRequestHandler(
+	directChatService *services.DirectChatService,
+	userService *services.UserService,
+	hub *pws.Hub,
+) *MessageRequestHandler {
+	return &MessageRequestHandler{
+		DirectChatService: directChatService,
+		UserService:       userService,
+		Hub:               hub,
+	}
+}
+
+// GetMessageRequests lista las solicitudes de mensajes pendientes del usuario
+//
+//	@Summary		Solicitudes de mensajes
+//	@Description	Devuelve los chats directos que abrieron usuarios que no son contactos del usuario autenticado y que aún no ha aceptado ni rechazado, y los chats de grupo a los que lo añadió alguien que no es su contacto
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		models.DirectChat	"Solicitudes pendientes"
+//	@Failure		401	{string}	string				"No autorizado"
+//	@Failure		500	{string}	string				"Error interno del servidor"
+//	@Router			/chat/direct/requests [get]
+func (h *MessageRequestHandler) GetMessageRequests(w http.ResponseWriter, r *http.Request) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	requests, err := h.DirectChatService.GetMessageRequests(userID)
+	if err != nil {
+		http.Error(w, "Error getting message requests: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(requests)
+}
+
+// AcceptMessageRequest acepta una solicitud de mensajes
+//
+//	@Summary		Acepta una solicitud de mensajes
+//	@Description	El chat pasa a la lista de chats directos del usuario y el remitente recibe un aviso. También se puede aceptar una solicitud rechazada antes. En un chat de grupo el usuario pasa a ser un participante más y se avisa al grupo
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId	path		string				true	"ID del chat directo"
+//	@Success		200		{object}	models.DirectChat	"Solicitud aceptada"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		404		{string}	string				"Solicitud no encontrada"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/direct/{chatId}/accept [post]
+func (h *MessageRequestHandler) AcceptMessageRequest(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, h.DirectChatService.AcceptMessageRequest, "Error accepting message request: ")
+}
+
+// DeclineMessageRequest rechaza una solicitud de mensajes
+//
+//	@Summary		Rechaza una solicitud de mensajes
+//	@Description	Oculta la solicitud sin avisar al remitente, que ya no podrá enviar mensajes en el chat. En un chat de grupo el usuario sale del grupo
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId	path		string				true	"ID del chat directo"
+//	@Success		200		{object}	models.DirectChat	"Solicitud rechazada"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		404		{string}	string				"Solicitud no encontrada"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/direct/{chatId}/decline [post]
+func (h *MessageRequestHandler) DeclineMessageRequest(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, h.DirectChatService.DeclineMessageRequest, "Error declining message request: ")
+}
+
+// BlockMessageRequest rechaza una solicitud de mensajes y bloquea al remitente
+//
+//	@Summary		Rechaza una solicitud y bloquea al remitente
+//	@Description	Rechaza la solicitud sin avisar al remitente y lo añade a los usuarios bloqueados. En un chat de grupo el usuario sale del grupo y se bloquea a quien lo añadió
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId	path		string				true	"ID del chat directo"
+//	@Success		200		{object}	models.DirectChat	"Solicitud rechazada y remitente bloqueado"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		404		{string}	string				"Solicitud no encontrada"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/direct/{chatId}/block [post]
+func (h *MessageRequestHandler) BlockMessageRequest(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, h.DirectChatService.BlockMessageRequest, "Error blocking message request: ")
+}
+
+// respond aplica una respuesta a una solicitud de mensajes y avisa a quien corresponda
+func (h *MessageRequestHandler) respond(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(directChatID, userID string) (*models.DirectChat, error),
+	errPrefix string,
+) {
+	chatID := chi.URLParam(r, "chatId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	chat, err := action(chatID, userID)
+	if err != nil {
+		if err.Error() == "message request not found" {
+			http.Error(w, errPrefix+err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, errPrefix+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// En los chats de grupo, aceptar deja al usuario como participante y rechazar lo saca del grupo
+	if chat.IsGroup {
+		h.Hub.SendToUsers(chat.AcceptedUserIDs(), pws.MessageTypeDirectChatUpdated, chat)
+		if !chat.IsParticipant(userID) {
+			h.Hub.RemoveUserFromDirectChat(userID, chat.ID)
+			h.syncBlockedUsers(r.Context(), userID)
+		}
+		json.NewEncoder(w).Encode(chat)
+		return
+	}
+
+	switch chat.RequestStatus {
+	case models.MessageRequestAccepted:
+		// Solo se avisa al remitente cuando se acepta su solicitud
+		h.Hub.SendToUsers([]string{chat.RequestedBy}, pws.MessageTypeMessageRequestAccepted, chat)
+	case models.MessageRequestDeclined:
+		// Si además se bloqueó al remitente, aplicar el bloqueo a las conexiones abiertas
+		h.syncBlockedUsers(r.Context(), userID)
+	}
+
+	json.NewEncoder(w).Encode(chat)
+}
+
+// syncBlockedUsers aplica la lista de bloqueados actualizada a las conexiones WebSocket abiertas del usuario
+func (h *MessageRequestHandler) syncBlockedUsers(ctx context.Context, userID string) {
+	blockedIDs, err := h.UserService.GetBlockedUserIDs(ctx, userID)
+	if err != nil {
+		log.Printf("Error reloading blocked users for %s: %v", userID, err)
+		return
+	}
+
+	h.Hub.UpdateBlockedUsers(userID, blockedIDs)
+}
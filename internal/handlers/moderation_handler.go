@@ -3,8 +3,10 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/Parchat/backend/internal/models"
+	pws "github.com/Parchat/backend/internal/pkg/websocket"
 	"github.com/Parchat/backend/internal/services"
 	"github.com/go-chi/chi/v5"
 )
@@ -13,23 +15,26 @@ import (
 type ModerationHandler struct {
 	moderationService *services.ModerationService
 	roomService       *services.RoomService
+	hub               *pws.Hub
 }
 
 // NewModerationHandler creates a new instance of ModerationHandler
 func NewModerationHandler(
 	moderationService *services.ModerationService,
 	roomService *services.RoomService,
+	hub *pws.Hub,
 ) *ModerationHandler {
 	return &ModerationHandler{
 		moderationService: moderationService,
 		roomService:       roomService,
+		hub:               hub,
 	}
 }
 
 // ReportMessage handles the request to report an inappropriate message
 //
 //	@Summary		Report an inappropriate message
-//	@Description	Reports a message as inappropriate in a chat room
+//	@Description	Reports a message as inappropriate in a chat room. The report is weighted by the reporter's account age and track record, and counts toward the sender's score for the room's report window
 //	@Tags			Moderation
 //	@Accept			json
 //	@Produce		json
@@ -86,7 +91,7 @@ func (h *ModerationHandler) ReportMessage(w http.ResponseWriter, r *http.Request
 // GetBannedUsers handles the request to get all banned users in a room
 //
 //	@Summary		Get banned users in a room
-//	@Description	Retrieves a list of users who have been banned in a chat room
+//	@Description	Retrieves the users whose weighted reports within the room's window reach its report threshold
 //	@Tags			Moderation
 //	@Accept			json
 //	@Produce		json
@@ -113,15 +118,15 @@ func (h *ModerationHandler) GetBannedUsers(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Check if the user is an admin or owner of the room
-	isAuthorized, err := h.roomService.IsUserAdminOrOwner(roomID, userID)
+	// Check if the user can manage reports in the room
+	isAuthorized, err := h.moderationService.CanManageReports(roomID, userID)
 	if err != nil {
 		http.Error(w, "Error checking user authorization", http.StatusInternalServerError)
 		return
 	}
 
 	if !isAuthorized {
-		http.Error(w, "Unauthorized: Only room admins or owner can view reported users", http.StatusForbidden)
+		http.Error(w, "Unauthorized: You are not allowed to view reported users", http.StatusForbidden)
 		return
 	}
 	// Get banned users in the room
@@ -140,7 +145,7 @@ func (h *ModerationHandler) GetBannedUsers(w http.ResponseWriter, r *http.Reques
 // ClearUserReports handles the request to clear all reports for a user in a room
 //
 //	@Summary		Clear reports for a user
-//	@Description	Clears all reports for a specific user in a chat room
+//	@Description	Dismisses all open reports for a specific user in a chat room and clears their report score. The dismissed reports count against their reporters' track record
 //	@Tags			Moderation
 //	@Accept			json
 //	@Produce		json
@@ -169,15 +174,15 @@ func (h *ModerationHandler) ClearUserReports(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Check if the user is an admin or owner of the room
-	isAuthorized, err := h.roomService.IsUserAdminOrOwner(roomID, userID)
+	// Check if the user can manage reports in the room
+	isAuthorized, err := h.moderationService.CanManageReports(roomID, userID)
 	if err != nil {
 		http.Error(w, "Error checking user authorization", http.StatusInternalServerError)
 		return
 	}
 
 	if !isAuthorized {
-		http.Error(w, "Unauthorized: Only room admins or owner can clear reports", http.StatusForbidden)
+		http.Error(w, "Unauthorized: You are not allowed to clear reports", http.StatusForbidden)
 		return
 	}
 
@@ -195,7 +200,7 @@ func (h *ModerationHandler) ClearUserReports(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Call the service to clear the reports
-	err = h.moderationService.ClearReportsForUser(roomID, clearReq.UserID)
+	err = h.moderationService.ClearReportsForUser(roomID, userID, clearReq.UserID, clearReq.Reason)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -205,3 +210,163 @@ func (h *ModerationHandler) ClearUserReports(w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Reports cleared successfully"))
 }
+
+// GetReportQueue handles the request to list the reports of a room
+//
+//	@Summary		Moderation queue
+//	@Description	Lists the individual reports of a room with a given status, oldest first, with the reported message, the reporter, the reason and the resolution. Requires manage_reports
+//	@Tags			Moderation
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string							true	"Room ID"
+//	@Param			status	query		string							false	"open, dismissed or actioned"	default(open)
+//	@Param			limit	query		int								false	"Reports per page (max 100)"	default(50)
+//	@Param			cursor	query		string							false	"Cursor from the previous page"
+//	@Success		200		{object}	models.PaginatedReportsResponse	"Reports"
+//	@Failure		400		{string}	string							"Invalid status"
+//	@Failure		401		{string}	string							"Unauthorized"
+//	@Failure		403		{string}	string							"Forbidden"
+//	@Failure		404		{string}	string							"Room not found"
+//	@Failure		500		{string}	string							"Internal server error"
+//	@Router			/chat/rooms/{roomId}/reports [get]
+func (h *ModerationHandler) GetReportQueue(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil {
+			limit = parsedLimit
+		}
+	}
+
+	queue, err := h.moderationService.GetReportQueue(
+		roomID, userID, r.URL.Query().Get("status"), limit, r.URL.Query().Get("cursor"),
+	)
+	if err != nil {
+		writeModerationError(w, "Error getting reports: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(queue)
+}
+
+// ResolveReports handles the request to resolve reports with a moderation action
+//
+//	@Summary		Resolve reports
+//	@Description	Resolves open reports against the same user with dismiss, delete_message, warn, mute or ban. Dismissed reports count against their reporters' track record and the rest are marked as actioned; either way they stop counting toward the user's score. The action is saved with the IDs of the reports it resolved, and each report links back to it. Requires manage_reports, plus delete_messages to delete the message and moderate_members to mute or ban
+//	@Tags			Moderation
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId		path		string							true	"Room ID"
+//	@Param			resolution	body		models.ResolveReportsRequest	true	"Reports and action"
+//	@Success		200			{object}	models.ModerationAction			"Moderation action"
+//	@Failure		400			{string}	string							"Invalid request"
+//	@Failure		401			{string}	string							"Unauthorized"
+//	@Failure		403			{string}	string							"Forbidden"
+//	@Failure		404			{string}	string							"Room, report or message not found"
+//	@Failure		409			{string}	string							"Report already resolved"
+//	@Failure		500			{string}	string							"Internal server error"
+//	@Router			/chat/rooms/{roomId}/reports/resolve [post]
+func (h *ModerationHandler) ResolveReports(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	var req models.ResolveReportsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	result, err := h.moderationService.ResolveReports(roomID, userID, &req)
+	if result != nil {
+		// Announce the deleted messages even if the action did not complete
+		broadcastDeletedMessages(h.hub, roomID, userID, result.Action.DeletedMessageIDs)
+	}
+	if err != nil {
+		writeModerationError(w, "Error resolving reports: ", err)
+		return
+	}
+
+	action := result.Action
+	switch action.Type {
+	case models.ModerationActionWarn:
+		h.hub.SendToUsers([]string{action.TargetID}, pws.MessageTypeMemberWarned, pws.WarningPayload{
+			RoomID:   roomID,
+			ActionID: action.ID,
+			Reason:   action.Reason,
+		})
+	case models.ModerationActionMute:
+		broadcastMute(h.hub, result.Sanction)
+	case models.ModerationActionBan:
+		broadcastBan(h.hub, result.Sanction, result.WasMember)
+	}
+
+	json.NewEncoder(w).Encode(action)
+}
+
+// GetModerationActions handles the request to list the moderation actions of a room
+//
+//	@Summary		Moderation actions
+//	@Description	Lists the latest 100 moderation actions of a room, newest first, each with the IDs of the reports it resolved. Requires manage_reports
+//	@Tags			Moderation
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string					true	"Room ID"
+//	@Success		200		{array}		models.ModerationAction	"Moderation actions"
+//	@Failure		401		{string}	string					"Unauthorized"
+//	@Failure		403		{string}	string					"Forbidden"
+//	@Failure		404		{string}	string					"Room not found"
+//	@Failure		500		{string}	string					"Internal server error"
+//	@Router			/chat/rooms/{roomId}/moderation-actions [get]
+func (h *ModerationHandler) GetModerationActions(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	actions, err := h.moderationService.GetModerationActions(roomID, userID)
+	if err != nil {
+		writeModerationError(w, "Error getting moderation actions: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(actions)
+}
+
+// writeModerationError maps moderation queue errors to their HTTP status. Mute and ban errors
+// come from the sanctions and keep their status
+func writeModerationError(w http.ResponseWriter, prefix string, err error) {
+	switch err.Error() {
+	case "report not found", "message not found":
+		http.Error(w, prefix+err.Error(), http.StatusNotFound)
+	case "user is not allowed to manage reports", "user is not allowed to delete this message":
+		http.Error(w, prefix+err.Error(), http.StatusForbidden)
+	case "invalid report status", "invalid moderation action", "no reports to resolve", "too many reports",
+		"reports must be against the same user", "reports must be about the same message":
+		http.Error(w, prefix+err.Error(), http.StatusBadRequest)
+	case "report already resolved":
+		http.Error(w, prefix+err.Error(), http.StatusConflict)
+	default:
+		writeSanctionError(w, prefix, err)
+	}
+}
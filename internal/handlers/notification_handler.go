@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// NotificationHandler maneja las peticiones de preferencias de notificación
+type NotificationHandler struct {
+	NotificationService *services.NotificationService
+}
+
+// NewNotificationHandler crea una nueva instancia de NotificationHandler
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		NotificationService: notificationService,
+	}
+}
+
+// GetUserPreferences lista las preferencias de notificación configuradas por el usuario
+//
+//	@Summary		Preferencias de notificación del usuario
+//	@Description	Devuelve las preferencias de todas las conversaciones que el usuario ha configurado. Las demás usan los valores por defecto
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		models.NotificationPreference	"Preferencias configuradas"
+//	@Failure		401	{string}	string							"No autorizado"
+//	@Failure		500	{string}	string							"Error interno del servidor"
+//	@Router			/user/notification-preferences [get]
+func (h *NotificationHandler) GetUserPreferences(w http.ResponseWriter, r *http.Request) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	prefs, err := h.NotificationService.GetUserPreferences(userID)
+	if err != nil {
+		http.Error(w, "Error getting notification preferences: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// GetRoomPreference obtiene las preferencias de notificación de una sala
+//
+//	@Summary		Preferencias de notificación de una sala
+//	@Description	Devuelve las preferencias del usuario para la sala, o las de por defecto si no las ha configurado
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string							true	"ID de la sala"
+//	@Success		200		{object}	models.NotificationPreference	"Preferencias de la sala"
+//	@Failure		401		{string}	string							"No autorizado"
+//	@Failure		404		{string}	string							"Sala no encontrada o el usuario no es miembro"
+//	@Failure		500		{string}	string							"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/notifications [get]
+func (h *NotificationHandler) GetRoomPreference(w http.ResponseWriter, r *http.Request) {
+	h.getPreference(w, r, models.ConversationTypeRoom, chi.URLParam(r, "roomId"))
+}
+
+// UpdateRoomPreference cambia las preferencias de notificación de una sala
+//
+//	@Summary		Cambia las preferencias de notificación de una sala
+//	@Description	Silencia la sala indefinidamente (muted) o hasta una fecha (mutedUntil), activa el modo solo menciones o cambia sonido y push. Solo se actualizan los campos enviados
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId		path		string										true	"ID de la sala"
+//	@Param			preferences	body		models.UpdateNotificationPreferenceRequest	true	"Preferencias a cambiar"
+//	@Success		200			{object}	models.NotificationPreference				"Preferencias actualizadas"
+//	@Failure		400			{string}	string										"Solicitud inválida"
+//	@Failure		401			{string}	string										"No autorizado"
+//	@Failure		404			{string}	string										"Sala no encontrada o el usuario no es miembro"
+//	@Failure		500			{string}	string										"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/notifications [put]
+func (h *NotificationHandler) UpdateRoomPreference(w http.ResponseWriter, r *http.Request) {
+	h.updatePreference(w, r, models.ConversationTypeRoom, chi.URLParam(r, "roomId"))
+}
+
+// GetDirectChatPreference obtiene las preferencias de notificación de un chat directo
+//
+//	@Summary		Preferencias de notificación de un chat directo
+//	@Description	Devuelve las preferencias del usuario para el chat, o las de por defecto si no las ha configurado
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId	path		string							true	"ID del chat directo"
+//	@Success		200		{object}	models.NotificationPreference	"Preferencias del chat"
+//	@Failure		401		{string}	string							"No autorizado"
+//	@Failure		404		{string}	string							"Chat no encontrado o el usuario no participa"
+//	@Failure		500		{string}	string							"Error interno del servidor"
+//	@Router			/chat/direct/{chatId}/notifications [get]
+func (h *NotificationHandler) GetDirectChatPreference(w http.ResponseWriter, r *http.Request) {
+	h.getPreference(w, r, models.ConversationTypeDirect, chi.URLParam(r, "chatId"))
+}
+
+// UpdateDirectChatPreference cambia las preferencias de notificación de un chat directo
+//
+//	@Summary		Cambia las preferencias de notificación de un chat directo
+//	@Description	Silencia el chat indefinidamente (muted) o hasta una fecha (mutedUntil), activa el modo solo menciones o cambia sonido y push. Solo se actualizan los campos enviados
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			chatId		path		string										true	"ID del chat directo"
+//	@Param			preferences	body		models.UpdateNotificationPreferenceRequest	true	"Preferencias a cambiar"
+//	@Success		200			{object}	models.NotificationPreference				"Preferencias actualizadas"
+//	@Failure		400			{string}	string										"Solicitud inválida"
+//	@Failure		401			{string}	string										"No autorizado"
+//	@Failure		404			{string}	string										"Chat no encontrado o el usuario no participa"
+//	@Failure		500			{string}	string										"Error interno del servidor"
+//	@Router			/chat/direct/{chatId}/notifications [put]
+func (h *NotificationHandler) UpdateDirectChatPreference(w http.ResponseWriter, r *http.Request) {
+	h.updatePreference(w, r, models.ConversationTypeDirect, chi.URLParam(r, "chatId"))
+}
+
+// getPreference responde con las preferencias del usuario para una conversación
+func (h *NotificationHandler) getPreference(w http.ResponseWriter, r *http.Request, conversationType, conversationID string) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	pref, err := h.NotificationService.GetPreference(userID, conversationType, conversationID)
+	if err != nil {
+		writeNotificationError(w, "Error getting notification preferences: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(pref)
+}
+
+// updatePreference aplica los cambios a las preferencias del usuario para una conversación
+func (h *NotificationHandler) updatePreference(w http.ResponseWriter, r *http.Request, conversationType, conversationID string) {
+	var req models.UpdateNotificationPreferenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	pref, err := h.NotificationService.UpdatePreference(userID, conversationType, conversationID, &req)
+	if err != nil {
+		writeNotificationError(w, "Error updating notification preferences: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(pref)
+}
+
+// writeNotificationError traduce los errores de las preferencias de notificación a su código HTTP
+func writeNotificationError(w http.ResponseWriter, prefix string, err error) {
+	switch err.Error() {
+	case "conversation not found":
+		http.Error(w, prefix+err.Error(), http.StatusNotFound)
+	case "mute end must be in the future", "invalid conversation type":
+		http.Error(w, prefix+err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,488 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Parchat/backend/internal/models"
+	pws "github.com/Parchat/backend/internal/pkg/websocket"
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// RoomAdminHandler maneja las peticiones de gestión de roles y propietario de las salas
+type RoomAdminHandler struct {
+	RoomService *services.RoomService
+	Hub         *pws.Hub
+}
+
+// NewRoomAdminHandler crea una nueva instancia de RoomAdminHandler
+func NewRoomAdminHandler(roomService *services.RoomService, hub *pws.Hub) *RoomAdminHandler {
+	return &RoomAdminHandler{
+		RoomService: roomService,
+		Hub:         hub,
+	}
+}
+
+// PromoteAdmin convierte a un miembro en admin de la sala
+//
+//	@Summary		Promueve a un miembro a admin
+//	@Description	Asigna el rol "admin" a un miembro de la sala. Requiere la capacidad manage_roles
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string	true	"ID de la sala"
+//	@Param			userId	path		string	true	"ID del miembro"
+//	@Success		200		{string}	string	"Miembro promovido a admin"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		403		{string}	string	"No permitido gestionar roles"
+//	@Failure		404		{string}	string	"Sala no encontrada o usuario no es miembro"
+//	@Failure		409		{string}	string	"El usuario ya es admin"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/admins/{userId} [post]
+func (h *RoomAdminHandler) PromoteAdmin(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	targetID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.RoomService.AssignRole(roomID, userID, targetID, models.RoleAdmin); err != nil {
+		writeRoleError(w, "Error promoting admin: ", err)
+		return
+	}
+
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeRoleUpdated, pws.RoleUpdatedPayload{
+		RoomID:  roomID,
+		UserID:  targetID,
+		Role:    models.RoleAdmin,
+		ActorID: userID,
+	})
+
+	response := map[string]string{
+		"message": "User promoted to admin",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DemoteAdmin quita el rol de admin a un usuario
+//
+//	@Summary		Quita el rol de admin
+//	@Description	Devuelve a un admin al rol "member". Requiere la capacidad manage_roles
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string	true	"ID de la sala"
+//	@Param			userId	path		string	true	"ID del admin"
+//	@Success		200		{string}	string	"Admin degradado a miembro"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		403		{string}	string	"No permitido gestionar roles"
+//	@Failure		404		{string}	string	"Sala no encontrada o usuario no es admin"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/admins/{userId} [delete]
+func (h *RoomAdminHandler) DemoteAdmin(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	targetID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.RoomService.DemoteAdmin(roomID, userID, targetID); err != nil {
+		writeRoleError(w, "Error demoting admin: ", err)
+		return
+	}
+
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeRoleUpdated, pws.RoleUpdatedPayload{
+		RoomID:  roomID,
+		UserID:  targetID,
+		Role:    models.RoleMember,
+		ActorID: userID,
+	})
+
+	response := map[string]string{
+		"message": "Admin demoted to member",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// AssignRole cambia el rol de un miembro de la sala
+//
+//	@Summary		Asigna un rol a un miembro
+//	@Description	Cambia el rol de un miembro. Requiere la capacidad manage_roles y no se pueden otorgar capacidades que el usuario no tenga
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string						true	"ID de la sala"
+//	@Param			userId	path		string						true	"ID del miembro"
+//	@Param			role	body		models.AssignRoleRequest	true	"Rol a asignar"
+//	@Success		200		{string}	string						"Rol asignado"
+//	@Failure		400		{string}	string						"Solicitud inválida"
+//	@Failure		401		{string}	string						"No autorizado"
+//	@Failure		403		{string}	string						"No permitido gestionar este rol"
+//	@Failure		404		{string}	string						"Sala, rol o miembro no encontrado"
+//	@Failure		409		{string}	string						"El usuario ya tiene ese rol"
+//	@Failure		500		{string}	string						"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/members/{userId}/role [put]
+func (h *RoomAdminHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	targetID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req models.AssignRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Role == "" {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.RoomService.AssignRole(roomID, userID, targetID, req.Role); err != nil {
+		writeRoleError(w, "Error assigning role: ", err)
+		return
+	}
+
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeRoleUpdated, pws.RoleUpdatedPayload{
+		RoomID:  roomID,
+		UserID:  targetID,
+		Role:    req.Role,
+		ActorID: userID,
+	})
+
+	response := map[string]string{
+		"message": "Role assigned successfully",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetRoomRoles obtiene los roles de una sala
+//
+//	@Summary		Obtiene los roles de una sala
+//	@Description	Devuelve los roles predefinidos y propios de la sala con sus capacidades. Solo para miembros
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string						true	"ID de la sala"
+//	@Success		200		{object}	map[string]models.RoomRole	"Roles de la sala"
+//	@Failure		401		{string}	string						"No autorizado"
+//	@Failure		404		{string}	string						"Sala no encontrada o usuario no es miembro"
+//	@Failure		500		{string}	string						"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/roles [get]
+func (h *RoomAdminHandler) GetRoomRoles(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	roles, err := h.RoomService.GetRoomRoles(roomID, userID)
+	if err != nil {
+		writeRoleError(w, "Error getting roles: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(roles)
+}
+
+// SaveRoomRole crea o redefine un rol de la sala
+//
+//	@Summary		Crea o actualiza un rol
+//	@Description	Define las capacidades de un rol propio de la sala o redefine "admin" y "member". Requiere la capacidad manage_roles
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId		path		string					true	"ID de la sala"
+//	@Param			roleName	path		string					true	"Nombre del rol"
+//	@Param			role		body		models.RoomRoleRequest	true	"Capacidades del rol"
+//	@Success		200			{object}	models.RoomRole			"Rol guardado"
+//	@Failure		400			{string}	string					"Nombre o capacidad inválidos"
+//	@Failure		401			{string}	string					"No autorizado"
+//	@Failure		403			{string}	string					"No permitido gestionar este rol"
+//	@Failure		404			{string}	string					"Sala no encontrada"
+//	@Failure		500			{string}	string					"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/roles/{roleName} [put]
+func (h *RoomAdminHandler) SaveRoomRole(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	roleName := chi.URLParam(r, "roleName")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req models.RoomRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.RoomService.SaveRoomRole(roomID, userID, roleName, req.Capabilities)
+	if err != nil {
+		writeRoleError(w, "Error saving role: ", err)
+		return
+	}
+
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeRoomRolesUpdated, pws.RoomRolesUpdatedPayload{
+		RoomID:  roomID,
+		Role:    role,
+		Name:    role.Name,
+		ActorID: userID,
+	})
+
+	json.NewEncoder(w).Encode(role)
+}
+
+// DeleteRoomRole elimina un rol propio de la sala
+//
+//	@Summary		Elimina un rol
+//	@Description	Elimina un rol propio de la sala; sus miembros pasan a ser "member". En "admin" y "member" restablece la definición por defecto
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId		path		string	true	"ID de la sala"
+//	@Param			roleName	path		string	true	"Nombre del rol"
+//	@Success		200			{string}	string	"Rol eliminado"
+//	@Failure		401			{string}	string	"No autorizado"
+//	@Failure		403			{string}	string	"No permitido gestionar este rol"
+//	@Failure		404			{string}	string	"Sala o rol no encontrado"
+//	@Failure		500			{string}	string	"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/roles/{roleName} [delete]
+func (h *RoomAdminHandler) DeleteRoomRole(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	roleName := chi.URLParam(r, "roleName")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	affected, err := h.RoomService.DeleteRoomRole(roomID, userID, roleName)
+	if err != nil {
+		writeRoleError(w, "Error deleting role: ", err)
+		return
+	}
+
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeRoomRolesUpdated, pws.RoomRolesUpdatedPayload{
+		RoomID:  roomID,
+		Name:    roleName,
+		Deleted: true,
+		ActorID: userID,
+	})
+	for _, memberID := range affected {
+		h.Hub.BroadcastToRoom(roomID, pws.MessageTypeRoleUpdated, pws.RoleUpdatedPayload{
+			RoomID:  roomID,
+			UserID:  memberID,
+			Role:    models.RoleMember,
+			ActorID: userID,
+		})
+	}
+
+	response := map[string]string{
+		"message": "Role deleted successfully",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// RequestOwnershipTransfer inicia la transferencia de propiedad de una sala
+//
+//	@Summary		Inicia una transferencia de propiedad
+//	@Description	El propietario propone a otro miembro como nuevo propietario. La transferencia se completa cuando el destinatario la acepta
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId		path		string							true	"ID de la sala"
+//	@Param			transfer	body		models.TransferOwnershipRequest	true	"Nuevo propietario"
+//	@Success		200			{object}	models.OwnershipTransfer		"Transferencia pendiente de confirmación"
+//	@Failure		400			{string}	string							"Solicitud inválida"
+//	@Failure		401			{string}	string							"No autorizado"
+//	@Failure		403			{string}	string							"Solo el propietario puede transferir la sala"
+//	@Failure		404			{string}	string							"Sala no encontrada o usuario no es miembro"
+//	@Failure		500			{string}	string							"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/ownership/transfer [post]
+func (h *RoomAdminHandler) RequestOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req models.TransferOwnershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == "" {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	transfer, err := h.RoomService.RequestOwnershipTransfer(roomID, userID, req.UserID)
+	if err != nil {
+		writeRoleError(w, "Error transferring ownership: ", err)
+		return
+	}
+
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeOwnershipTransferRequested, pws.OwnershipPayload{
+		RoomID:          roomID,
+		PreviousOwnerID: transfer.FromUserID,
+		NewOwnerID:      transfer.ToUserID,
+	})
+
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// AcceptOwnershipTransfer confirma una transferencia de propiedad dirigida al usuario actual
+//
+//	@Summary		Acepta una transferencia de propiedad
+//	@Description	El destinatario confirma la transferencia y pasa a ser el propietario de la sala. El propietario anterior queda como admin
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string	true	"ID de la sala"
+//	@Success		200		{string}	string	"Propiedad transferida"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		404		{string}	string	"No hay transferencia pendiente para el usuario"
+//	@Failure		410		{string}	string	"La transferencia expiró"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/ownership/accept [post]
+func (h *RoomAdminHandler) AcceptOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	transfer, err := h.RoomService.AcceptOwnershipTransfer(roomID, userID)
+	if err != nil {
+		writeRoleError(w, "Error accepting ownership transfer: ", err)
+		return
+	}
+
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeOwnershipTransferred, pws.OwnershipPayload{
+		RoomID:          roomID,
+		PreviousOwnerID: transfer.FromUserID,
+		NewOwnerID:      transfer.ToUserID,
+	})
+
+	response := map[string]string{
+		"message": "Ownership transferred successfully",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// CancelOwnershipTransfer cancela o rechaza una transferencia de propiedad pendiente
+//
+//	@Summary		Cancela una transferencia de propiedad
+//	@Description	El propietario cancela la transferencia pendiente o el destinatario la rechaza
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string	true	"ID de la sala"
+//	@Success		200		{string}	string	"Transferencia cancelada"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		403		{string}	string	"No permitido cancelar la transferencia"
+//	@Failure		404		{string}	string	"No hay transferencia pendiente"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/ownership/transfer [delete]
+func (h *RoomAdminHandler) CancelOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	transfer, err := h.RoomService.CancelOwnershipTransfer(roomID, userID)
+	if err != nil {
+		writeRoleError(w, "Error cancelling ownership transfer: ", err)
+		return
+	}
+
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeOwnershipTransferCancelled, pws.OwnershipPayload{
+		RoomID:          roomID,
+		PreviousOwnerID: transfer.FromUserID,
+		NewOwnerID:      transfer.ToUserID,
+	})
+
+	response := map[string]string{
+		"message": "Ownership transfer cancelled",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeRoleError traduce los errores de gestión de roles a su código HTTP
+func writeRoleError(w http.ResponseWriter, prefix string, err error) {
+	switch err.Error() {
+	case "room not found",
+		"role not found",
+		"user is not a member of the room",
+		"user is not an admin of the room",
+		"no pending ownership transfer for this user":
+		http.Error(w, prefix+err.Error(), http.StatusNotFound)
+	case "user is not allowed to manage roles",
+		"user is not allowed to manage this role",
+		"only the room owner can transfer ownership",
+		"only the room owner or the recipient can cancel the transfer",
+		"the room owner's role cannot be changed",
+		"the owner role cannot be modified":
+		http.Error(w, prefix+err.Error(), http.StatusForbidden)
+	case "user already has this role":
+		http.Error(w, prefix+err.Error(), http.StatusConflict)
+	case "user already owns the room",
+		"ownership can only be transferred",
+		"invalid role name":
+		http.Error(w, prefix+err.Error(), http.StatusBadRequest)
+	case "ownership transfer has expired":
+		http.Error(w, prefix+err.Error(), http.StatusGone)
+	default:
+		if strings.HasPrefix(err.Error(), "invalid capability") {
+			http.Error(w, prefix+err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// RoomStatsHandler maneja las peticiones de estadísticas de actividad de las salas
+type RoomStatsHandler struct {
+	RoomStatsService *services.RoomStatsService
+}
+
+// NewRoomStatsHandler crea una nueva instancia de RoomStatsHandler
+func NewRoomStatsHandler(roomStatsService *services.RoomStatsService) *RoomStatsHandler {
+	return &RoomStatsHandler{
+		RoomStatsService: roomStatsService,
+	}
+}
+
+// GetRoomStats obtiene las estadísticas de actividad de una sala
+//
+//	@Summary		Estadísticas de una sala
+//	@Description	Devuelve mensajes y remitentes activos por día, usuarios más activos, horas pico (UTC), crecimiento de miembros y reportes. Se calculan a partir de contadores diarios. Requiere la capacidad manage_room
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string						true	"ID de la sala"
+//	@Param			days	query		int							false	"Número de días hasta hoy (máximo 90)"	default(30)
+//	@Success		200		{object}	models.RoomStatsResponse	"Estadísticas de la sala"
+//	@Failure		401		{string}	string						"No autorizado"
+//	@Failure		403		{string}	string						"No permitido ver las estadísticas"
+//	@Failure		404		{string}	string						"Sala no encontrada"
+//	@Failure		500		{string}	string						"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/stats [get]
+func (h *RoomStatsHandler) GetRoomStats(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	days := 0
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		if parsedDays, err := strconv.Atoi(daysStr); err == nil {
+			days = parsedDays
+		}
+	}
+
+	stats, err := h.RoomStatsService.GetRoomStats(roomID, userID, days)
+	if err != nil {
+		switch err.Error() {
+		case "room not found":
+			http.Error(w, "Error getting room stats: "+err.Error(), http.StatusNotFound)
+		case "user is not allowed to view room stats":
+			http.Error(w, "Error getting room stats: "+err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, "Error getting room stats: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	json.NewEncoder(w).Encode(stats)
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Parchat/backend/internal/models"
+	pws "github.com/Parchat/backend/internal/pkg/websocket"
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// SanctionHandler maneja los silencios y baneos de los miembros de las salas
+type SanctionHandler struct {
+	SanctionService *services.SanctionService
+	Hub             *pws.Hub
+}
+
+// NewSanctionHandler crea una nueva instancia de SanctionHandler
+func NewSanctionHandler(sanctionService *services.SanctionService, hub *pws.Hub) *SanctionHandler {
+	return &SanctionHandler{
+		SanctionService: sanctionService,
+		Hub:             hub,
+	}
+}
+
+// MuteMember silencia a un miembro de una sala durante un tiempo
+//
+//	@Summary		Silencia a un miembro
+//	@Description	Impide a un miembro escribir en la sala durante durationSeconds (máximo 30 días). Un nuevo silencio reemplaza al anterior. Requiere la capacidad moderate_members y solo el propietario puede silenciar a otros moderadores
+//	@Tags			Moderation
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string						true	"ID de la sala"
+//	@Param			userId	path		string						true	"ID del miembro"
+//	@Param			mute	body		models.MuteMemberRequest	true	"Duración y motivo"
+//	@Success		200		{object}	models.RoomSanction			"Miembro silenciado"
+//	@Failure		400		{string}	string						"Duración inválida"
+//	@Failure		401		{string}	string						"No autorizado"
+//	@Failure		403		{string}	string						"No permitido silenciar a este usuario"
+//	@Failure		404		{string}	string						"Sala no encontrada o el usuario no es miembro"
+//	@Failure		500		{string}	string						"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/members/{userId}/mute [post]
+func (h *SanctionHandler) MuteMember(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	targetID := chi.URLParam(r, "userId")
+
+	var req models.MuteMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	sanction, err := h.SanctionService.MuteMember(roomID, userID, targetID, &req)
+	if err != nil {
+		writeSanctionError(w, "Error muting member: ", err)
+		return
+	}
+
+	broadcastMute(h.Hub, sanction)
+
+	json.NewEncoder(w).Encode(sanction)
+}
+
+// UnmuteMember levanta el silencio de un miembro
+//
+//	@Summary		Levanta el silencio de un miembro
+//	@Description	Permite de nuevo escribir a un miembro silenciado. Requiere la capacidad moderate_members
+//	@Tags			Moderation
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string				true	"ID de la sala"
+//	@Param			userId	path		string				true	"ID del miembro"
+//	@Success		200		{object}	models.RoomSanction	"Silencio levantado"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		403		{string}	string				"No permitido moderar miembros"
+//	@Failure		404		{string}	string				"Sala no encontrada o el usuario no está silenciado"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/members/{userId}/mute [delete]
+func (h *SanctionHandler) UnmuteMember(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	targetID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	sanction, err := h.SanctionService.UnmuteMember(roomID, userID, targetID)
+	if err != nil {
+		writeSanctionError(w, "Error unmuting member: ", err)
+		return
+	}
+
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeMemberUnmuted, pws.SanctionPayload{
+		RoomID:  roomID,
+		UserID:  targetID,
+		ActorID: userID,
+	})
+
+	json.NewEncoder(w).Encode(sanction)
+}
+
+// BanUser banea a un usuario de una sala
+//
+//	@Summary		Banea a un usuario
+//	@Description	Saca al usuario de la sala y le impide volver a entrar por cualquier vía, para siempre (durationSeconds 0) o durante un tiempo. Con deleteMessagesHours borra también sus mensajes de las últimas horas (máximo 7 días). Requiere la capacidad moderate_members y solo el propietario puede banear a otros moderadores
+//	@Tags			Moderation
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string					true	"ID de la sala"
+//	@Param			userId	path		string					true	"ID del usuario"
+//	@Param			ban		body		models.BanMemberRequest	true	"Duración, motivo y mensajes a borrar"
+//	@Success		200		{object}	models.RoomSanction		"Usuario baneado"
+//	@Failure		400		{string}	string					"Duración o ventana de borrado inválidas"
+//	@Failure		401		{string}	string					"No autorizado"
+//	@Failure		403		{string}	string					"No permitido banear a este usuario"
+//	@Failure		404		{string}	string					"Sala o usuario no encontrados"
+//	@Failure		500		{string}	string					"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/bans/{userId} [post]
+func (h *SanctionHandler) BanUser(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	targetID := chi.URLParam(r, "userId")
+
+	var req models.BanMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	result, err := h.SanctionService.BanUser(roomID, userID, targetID, &req)
+	if result != nil {
+		// Avisar de los mensajes borrados aunque el borrado no se completara
+		broadcastDeletedMessages(h.Hub, roomID, userID, result.DeletedMessageIDs)
+	}
+	if err != nil {
+		writeSanctionError(w, "Error banning user: ", err)
+		return
+	}
+
+	broadcastBan(h.Hub, result.Sanction, result.WasMember)
+
+	json.NewEncoder(w).Encode(result.Sanction)
+}
+
+// UnbanUser levanta el baneo de un usuario
+//
+//	@Summary		Levanta el baneo de un usuario
+//	@Description	Permite de nuevo al usuario entrar en la sala. No lo vuelve a añadir como miembro. Requiere la capacidad moderate_members
+//	@Tags			Moderation
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string				true	"ID de la sala"
+//	@Param			userId	path		string				true	"ID del usuario"
+//	@Success		200		{object}	models.RoomSanction	"Baneo levantado"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		403		{string}	string				"No permitido moderar miembros"
+//	@Failure		404		{string}	string				"Sala no encontrada o el usuario no está baneado"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/bans/{userId} [delete]
+func (h *SanctionHandler) UnbanUser(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+	targetID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	sanction, err := h.SanctionService.UnbanUser(roomID, userID, targetID)
+	if err != nil {
+		writeSanctionError(w, "Error unbanning user: ", err)
+		return
+	}
+
+	h.Hub.BroadcastToRoom(roomID, pws.MessageTypeMemberUnbanned, pws.SanctionPayload{
+		RoomID:  roomID,
+		UserID:  targetID,
+		ActorID: userID,
+	})
+
+	json.NewEncoder(w).Encode(sanction)
+}
+
+// GetRoomSanctions lista el historial de sanciones de una sala
+//
+//	@Summary		Historial de sanciones
+//	@Description	Devuelve los últimos 100 silencios y baneos de la sala, activos o no, del más reciente al más antiguo. Requiere la capacidad moderate_members
+//	@Tags			Moderation
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			roomId	path		string				true	"ID de la sala"
+//	@Success		200		{array}		models.RoomSanction	"Sanciones de la sala"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		403		{string}	string				"No permitido moderar miembros"
+//	@Failure		404		{string}	string				"Sala no encontrada"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/rooms/{roomId}/sanctions [get]
+func (h *SanctionHandler) GetRoomSanctions(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "roomId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	sanctions, err := h.SanctionService.GetRoomSanctions(roomID, userID)
+	if err != nil {
+		writeSanctionError(w, "Error getting sanctions: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(sanctions)
+}
+
+// broadcastMute avisa a la sala de que se silenció a un miembro
+func broadcastMute(hub *pws.Hub, sanction *models.RoomSanction) {
+	hub.BroadcastToRoom(sanction.RoomID, pws.MessageTypeMemberMuted, pws.SanctionPayload{
+		RoomID:    sanction.RoomID,
+		UserID:    sanction.UserID,
+		ActorID:   sanction.ActorID,
+		ExpiresAt: sanction.ExpiresAt,
+	})
+}
+
+// broadcastBan avisa a la sala de que se baneó a un usuario y cancela sus suscripciones a la sala
+func broadcastBan(hub *pws.Hub, sanction *models.RoomSanction, wasMember bool) {
+	// Avisar a la sala (incluido el baneado) antes de cancelar sus suscripciones
+	if wasMember {
+		hub.BroadcastToRoom(sanction.RoomID, pws.MessageTypeUserLeave, pws.UserLeavePayload{
+			RoomID:    sanction.RoomID,
+			UserID:    sanction.UserID,
+			Reason:    "banned",
+			RemovedBy: sanction.ActorID,
+		})
+	}
+	hub.RemoveUserFromRoom(sanction.UserID, sanction.RoomID)
+}
+
+// broadcastDeletedMessages avisa a la sala de los mensajes que borró un moderador
+func broadcastDeletedMessages(hub *pws.Hub, roomID, actorID string, messageIDs []string) {
+	for _, messageID := range messageIDs {
+		hub.BroadcastToRoom(roomID, pws.MessageTypeMessageDeleted, pws.MessageDeletedPayload{
+			RoomID:    roomID,
+			MessageID: messageID,
+			DeletedBy: actorID,
+		})
+	}
+}
+
+// writeSanctionError traduce los errores de las sanciones a su código HTTP
+func writeSanctionError(w http.ResponseWriter, prefix string, err error) {
+	switch err.Error() {
+	case "room not found", "user not found", "user is not a member of the room", "user is not muted", "user is not banned":
+		http.Error(w, prefix+err.Error(), http.StatusNotFound)
+	case "user is not allowed to moderate members", "users cannot sanction themselves",
+		"the room owner cannot be sanctioned", "only the room owner can sanction moderators":
+		http.Error(w, prefix+err.Error(), http.StatusForbidden)
+	case "invalid mute duration", "invalid ban duration", "invalid message deletion window":
+		http.Error(w, prefix+err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
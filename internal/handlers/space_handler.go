@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// SpaceHandler maneja las peticiones relacionadas con los espacios y sus canales
+type SpaceHandler struct {
+	SpaceService *services.SpaceService
+}
+
+// NewSpaceHandler crea una nueva instancia de SpaceHandler
+func NewSpaceHandler(spaceService *services.SpaceService) *SpaceHandler {
+	return &SpaceHandler{
+		SpaceService: spaceService,
+	}
+}
+
+// CreateSpace crea un nuevo espacio
+//
+//	@Summary		Crea un espacio
+//	@Description	Crea un espacio que agrupa canales con miembros y admins compartidos. El creador es su propietario
+//	@Tags			Spaces
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			space	body		models.CreateSpaceRequest	true	"Datos del espacio"
+//	@Success		201		{object}	models.Space				"Espacio creado"
+//	@Failure		400		{string}	string						"Solicitud inválida"
+//	@Failure		401		{string}	string						"No autorizado"
+//	@Failure		500		{string}	string						"Error interno del servidor"
+//	@Router			/chat/spaces [post]
+func (h *SpaceHandler) CreateSpace(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateSpaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	space, err := h.SpaceService.CreateSpace(userID, &req)
+	if err != nil {
+		writeSpaceError(w, "Error creating space: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(space)
+}
+
+// ListSpaces lista los espacios
+//
+//	@Summary		Lista los espacios
+//	@Description	Devuelve los espacios con más miembros
+//	@Tags			Spaces
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			limit	query		int					false	"Número de espacios (máximo 50)"	default(20)
+//	@Success		200		{array}		models.SpaceSummary	"Espacios"
+//	@Failure		401		{string}	string				"No autorizado"
+//	@Failure		500		{string}	string				"Error interno del servidor"
+//	@Router			/chat/spaces [get]
+func (h *SpaceHandler) ListSpaces(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil {
+			limit = parsedLimit
+		}
+	}
+
+	spaces, err := h.SpaceService.ListSpaces(limit)
+	if err != nil {
+		writeSpaceError(w, "Error getting spaces: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(spaces)
+}
+
+// GetUserSpaces lista los espacios del usuario actual
+//
+//	@Summary		Espacios del usuario
+//	@Description	Devuelve los espacios a los que pertenece el usuario autenticado
+//	@Tags			Spaces
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		models.SpaceSummary	"Espacios del usuario"
+//	@Failure		401	{string}	string				"No autorizado"
+//	@Failure		500	{string}	string				"Error interno del servidor"
+//	@Router			/chat/spaces/me [get]
+func (h *SpaceHandler) GetUserSpaces(w http.ResponseWriter, r *http.Request) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	spaces, err := h.SpaceService.GetUserSpaces(userID)
+	if err != nil {
+		writeSpaceError(w, "Error getting spaces: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(spaces)
+}
+
+// GetSpace obtiene un espacio y sus canales
+//
+//	@Summary		Obtiene un espacio
+//	@Description	Devuelve el espacio, el rol del usuario en él y los canales que puede ver: los públicos y los privados a los que tiene acceso
+//	@Tags			Spaces
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			spaceId	path		string					true	"ID del espacio"
+//	@Success		200		{object}	models.SpaceResponse	"Espacio y canales"
+//	@Failure		401		{string}	string					"No autorizado"
+//	@Failure		404		{string}	string					"Espacio no encontrado"
+//	@Failure		500		{string}	string					"Error interno del servidor"
+//	@Router			/chat/spaces/{spaceId} [get]
+func (h *SpaceHandler) GetSpace(w http.ResponseWriter, r *http.Request) {
+	spaceID := chi.URLParam(r, "spaceId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	space, err := h.SpaceService.GetSpaceDetails(spaceID, userID)
+	if err != nil {
+		writeSpaceError(w, "Error getting space: ", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(space)
+}
+
+// JoinSpace une al usuario a un espacio
+//
+//	@Summary		Unirse a un espacio
+//	@Description	Une al usuario autenticado al espacio y a todos sus canales públicos
+//	@Tags			Spaces
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			spaceId	path		string	true	"ID del espacio"
+//	@Success		200		{string}	string	"Usuario unido al espacio"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		404		{string}	string	"Espacio no encontrado"
+//	@Failure		409		{string}	string	"El usuario ya es miembro"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/spaces/{spaceId}/join [post]
+func (h *SpaceHandler) JoinSpace(w http.ResponseWriter, r *http.Request) {
+	spaceID := chi.URLParam(r, "spaceId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.SpaceService.JoinSpace(spaceID, userID); err != nil {
+		writeSpaceError(w, "Error joining space: ", err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Successfully joined the space",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// LeaveSpace saca al usuario de un espacio
+//
+//	@Summary		Abandonar un espacio
+//	@Description	Saca al usuario autenticado del espacio y de todos sus canales. El propietario no puede abandonarlo
+//	@Tags			Spaces
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			spaceId	path		string	true	"ID del espacio"
+//	@Success		200		{string}	string	"Usuario salió del espacio"
+//	@Failure		400		{string}	string	"El propietario no puede abandonar el espacio"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		404		{string}	string	"Espacio no encontrado o el usuario no es miembro"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/chat/spaces/{spaceId}/leave [post]
+func (h *SpaceHandler) LeaveSpace(w http.ResponseWriter, r *http.Request) {
+	spaceID := chi.URLParam(r, "spaceId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.SpaceService.LeaveSpace(spaceID, userID); err != nil {
+		writeSpaceError(w, "Error leaving space: ", err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Successfully left the space",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateChannel crea un canal dentro de un espacio
+//
+//	@Summary		Crea un canal en un espacio
+//	@Description	Crea una sala dentro del espacio. Los canales públicos incluyen a todos los miembros del espacio; los privados solo a los usuarios indicados. Solo los admins del espacio pueden crear canales
+//	@Tags			Spaces
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			spaceId	path		string						true	"ID del espacio"
+//	@Param			channel	body		models.CreateRoomRequest	true	"Datos del canal"
+//	@Success		201		{object}	models.Room					"Canal creado"
+//	@Failure		400		{string}	string						"Solicitud inválida"
+//	@Failure		401		{string}	string						"No autorizado"
+//	@Failure		403		{string}	string						"No permitido gestionar el espacio"
+//	@Failure		404		{string}	string						"Espacio no encontrado"
+//	@Failure		500		{string}	string						"Error interno del servidor"
+//	@Router			/chat/spaces/{spaceId}/channels [post]
+func (h *SpaceHandler) CreateChannel(w http.ResponseWriter, r *http.Request) {
+	spaceID := chi.URLParam(r, "spaceId")
+
+	var req models.CreateRoomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	room, err := h.SpaceService.CreateChannel(spaceID, userID, &req)
+	if err != nil {
+		writeSpaceError(w, "Error creating channel: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(room)
+}
+
+// SetMemberRole cambia el rol de un miembro del espacio
+//
+//	@Summary		Cambia el rol de un miembro del espacio
+//	@Description	Asigna el rol "admin" o "member". Los admins del espacio son admins en todos sus canales. Solo el propietario puede cambiar roles. El cambio se anota en el registro de auditoría de cada canal
+//	@Tags			Spaces
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			spaceId	path		string						true	"ID del espacio"
+//	@Param			userId	path		string						true	"ID del miembro"
+//	@Param			role	body		models.AssignRoleRequest	true	"Rol a asignar"
+//	@Success		200		{string}	string						"Rol actualizado"
+//	@Failure		400		{string}	string						"Rol inválido"
+//	@Failure		401		{string}	string						"No autorizado"
+//	@Failure		403		{string}	string						"Solo el propietario puede cambiar roles"
+//	@Failure		404		{string}	string						"Espacio no encontrado o el usuario no es miembro"
+//	@Failure		500		{string}	string						"Error interno del servidor"
+//	@Router			/chat/spaces/{spaceId}/members/{userId}/role [put]
+func (h *SpaceHandler) SetMemberRole(w http.ResponseWriter, r *http.Request) {
+	spaceID := chi.URLParam(r, "spaceId")
+	targetID := chi.URLParam(r, "userId")
+
+	var req models.AssignRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.SpaceService.SetMemberRole(spaceID, userID, targetID, req.Role); err != nil {
+		writeSpaceError(w, "Error changing role: ", err)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Role updated successfully",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeSpaceError traduce los errores de los espacios a su código HTTP
+func writeSpaceError(w http.ResponseWriter, prefix string, err error) {
+	switch err.Error() {
+	case "space not found", "user is not a member of the space":
+		http.Error(w, prefix+err.Error(), http.StatusNotFound)
+	case "user is not allowed to manage the space", "only the space owner can change roles":
+		http.Error(w, prefix+err.Error(), http.StatusForbidden)
+	case "user is already a member of the space":
+		http.Error(w, prefix+err.Error(), http.StatusConflict)
+	case "space name is required", "space owner cannot leave the space", "invalid role",
+		"cannot change the role of the space owner", "category is too long", "too many categories":
+		http.Error(w, prefix+err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
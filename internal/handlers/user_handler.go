@@ -2,22 +2,27 @@ package handlers
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
 
 	"github.com/Parchat/backend/internal/models"
+	pws "github.com/Parchat/backend/internal/pkg/websocket"
 	"github.com/Parchat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
 )
 
 type UserHandler struct {
 	UserService *services.UserService
 	AuthService *services.AuthService
+	Hub         *pws.Hub
 }
 
 // NewUserHandler crea una nueva instancia de UserHandler
-func NewUserHandler(userService *services.UserService, authService *services.AuthService) *UserHandler {
+func NewUserHandler(userService *services.UserService, authService *services.AuthService, hub *pws.Hub) *UserHandler {
 	return &UserHandler{
 		UserService: userService,
 		AuthService: authService,
+		Hub:         hub,
 	}
 }
 
@@ -76,3 +81,353 @@ func (h *UserHandler) EnsureUserExists(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(user)
 }
+
+// DeleteAccount elimina la cuenta del usuario actual
+//
+//	@Summary		Elimina la cuenta del usuario
+//	@Description	Traspasa la propiedad de las salas del usuario autenticado al admin más antiguo de cada una, marca su cuenta como eliminada y deshabilita su acceso, en ese orden para que se pueda reintentar si algún paso falla
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{string}	string	"Cuenta eliminada"
+//	@Failure		401	{string}	string	"No autorizado"
+//	@Failure		500	{string}	string	"Error interno del servidor. La petición se puede repetir"
+//	@Router			/user/me [delete]
+func (h *UserHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	changes, err := h.UserService.DeleteAccount(r.Context(), userID)
+
+	// Notificar a los miembros conectados de cada sala traspasada o eliminada, también si un paso
+	// posterior falló, porque esos cambios ya están hechos
+	for _, change := range changes {
+		if change.NewOwnerID == "" {
+			h.Hub.BroadcastToRoom(change.RoomID, pws.MessageTypeRoomDeleted, map[string]string{"roomId": change.RoomID})
+			continue
+		}
+		h.Hub.BroadcastToRoom(change.RoomID, pws.MessageTypeOwnershipTransferred, pws.OwnershipPayload{
+			RoomID:          change.RoomID,
+			PreviousOwnerID: change.PreviousOwnerID,
+			NewOwnerID:      change.NewOwnerID,
+		})
+	}
+
+	if err != nil {
+		http.Error(w, "Error deleting account: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Account deleted successfully",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetBlockedUsers lista los usuarios bloqueados por el usuario actual
+//
+//	@Summary		Usuarios bloqueados
+//	@Description	Devuelve los usuarios que el usuario autenticado ha bloqueado
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		models.BlockedUser	"Usuarios bloqueados"
+//	@Failure		401	{string}	string				"No autorizado"
+//	@Failure		500	{string}	string				"Error interno del servidor"
+//	@Router			/user/blocks [get]
+func (h *UserHandler) GetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	blocked, err := h.UserService.GetBlockedUsers(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "Error getting blocked users: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(blocked)
+}
+
+// BlockUser bloquea a un usuario
+//
+//	@Summary		Bloquea a un usuario
+//	@Description	El usuario bloqueado no puede abrir chats directos con el usuario actual ni enviarle mensajes directos, y sus mensajes en las salas dejan de mostrarse al usuario actual, tanto en el historial como en tiempo real
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			userId	path		string	true	"ID del usuario a bloquear"
+//	@Success		200		{string}	string	"Usuario bloqueado"
+//	@Failure		400		{string}	string	"No puedes bloquearte a ti mismo"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		404		{string}	string	"Usuario no encontrado"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/user/blocks/{userId} [post]
+func (h *UserHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	targetID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.UserService.BlockUser(r.Context(), userID, targetID); err != nil {
+		switch err.Error() {
+		case "cannot block yourself":
+			http.Error(w, "Error blocking user: "+err.Error(), http.StatusBadRequest)
+		case "user not found":
+			http.Error(w, "Error blocking user: "+err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "Error blocking user: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.syncBlockedUsers(r, userID)
+
+	response := map[string]string{
+		"message": "User blocked successfully",
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// UnblockUser desbloquea a un usuario
+//
+//	@Summary		Desbloquea a un usuario
+//	@Description	Quita a un usuario de la lista de bloqueados del usuario actual
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			userId	path		string	true	"ID del usuario a desbloquear"
+//	@Success		200		{string}	string	"Usuario desbloqueado"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/user/blocks/{userId} [delete]
+func (h *UserHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	targetID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.UserService.UnblockUser(r.Context(), userID, targetID); err != nil {
+		http.Error(w, "Error unblocking user: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.syncBlockedUsers(r, userID)
+
+	response := map[string]string{
+		"message": "User unblocked successfully",
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// syncBlockedUsers aplica la lista de bloqueados actualizada a las conexiones WebSocket abiertas del usuario
+func (h *UserHandler) syncBlockedUsers(r *http.Request, userID string) {
+	blockedIDs, err := h.UserService.GetBlockedUserIDs(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error reloading blocked users for %s: %v", userID, err)
+		return
+	}
+
+	h.Hub.UpdateBlockedUsers(userID, blockedIDs)
+}
+
+// GetContacts lista los contactos del usuario actual
+//
+//	@Summary		Contactos
+//	@Description	Devuelve los contactos del usuario autenticado. Sus contactos pueden abrirle chats directos sin pasar por las solicitudes de mensajes
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		models.Contact	"Contactos"
+//	@Failure		401	{string}	string			"No autorizado"
+//	@Failure		500	{string}	string			"Error interno del servidor"
+//	@Router			/user/contacts [get]
+func (h *UserHandler) GetContacts(w http.ResponseWriter, r *http.Request) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	contacts, err := h.UserService.GetContacts(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "Error getting contacts: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(contacts)
+}
+
+// AddContact añade un usuario a los contactos
+//
+//	@Summary		Añade un contacto
+//	@Description	Añade un usuario a los contactos del usuario autenticado
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			userId	path		string	true	"ID del usuario"
+//	@Success		200		{string}	string	"Contacto añadido"
+//	@Failure		400		{string}	string	"No puedes añadirte a ti mismo"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		404		{string}	string	"Usuario no encontrado"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/user/contacts/{userId} [post]
+func (h *UserHandler) AddContact(w http.ResponseWriter, r *http.Request) {
+	contactID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.UserService.AddContact(r.Context(), userID, contactID); err != nil {
+		switch err.Error() {
+		case "cannot add yourself as a contact":
+			http.Error(w, "Error adding contact: "+err.Error(), http.StatusBadRequest)
+		case "user not found":
+			http.Error(w, "Error adding contact: "+err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "Error adding contact: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := map[string]string{
+		"message": "Contact added successfully",
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// RemoveContact quita un usuario de los contactos
+//
+//	@Summary		Quita un contacto
+//	@Description	Quita un usuario de los contactos del usuario autenticado
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			userId	path		string	true	"ID del usuario"
+//	@Success		200		{string}	string	"Contacto eliminado"
+//	@Failure		401		{string}	string	"No autorizado"
+//	@Failure		500		{string}	string	"Error interno del servidor"
+//	@Router			/user/contacts/{userId} [delete]
+func (h *UserHandler) RemoveContact(w http.ResponseWriter, r *http.Request) {
+	contactID := chi.URLParam(r, "userId")
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.UserService.RemoveContact(r.Context(), userID, contactID); err != nil {
+		http.Error(w, "Error removing contact: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Contact removed successfully",
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetPrivacySettings obtiene los ajustes de privacidad del usuario actual
+//
+//	@Summary		Ajustes de privacidad
+//	@Description	Devuelve quién puede abrir chats directos con el usuario autenticado
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	models.PrivacySettings	"Ajustes de privacidad"
+//	@Failure		401	{string}	string					"No autorizado"
+//	@Failure		500	{string}	string					"Error interno del servidor"
+//	@Router			/user/privacy [get]
+func (h *UserHandler) GetPrivacySettings(w http.ResponseWriter, r *http.Request) {
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	settings, err := h.UserService.GetPrivacySettings(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "Error getting privacy settings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdatePrivacySettings cambia los ajustes de privacidad del usuario actual
+//
+//	@Summary		Cambia los ajustes de privacidad
+//	@Description	Elige quién puede abrir chats directos con el usuario: everyone, shared_rooms (quien comparte alguna sala o es contacto), contacts o nobody. Los chats existentes siguen siendo legibles, pero los nuevos envíos se validan con el ajuste nuevo
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			settings	body		models.UpdatePrivacySettingsRequest	true	"Ajustes de privacidad"
+//	@Success		200			{object}	models.PrivacySettings				"Ajustes actualizados"
+//	@Failure		400			{string}	string								"Ajuste inválido"
+//	@Failure		401			{string}	string								"No autorizado"
+//	@Failure		500			{string}	string								"Error interno del servidor"
+//	@Router			/user/privacy [put]
+func (h *UserHandler) UpdatePrivacySettings(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdatePrivacySettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Obtener el ID del usuario del contexto
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	settings, err := h.UserService.UpdatePrivacySettings(r.Context(), userID, &req)
+	if err != nil {
+		if err.Error() == "invalid dm privacy" {
+			http.Error(w, "Error updating privacy settings: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Error updating privacy settings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(settings)
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Acciones del registro de auditoría
+const (
+	AuditReportCreated                = "report_created"
+	AuditReportsCleared               = "reports_cleared"
+	AuditReportsResolved              = "reports_resolved"
+	AuditMessageDeleted               = "message_deleted"
+	AuditMemberRemoved                = "member_removed"
+	AuditMemberMuted                  = "member_muted"
+	AuditMemberUnmuted                = "member_unmuted"
+	AuditMemberBanned                 = "member_banned"
+	AuditMemberUnbanned               = "member_unbanned"
+	AuditSanctionExpired              = "sanction_expired"
+	AuditRoleAssigned                 = "role_assigned"
+	AuditRoleUpdated                  = "role_updated"
+	AuditRoleDeleted                  = "role_deleted"
+	AuditSpaceRoleAssigned            = "space_role_assigned"
+	AuditOwnershipTransferRequested   = "ownership_transfer_requested"
+	AuditOwnershipTransferCancelled   = "ownership_transfer_cancelled"
+	AuditOwnershipTransferred         = "ownership_transferred"
+	AuditOwnershipInheritedOnDeletion = "ownership_inherited_on_deletion"
+	AuditRoomUpdated                  = "room_updated"
+	AuditContentFilterUpdated         = "content_filter_updated"
+	AuditRoomDeleted                  = "room_deleted"
+	AuditInviteCreated                = "invite_created"
+	AuditInviteRevoked                = "invite_revoked"
+	AuditJoinRequestApproved          = "join_request_approved"
+	AuditJoinRequestRejected          = "join_request_rejected"
+)
+
+// Tipos de objetivo de una entrada del registro de auditoría
+const (
+	AuditTargetUser    = "user"
+	AuditTargetMessage = "message"
+	AuditTargetRole    = "role"
+	AuditTargetRoom    = "room"
+	AuditTargetInvite  = "invite"
+)
+
+// Límites de las consultas del registro de auditoría
+const (
+	DefaultAuditLogLimit = 50
+	MaxAuditLogLimit     = 200
+	MaxAuditLogExport    = 10000
+)
+
+// AuditEntry es una acción de moderación o administración. El registro solo admite añadir entradas
+type AuditEntry struct {
+	ID         string                 `json:"id" firestore:"id"`
+	RoomID     string                 `json:"roomId,omitempty" firestore:"roomId"`
+	Action     string                 `json:"action" firestore:"action"`
+	ActorID    string                 `json:"actorId,omitempty" firestore:"actorId"` // Vacío si la acción la hizo el sistema
+	TargetType string                 `json:"targetType" firestore:"targetType"`     // "user", "message", "role", "room" o "invite"
+	TargetID   string                 `json:"targetId" firestore:"targetId"`
+	Before     map[string]interface{} `json:"before,omitempty" firestore:"before,omitempty"` // Estado anterior de lo que cambió
+	After      map[string]interface{} `json:"after,omitempty" firestore:"after,omitempty"`   // Estado posterior de lo que cambió
+	Reason     string                 `json:"reason,omitempty" firestore:"reason,omitempty"`
+	CreatedAt  time.Time              `json:"createdAt" firestore:"createdAt"`
+}
+
+// AuditLogQuery son los filtros de una consulta del registro de auditoría
+type AuditLogQuery struct {
+	RoomID   string
+	ActorID  string
+	TargetID string
+	Action   string
+	From     time.Time // Incluida; sin valor no limita
+	To       time.Time // Excluida; sin valor no limita
+	Limit    int
+	Cursor   string
+}
+
+// PaginatedAuditLogResponse representa una página del registro de auditoría
+type PaginatedAuditLogResponse struct {
+	Entries    []AuditEntry `json:"entries"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+	HasMore    bool         `json:"hasMore"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+const (
+	// BookmarkChatTypeRoom indica que el mensaje guardado pertenece a una sala
+	BookmarkChatTypeRoom = "room"
+	// BookmarkChatTypeDirect indica que el mensaje guardado pertenece a un chat directo
+	BookmarkChatTypeDirect = "direct"
+)
+
+// Bookmark representa un mensaje guardado por un usuario junto con una nota privada
+type Bookmark struct {
+	ID        string    `json:"id" firestore:"id"`
+	UserID    string    `json:"userId" firestore:"userId"`
+	MessageID string    `json:"messageId" firestore:"messageId"`
+	ChatID    string    `json:"chatId" firestore:"chatId"`     // ID de la sala o del chat directo
+	ChatType  string    `json:"chatType" firestore:"chatType"` // "room" o "direct"
+	Note      string    `json:"note" firestore:"note"`
+	Message   *Message  `json:"message,omitempty" firestore:"message,omitempty"` // Copia del mensaje al momento de guardarlo
+	CreatedAt time.Time `json:"createdAt" firestore:"createdAt"`
+}
+
+// CreateBookmarkRequest representa la petición para guardar un mensaje
+type CreateBookmarkRequest struct {
+	MessageID string `json:"messageId"`
+	ChatID    string `json:"chatId"`
+	ChatType  string `json:"chatType"` // "room" o "direct"
+	Note      string `json:"note,omitempty"`
+}
+
+// BookmarkResponse es un mensaje guardado junto con su disponibilidad y el enlace a su conversación
+type BookmarkResponse struct {
+	Bookmark
+	Available bool   `json:"available"` // false si el mensaje fue eliminado o el usuario ya no pertenece a la conversación
+	Link      string `json:"link"`
+}
+
+// PaginatedBookmarksResponse representa una respuesta paginada de mensajes guardados
+type PaginatedBookmarksResponse struct {
+	Bookmarks  []BookmarkResponse `json:"bookmarks"`
+	NextCursor string             `json:"nextCursor,omitempty"`
+	HasMore    bool               `json:"hasMore"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// Límites del filtro de contenido
+const (
+	// MaxContentFilterRules es cuántas reglas propias puede tener el filtro de una sala
+	MaxContentFilterRules = 200
+)
+
+// ContentFilterRule es una regla del filtro de contenido de una sala
+type ContentFilterRule struct {
+	Type   string `json:"type" firestore:"type"`     // "word", "regex" o "domain"
+	Value  string `json:"value" firestore:"value"`   // Palabra o frase, expresión regular o dominio
+	Action string `json:"action" firestore:"action"` // "reject", "mask" o "flag"
+}
+
+// ContentFilterSettings es la configuración del filtro de contenido de una sala
+type ContentFilterSettings struct {
+	// DefaultListAction es la acción para la lista de palabrotas por defecto. Vacío la desactiva
+	DefaultListAction string              `json:"defaultListAction" firestore:"defaultListAction"`
+	Rules             []ContentFilterRule `json:"rules" firestore:"rules"`
+}
+
+// UpdateContentFilterRequest represents the request body for replacing a room's content filter
+type UpdateContentFilterRequest struct {
+	DefaultListAction string              `json:"defaultListAction"` // "reject", "mask", "flag" or empty to disable the default list
+	Rules             []ContentFilterRule `json:"rules"`
+}
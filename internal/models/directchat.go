@@ -1,14 +1,141 @@
 package models
 
-import "time"
+import (
+	"sort"
+	"strings"
+	"time"
+)
 
-// DirectChat representa un chat directo entre dos usuarios
+// Límites de los chats directos de grupo
+const (
+	MinGroupDirectChatParticipants = 3
+	MaxGroupDirectChatParticipants = 20
+	MaxDirectChatTitleLength       = 100
+)
+
+// Estados de la solicitud de mensajes de un chat directo. Los chats sin estado están aceptados
+const (
+	MessageRequestPending  = "pending"
+	MessageRequestAccepted = "accepted"
+	MessageRequestDeclined = "declined"
+)
+
+// DirectChat representa un chat directo entre dos usuarios o un chat de grupo entre varios
 type DirectChat struct {
-	ID           string    `json:"id" firestore:"id"`
-	UserIDs      []string  `json:"userIds" firestore:"userIds"`
-	DisplayNames []string  `json:"displayNames" firestore:"displayNames,omitempty"`
-	LastMessage  *Message  `json:"lastMessage,omitempty" firestore:"lastMessage,omitempty"`
-	CreatedAt    time.Time `json:"createdAt" firestore:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt" firestore:"updatedAt"`
-	IsDeleted    bool      `json:"isDeleted" firestore:"isDeleted"`
+	ID           string   `json:"id" firestore:"id"`
+	UserIDs      []string `json:"userIds" firestore:"userIds"`
+	DisplayNames []string `json:"displayNames" firestore:"displayNames,omitempty"`
+	IsGroup      bool     `json:"isGroup" firestore:"isGroup"`
+	Title        string   `json:"title,omitempty" firestore:"title,omitempty"`         // Solo en chats de grupo
+	CreatedBy    string   `json:"createdBy,omitempty" firestore:"createdBy,omitempty"` // Quien abrió el chat; en los de grupo puede quitar participantes
+	// Encrypted indica que el chat usa cifrado de extremo a extremo; una vez activado no se puede desactivar
+	Encrypted bool `json:"encrypted" firestore:"encrypted"`
+	// RequestStatus es el estado de la solicitud si el chat lo abrió alguien que no era contacto del otro usuario
+	RequestStatus string `json:"requestStatus,omitempty" firestore:"requestStatus,omitempty"`
+	RequestedBy   string `json:"requestedBy,omitempty" firestore:"requestedBy,omitempty"` // Usuario que envió la solicitud
+	// PendingInvites son los participantes de un chat de grupo que deben aceptar la invitación porque quien
+	// los añadió no era su contacto, con el ID de quien los añadió
+	PendingInvites map[string]string `json:"pendingInvites,omitempty" firestore:"pendingInvites,omitempty"`
+	// Estado de la conversación para cada participante: archivada, oculta tras borrarla y desde cuándo ve el historial
+	ArchivedBy    []string             `json:"-" firestore:"archivedBy,omitempty"`
+	HiddenBy      []string             `json:"-" firestore:"hiddenBy,omitempty"`
+	ClearedBefore map[string]time.Time `json:"-" firestore:"clearedBefore,omitempty"`
+	// Archived indica si el usuario que consulta sus chats ha archivado este
+	Archived bool `json:"archived" firestore:"-"`
+	// ParticipantKey identifica el conjunto de participantes para reutilizar el chat en lugar de duplicarlo
+	ParticipantKey string    `json:"-" firestore:"participantKey,omitempty"`
+	LastMessage    *Message  `json:"lastMessage,omitempty" firestore:"lastMessage,omitempty"`
+	CreatedAt      time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt" firestore:"updatedAt"`
+	IsDeleted      bool      `json:"isDeleted" firestore:"isDeleted"`
+	// NotificationPreferences son las preferencias del usuario que consulta sus chats
+	NotificationPreferences *NotificationPreference `json:"notificationPreferences,omitempty" firestore:"-"`
+}
+
+// CreateGroupDirectChatRequest represents the request body for creating a group direct chat
+type CreateGroupDirectChatRequest struct {
+	UserIDs []string `json:"userIds"`         // IDs of the other participants; the creator is added automatically
+	Title   string   `json:"title,omitempty"` // Optional title of the group
+}
+
+// UpdateDirectChatRequest represents the request body for changing a group direct chat
+type UpdateDirectChatRequest struct {
+	Title *string `json:"title,omitempty"` // An empty title removes it
+}
+
+// AddDirectChatParticipantRequest represents the request body for adding a participant to a group direct chat
+type AddDirectChatParticipantRequest struct {
+	UserID string `json:"userId"`
+}
+
+// DirectChatParticipantKey devuelve la clave del conjunto de participantes, independiente del orden.
+// Los chats de grupo usan un prefijo propio para no coincidir con un chat entre dos usuarios
+func DirectChatParticipantKey(userIDs []string, isGroup bool) string {
+	sorted := append([]string(nil), userIDs...)
+	sort.Strings(sorted)
+
+	key := strings.Join(sorted, "_")
+	if isGroup {
+		return "group:" + key
+	}
+	return key
+}
+
+// IsRequestFor indica si el chat es una solicitud de mensajes sin aceptar dirigida al usuario. En los chats
+// de grupo lo es mientras el usuario no acepte la invitación
+func (c *DirectChat) IsRequestFor(userID string) bool {
+	if c.IsGroup {
+		_, pending := c.PendingInvites[userID]
+		return pending
+	}
+	return (c.RequestStatus == MessageRequestPending || c.RequestStatus == MessageRequestDeclined) &&
+		c.RequestedBy != userID
+}
+
+// IsParticipant indica si el usuario participa en el chat
+func (c *DirectChat) IsParticipant(userID string) bool {
+	for _, id := range c.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMember indica si el usuario participa en el chat y, en los grupos, ya aceptó la invitación. Quien
+// tiene la invitación pendiente no puede leer el historial, escuchar el chat ni escribir en él
+func (c *DirectChat) IsMember(userID string) bool {
+	_, pending := c.PendingInvites[userID]
+	return !pending && c.IsParticipant(userID)
+}
+
+// AcceptedUserIDs devuelve los participantes que no tienen una invitación al grupo pendiente
+func (c *DirectChat) AcceptedUserIDs() []string {
+	userIDs := make([]string, 0, len(c.UserIDs))
+	for _, userID := range c.UserIDs {
+		if _, pending := c.PendingInvites[userID]; !pending {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs
+}
+
+// IsArchivedBy indica si el usuario ha archivado el chat
+func (c *DirectChat) IsArchivedBy(userID string) bool {
+	for _, id := range c.ArchivedBy {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHiddenBy indica si el usuario ha borrado el chat y no ha llegado ningún mensaje desde entonces
+func (c *DirectChat) IsHiddenBy(userID string) bool {
+	for _, id := range c.HiddenBy {
+		if id == userID {
+			return true
+		}
+	}
+	return false
 }
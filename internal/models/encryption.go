@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Límites de las claves de cifrado de extremo a extremo
+const (
+	MaxOneTimePreKeysPerUpload = 100
+	MaxDevicesPerUser          = 10
+	// Paquetes de claves que un usuario puede pedir de otro por ventana, para que nadie agote sus claves de un solo uso
+	MaxKeyBundleRequests   = 10
+	KeyBundleRequestWindow = time.Hour
+)
+
+// Motivos de los avisos de cambio de claves
+const (
+	KeyChangeDeviceAdded     = "device_added"
+	KeyChangeIdentityChanged = "identity_changed"
+	KeyChangeDeviceRemoved   = "device_removed"
+)
+
+// SignedPreKey es la clave previa firmada de un dispositivo. El servidor no verifica la firma
+type SignedPreKey struct {
+	KeyID     int    `json:"keyId" firestore:"keyId"`
+	PublicKey string `json:"publicKey" firestore:"publicKey"` // Base64
+	Signature string `json:"signature" firestore:"signature"` // Base64
+}
+
+// OneTimePreKey es una clave previa de un solo uso. Se entrega como mucho una vez y luego se borra
+type OneTimePreKey struct {
+	KeyID     int    `json:"keyId" firestore:"keyId"`
+	PublicKey string `json:"publicKey" firestore:"publicKey"` // Base64
+}
+
+// DeviceKeys son las claves públicas de un dispositivo de un usuario.
+// Se guardan en users/{uid}/devices/{deviceId} y sus claves de un solo uso en la subcolección prekeys
+type DeviceKeys struct {
+	DeviceID     string       `json:"deviceId" firestore:"deviceId"`
+	UserID       string       `json:"userId" firestore:"userId"`
+	IdentityKey  string       `json:"identityKey" firestore:"identityKey"` // Base64
+	SignedPreKey SignedPreKey `json:"signedPreKey" firestore:"signedPreKey"`
+	// IdentityChangedAt es la última vez que cambió la clave de identidad, para que los demás vuelvan a verificarla
+	IdentityChangedAt time.Time `json:"identityChangedAt" firestore:"identityChangedAt"`
+	CreatedAt         time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt" firestore:"updatedAt"`
+	// RemainingPreKeys es el número de claves de un solo uso que quedan; solo se muestra al propietario
+	RemainingPreKeys int `json:"remainingPreKeys" firestore:"-"`
+}
+
+// PublishDeviceKeysRequest represents the request body for publishing the keys of a device
+type PublishDeviceKeysRequest struct {
+	IdentityKey    string          `json:"identityKey"`
+	SignedPreKey   SignedPreKey    `json:"signedPreKey"`
+	OneTimePreKeys []OneTimePreKey `json:"oneTimePreKeys,omitempty"`
+}
+
+// UploadPreKeysRequest represents the request body for adding one-time prekeys to a device
+type UploadPreKeysRequest struct {
+	OneTimePreKeys []OneTimePreKey `json:"oneTimePreKeys"`
+}
+
+// DeviceKeyBundle son las claves que necesita otro usuario para iniciar una sesión cifrada con un dispositivo
+type DeviceKeyBundle struct {
+	DeviceID          string         `json:"deviceId"`
+	IdentityKey       string         `json:"identityKey"`
+	SignedPreKey      SignedPreKey   `json:"signedPreKey"`
+	OneTimePreKey     *OneTimePreKey `json:"oneTimePreKey,omitempty"` // Vacía si el dispositivo se quedó sin claves de un solo uso
+	IdentityChangedAt time.Time      `json:"identityChangedAt"`
+}
+
+// KeyBundleResponse son los paquetes de claves de todos los dispositivos de un usuario
+type KeyBundleResponse struct {
+	UserID  string            `json:"userId"`
+	Devices []DeviceKeyBundle `json:"devices"`
+}
+
+// EncryptedEnvelope es el texto cifrado de un mensaje para un dispositivo. El servidor lo guarda y
+// lo reenvía sin leerlo
+type EncryptedEnvelope struct {
+	RecipientID    string `json:"recipientId" firestore:"recipientId"`
+	DeviceID       string `json:"deviceId" firestore:"deviceId"`
+	SenderDeviceID string `json:"senderDeviceId" firestore:"senderDeviceId"`
+	Type           int    `json:"type" firestore:"type"`             // Tipo de mensaje del protocolo, definido por los clientes
+	Ciphertext     string `json:"ciphertext" firestore:"ciphertext"` // Base64
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Invite representa un código de invitación para unirse a una sala
+type Invite struct {
+	Code      string     `json:"code" firestore:"code"`
+	RoomID    string     `json:"roomId" firestore:"roomId"`
+	CreatedBy string     `json:"createdBy" firestore:"createdBy"`
+	CreatedAt time.Time  `json:"createdAt" firestore:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" firestore:"expiresAt,omitempty"` // Sin fecha no expira
+	MaxUses   int        `json:"maxUses" firestore:"maxUses"`                         // 0 significa usos ilimitados
+	Uses      int        `json:"uses" firestore:"uses"`
+	Revoked   bool       `json:"revoked" firestore:"revoked"`
+	Link      string     `json:"link,omitempty" firestore:"-"` // Enlace para compartir, no se guarda en Firestore
+}
+
+// CreateInviteRequest represents the request body for creating an invite
+type CreateInviteRequest struct {
+	ExpiresInHours int `json:"expiresInHours,omitempty"` // 0 significa que no expira
+	MaxUses        int `json:"maxUses,omitempty"`        // 0 significa usos ilimitados
+}
+
+// InvitePreview es la información pública de una sala que se muestra con un código de invitación válido
+type InvitePreview struct {
+	Code        string     `json:"code"`
+	RoomID      string     `json:"roomId"`
+	RoomName    string     `json:"roomName"`
+	ImageURL    string     `json:"imageUrl,omitempty"`
+	MemberCount int        `json:"memberCount"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
+// IsExpired indica si la invitación ya expiró
+func (i *Invite) IsExpired(now time.Time) bool {
+	return i.ExpiresAt != nil && !now.Before(*i.ExpiresAt)
+}
+
+// IsExhausted indica si la invitación alcanzó su número máximo de usos
+func (i *Invite) IsExhausted() bool {
+	return i.MaxUses > 0 && i.Uses >= i.MaxUses
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Estados de una solicitud para unirse a una sala
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+// MaxJoinRequestMessageLength es la longitud máxima del mensaje de una solicitud
+const MaxJoinRequestMessageLength = 500
+
+// JoinRequest representa la solicitud de un usuario para unirse a una sala privada.
+// Se guarda en rooms/{roomId}/joinRequests/{userId}, por lo que cada usuario tiene como mucho una por sala
+type JoinRequest struct {
+	UserID      string     `json:"userId" firestore:"userId"`
+	RoomID      string     `json:"roomId" firestore:"roomId"`
+	Message     string     `json:"message,omitempty" firestore:"message,omitempty"`
+	Status      string     `json:"status" firestore:"status"`
+	CreatedAt   time.Time  `json:"createdAt" firestore:"createdAt"`
+	ReviewedBy  string     `json:"reviewedBy,omitempty" firestore:"reviewedBy,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewedAt,omitempty" firestore:"reviewedAt,omitempty"`
+	DisplayName string     `json:"displayName,omitempty" firestore:"-"` // Excluido de Firestore
+}
+
+// CreateJoinRequestRequest represents the request body for requesting to join a room
+type CreateJoinRequestRequest struct {
+	Message string `json:"message,omitempty"`
+}
@@ -11,7 +11,12 @@ type Message struct {
 	CreatedAt   time.Time `json:"createdAt" firestore:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt" firestore:"updatedAt"`
 	IsDeleted   bool      `json:"isDeleted" firestore:"isDeleted"`
-	DisplayName string    `json:"displayName,omitempty" firestore:"-"` // Excluido de Firestore
+	ThreadID    string    `json:"threadId,omitempty" firestore:"threadId,omitempty"` // Mensaje raíz si es una respuesta en un hilo
+	Mentions    []string  `json:"mentions,omitempty" firestore:"mentions,omitempty"` // IDs de los usuarios mencionados
+	DisplayName string    `json:"displayName,omitempty" firestore:"-"`               // Excluido de Firestore
+	// Encrypted indica que el mensaje es de un chat cifrado: Content va vacío y el texto viaja en Envelopes
+	Encrypted bool                `json:"encrypted,omitempty" firestore:"encrypted,omitempty"`
+	Envelopes []EncryptedEnvelope `json:"envelopes,omitempty" firestore:"envelopes,omitempty"`
 }
 
 // MessageResponse es la respuesta que incluye un mensaje y el nombre del usuario que lo envió
@@ -26,3 +31,9 @@ type PaginatedMessagesResponse struct {
 	NextCursor string            `json:"nextCursor,omitempty"`
 	HasMore    bool              `json:"hasMore"`
 }
+
+// ThreadResponse representa un hilo: el mensaje raíz y sus respuestas
+type ThreadResponse struct {
+	Root    Message   `json:"root"`
+	Replies []Message `json:"replies"`
+}
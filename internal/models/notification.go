@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Tipos de conversación a los que se aplican las preferencias de notificación
+const (
+	ConversationTypeRoom   = "room"
+	ConversationTypeDirect = "direct"
+)
+
+// NotificationPreference son las preferencias de notificación de un usuario para una conversación.
+// Se guardan en users/{uid}/notificationPrefs/{tipo}_{id}; sin documento se usan los valores por defecto
+type NotificationPreference struct {
+	ConversationType string     `json:"conversationType" firestore:"conversationType"`
+	ConversationID   string     `json:"conversationId" firestore:"conversationId"`
+	Muted            bool       `json:"muted" firestore:"muted"`
+	MutedUntil       *time.Time `json:"mutedUntil,omitempty" firestore:"mutedUntil,omitempty"` // Vacío si se silenció sin fecha de fin
+	MentionsOnly     bool       `json:"mentionsOnly" firestore:"mentionsOnly"`
+	Sound            bool       `json:"sound" firestore:"sound"`
+	Push             bool       `json:"push" firestore:"push"`
+	UpdatedAt        time.Time  `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// UpdateNotificationPreferenceRequest represents the request body for changing the notification
+// preferences of a conversation. Only the fields that are present are updated
+type UpdateNotificationPreferenceRequest struct {
+	Muted        *bool      `json:"muted,omitempty"`
+	MutedUntil   *time.Time `json:"mutedUntil,omitempty"` // Silencia hasta esa fecha; implica muted
+	MentionsOnly *bool      `json:"mentionsOnly,omitempty"`
+	Sound        *bool      `json:"sound,omitempty"`
+	Push         *bool      `json:"push,omitempty"`
+}
+
+// DefaultNotificationPreference devuelve las preferencias de una conversación que el usuario no ha configurado
+func DefaultNotificationPreference(conversationType, conversationID string) NotificationPreference {
+	return NotificationPreference{
+		ConversationType: conversationType,
+		ConversationID:   conversationID,
+		Sound:            true,
+		Push:             true,
+	}
+}
+
+// NotificationPreferenceKey devuelve el ID del documento de las preferencias de una conversación
+func NotificationPreferenceKey(conversationType, conversationID string) string {
+	return conversationType + "_" + conversationID
+}
+
+// IsMuted indica si la conversación está silenciada en un momento dado. Un silencio con fecha
+// de fin deja de aplicar al llegar a ella
+func (p *NotificationPreference) IsMuted(now time.Time) bool {
+	if !p.Muted {
+		return false
+	}
+	return p.MutedUntil == nil || now.Before(*p.MutedUntil)
+}
+
+// ShouldNotify indica si un evento de la conversación debe notificarse al usuario
+func (p *NotificationPreference) ShouldNotify(now time.Time, mentioned bool) bool {
+	if p.IsMuted(now) {
+		return false
+	}
+	return !p.MentionsOnly || mentioned
+}
@@ -0,0 +1,186 @@
+package models
+
+// Capability es una acción que un rol puede realizar dentro de una sala
+type Capability string
+
+const (
+	CapSendMessages   Capability = "send_messages"
+	CapDeleteMessages Capability = "delete_messages" // Eliminar mensajes de otros usuarios
+	CapPinMessages    Capability = "pin_messages"
+	CapInviteMembers  Capability = "invite_members"
+	CapRemoveMembers  Capability = "remove_members"
+	CapManageReports  Capability = "manage_reports"
+	CapManageRoles    Capability = "manage_roles"
+	CapManageRoom     Capability = "manage_room"      // Editar la configuración de la sala
+	CapBypassSlowMode Capability = "bypass_slow_mode" // Escribir sin esperar el intervalo del modo lento
+	// Escribir en el timeline principal de las salas de solo anuncios
+	CapPostAnnouncements Capability = "post_announcements"
+	// Silenciar y banear miembros temporal o permanentemente
+	CapModerateMembers Capability = "moderate_members"
+)
+
+// Roles predefinidos de todas las salas
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// RoomRole es un rol con nombre y el conjunto de capacidades que otorga
+type RoomRole struct {
+	Name         string       `json:"name" firestore:"name"`
+	Capabilities []Capability `json:"capabilities" firestore:"capabilities"`
+}
+
+// RoomRoleRequest represents the request body for creating or updating a room role
+type RoomRoleRequest struct {
+	Capabilities []Capability `json:"capabilities"`
+}
+
+// AssignRoleRequest represents the request body for changing a member's role
+type AssignRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// AllCapabilities devuelve todas las capacidades conocidas
+func AllCapabilities() []Capability {
+	return []Capability{
+		CapSendMessages,
+		CapDeleteMessages,
+		CapPinMessages,
+		CapInviteMembers,
+		CapRemoveMembers,
+		CapManageReports,
+		CapManageRoles,
+		CapManageRoom,
+		CapBypassSlowMode,
+		CapPostAnnouncements,
+		CapModerateMembers,
+	}
+}
+
+// IsValidCapability indica si una capacidad es conocida
+func IsValidCapability(capability Capability) bool {
+	for _, c := range AllCapabilities() {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRoomRoles devuelve los roles predefinidos. Las salas pueden redefinir "admin" y "member",
+// pero el rol "owner" siempre tiene todas las capacidades
+func DefaultRoomRoles() map[string]RoomRole {
+	return map[string]RoomRole{
+		RoleOwner: {
+			Name:         RoleOwner,
+			Capabilities: AllCapabilities(),
+		},
+		RoleAdmin: {
+			Name: RoleAdmin,
+			Capabilities: []Capability{
+				CapSendMessages,
+				CapDeleteMessages,
+				CapPinMessages,
+				CapInviteMembers,
+				CapRemoveMembers,
+				CapManageReports,
+				CapManageRoom,
+				CapBypassSlowMode,
+				CapPostAnnouncements,
+				CapModerateMembers,
+			},
+		},
+		RoleMember: {
+			Name:         RoleMember,
+			Capabilities: []Capability{CapSendMessages},
+		},
+	}
+}
+
+// Has indica si el rol otorga una capacidad
+func (r RoomRole) Has(capability Capability) bool {
+	for _, c := range r.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleDefinition devuelve la definición efectiva de un rol en la sala
+func (r *Room) RoleDefinition(name string) (RoomRole, bool) {
+	defaults := DefaultRoomRoles()
+	if name == RoleOwner {
+		return defaults[RoleOwner], true
+	}
+
+	if role, ok := r.Roles[name]; ok {
+		return role, true
+	}
+
+	role, ok := defaults[name]
+	return role, ok
+}
+
+// EffectiveRoles devuelve los roles predefinidos junto con los roles propios de la sala
+func (r *Room) EffectiveRoles() map[string]RoomRole {
+	roles := DefaultRoomRoles()
+	for name, role := range r.Roles {
+		if name != RoleOwner {
+			roles[name] = role
+		}
+	}
+	return roles
+}
+
+// RoleOf devuelve el rol de un usuario en la sala, o una cadena vacía si no pertenece a ella.
+// Las salas sin roles asignados usan las listas de miembros y admins, y los admins
+// del espacio de un canal son admins en él aunque no sean miembros
+func (r *Room) RoleOf(userID string) string {
+	if r.OwnerID == userID {
+		return RoleOwner
+	}
+
+	if containsString(r.SpaceAdmins, userID) {
+		return RoleAdmin
+	}
+
+	isAdmin := containsString(r.Admins, userID)
+	if !isAdmin && !containsString(r.Members, userID) {
+		return ""
+	}
+
+	if role, ok := r.MemberRoles[userID]; ok && role != RoleOwner {
+		if _, defined := r.RoleDefinition(role); defined {
+			return role
+		}
+	}
+
+	if isAdmin {
+		return RoleAdmin
+	}
+	return RoleMember
+}
+
+// HasCapability indica si el rol del usuario en la sala le otorga una capacidad
+func (r *Room) HasCapability(userID string, capability Capability) bool {
+	role := r.RoleOf(userID)
+	if role == "" {
+		return false
+	}
+
+	definition, ok := r.RoleDefinition(role)
+	return ok && definition.Has(capability)
+}
+
+// containsString verifica si un slice contiene un valor
+func containsString(slice []string, value string) bool {
+	for _, item := range slice {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -8,9 +8,73 @@ type Report struct {
 	MessageID  string    `json:"messageId" firestore:"messageId"`
 	RoomID     string    `json:"roomId" firestore:"roomId"`
 	ReportedID string    `json:"reportedId" firestore:"reportedId"` // ID of the user being reported
-	ReporterID string    `json:"reporterId" firestore:"reporterId"` // ID of the user making the report
+	ReporterID string    `json:"reporterId" firestore:"reporterId"` // ID of the user making the report, empty for system reports
 	Reason     string    `json:"reason" firestore:"reason"`
 	CreatedAt  time.Time `json:"createdAt" firestore:"createdAt"`
+	// Weight is how much the report counts toward the reported user's score, based on the reporter
+	Weight float64 `json:"weight" firestore:"weight"`
+	// MessageContent is a copy of the reported message, kept for review even if the message is deleted
+	MessageContent string     `json:"messageContent" firestore:"messageContent"`
+	Status         string     `json:"status" firestore:"status"`
+	ActionID       string     `json:"actionId,omitempty" firestore:"actionId,omitempty"` // Moderation action that resolved the report
+	ResolvedBy     string     `json:"resolvedBy,omitempty" firestore:"resolvedBy,omitempty"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty" firestore:"resolvedAt,omitempty"`
+	// System is true for reports filed automatically by the content filter
+	System       bool   `json:"system,omitempty" firestore:"system,omitempty"`
+	ReporterName string `json:"reporterName,omitempty" firestore:"-"`
+	ReportedName string `json:"reportedName,omitempty" firestore:"-"`
+}
+
+// Report statuses. Every report starts open and a moderation action dismisses or actions it
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusDismissed = "dismissed"
+	ReportStatusActioned  = "actioned"
+)
+
+// Moderation actions that resolve reports. Dismiss leaves the reports dismissed and the rest actioned
+const (
+	ModerationActionDismiss       = "dismiss"
+	ModerationActionDeleteMessage = "delete_message"
+	ModerationActionWarn          = "warn"
+	ModerationActionMute          = "mute"
+	ModerationActionBan           = "ban"
+)
+
+// MaxReportsPerResolution is how many reports a single moderation action can resolve
+const MaxReportsPerResolution = 100
+
+// ModerationAction is a moderator's resolution of one or more reports against a user in a room
+type ModerationAction struct {
+	ID        string    `json:"id" firestore:"id"`
+	RoomID    string    `json:"roomId" firestore:"roomId"`
+	Type      string    `json:"type" firestore:"type"`
+	ActorID   string    `json:"actorId" firestore:"actorId"`
+	TargetID  string    `json:"targetId" firestore:"targetId"` // ID of the reported user
+	ReportIDs []string  `json:"reportIds" firestore:"reportIds"`
+	Reason    string    `json:"reason,omitempty" firestore:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt" firestore:"createdAt"`
+	// DeletedMessageIDs are the messages removed by delete_message or by a ban
+	DeletedMessageIDs []string `json:"deletedMessageIds,omitempty" firestore:"deletedMessageIds,omitempty"`
+	// SanctionID is the mute or ban issued by the action
+	SanctionID string `json:"sanctionId,omitempty" firestore:"sanctionId,omitempty"`
+}
+
+// ResolveReportsRequest represents the request to resolve reports with a moderation action.
+// All the reports must be open and against the same user, and delete_message needs them on the same message
+type ResolveReportsRequest struct {
+	ReportIDs           []string `json:"reportIds"`
+	Action              string   `json:"action"` // dismiss, delete_message, warn, mute or ban
+	Reason              string   `json:"reason,omitempty"`
+	DurationSeconds     int      `json:"durationSeconds,omitempty"`     // Mute or ban duration. 0 bans permanently
+	DeleteMessagesHours int      `json:"deleteMessagesHours,omitempty"` // Ban only: also delete the user's recent messages
+}
+
+// PaginatedReportsResponse represents a page of the moderation queue
+type PaginatedReportsResponse struct {
+	Reports    []Report `json:"reports"`
+	NextCursor string   `json:"nextCursor,omitempty"`
+	HasMore    bool     `json:"hasMore"`
 }
 
 // ReportRequest represents the request to report a message
@@ -24,6 +88,8 @@ type BannedUserResponse struct {
 	UserID      string `json:"userId"`
 	DisplayName string `json:"displayName"`
 	ReportCount int    `json:"reportCount"`
+	// ReportScore is the sum of the weights of the reports within the room's window
+	ReportScore float64 `json:"reportScore"`
 }
 
 // BannedUsersResponse represents a list of banned users in a room
@@ -34,4 +100,100 @@ type BannedUsersResponse struct {
 // ClearReportRequest represents the request to clear reports for a user in a room
 type ClearReportRequest struct {
 	UserID string `json:"userId"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Report thresholds and windows. Rooms that have not configured them use the defaults
+const (
+	DefaultReportThreshold   = 3.0
+	DefaultReportWindowHours = 7 * 24
+	MinReportThreshold       = 1.0
+	MaxReportThreshold       = 1000.0
+	MaxReportWindowHours     = 365 * 24
+)
+
+// Report weights. Reports from new accounts and from users whose reports are often dismissed count less
+const (
+	// MinReportsForTrackRecord is how many reports a user must have filed before their track record is used
+	MinReportsForTrackRecord = 5
+	// MinTrackRecordFactor keeps some weight for reporters with a poor track record
+	MinTrackRecordFactor = 0.25
+)
+
+// WeightedReport is a report as it counts toward a user's score in a room. Reports older than the
+// room's window no longer count
+type WeightedReport struct {
+	ReportID  string    `json:"reportId" firestore:"reportId"`
+	Weight    float64   `json:"weight" firestore:"weight"`
+	CreatedAt time.Time `json:"createdAt" firestore:"createdAt"`
+}
+
+// ReporterRecord tracks how a user's reports have been resolved, to weigh their future reports
+type ReporterRecord struct {
+	Filed     int `json:"filed" firestore:"filed"`
+	Dismissed int `json:"dismissed" firestore:"dismissed"` // Reports that moderators cleared
+}
+
+// ReportWeight returns how much a report counts, based on the reporter's account age and track record.
+// The result is between 0 and 1
+func ReportWeight(accountAge time.Duration, record ReporterRecord) float64 {
+	weight := 1.0
+	switch {
+	case accountAge < 24*time.Hour:
+		weight = 0.25
+	case accountAge < 7*24*time.Hour:
+		weight = 0.5
+	case accountAge < 30*24*time.Hour:
+		weight = 0.75
+	}
+
+	if record.Filed >= MinReportsForTrackRecord {
+		factor := 1 - float64(record.Dismissed)/float64(record.Filed)
+		if factor < MinTrackRecordFactor {
+			factor = MinTrackRecordFactor
+		}
+		weight *= factor
+	}
+
+	return weight
+}
+
+// EffectiveReportThreshold returns the room's report threshold, or the default if it has not set one
+func (r *Room) EffectiveReportThreshold() float64 {
+	if r.ReportThreshold <= 0 {
+		return DefaultReportThreshold
+	}
+	return r.ReportThreshold
+}
+
+// ReportWindow returns how long a report counts toward a user's score in the room
+func (r *Room) ReportWindow() time.Duration {
+	hours := r.ReportWindowHours
+	if hours <= 0 {
+		hours = DefaultReportWindowHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// ReportScore returns the sum of the weights of the reports against a user that are still within
+// the room's window, and how many reports that is
+func (r *Room) ReportScore(userID string, now time.Time) (float64, int) {
+	since := now.Add(-r.ReportWindow())
+
+	score := 0.0
+	count := 0
+	for _, report := range r.ReportScores[userID] {
+		if report.CreatedAt.After(since) {
+			score += report.Weight
+			count++
+		}
+	}
+
+	return score, count
+}
+
+// IsOverReportThreshold checks if the reports against a user within the window reach the room's threshold
+func (r *Room) IsOverReportThreshold(userID string, now time.Time) bool {
+	score, _ := r.ReportScore(userID, now)
+	return score >= r.EffectiveReportThreshold()
 }
@@ -16,7 +16,57 @@ type Room struct {
 	CreatedAt     time.Time      `json:"createdAt" firestore:"createdAt"`
 	UpdatedAt     time.Time      `json:"updatedAt" firestore:"updatedAt"`
 	IsDeleted     bool           `json:"isDeleted" firestore:"isDeleted"`
-	ReportedUsers map[string]int `json:"reportedUsers" firestore:"reportedUsers"` // Map of userID to report count
+	ReportedUsers map[string]int `json:"-" firestore:"reportedUsers,omitempty"` // Obsoleto: contadores sin caducidad, solo se leen para migrarlos a ReportScores
+	// AdminSince guarda cuándo cada admin obtuvo el rol, para elegir al admin más antiguo como sucesor
+	AdminSince        map[string]time.Time `json:"adminSince,omitempty" firestore:"adminSince,omitempty"`
+	OwnershipTransfer *OwnershipTransfer   `json:"ownershipTransfer,omitempty" firestore:"ownershipTransfer,omitempty"`
+	// Roles contiene los roles propios de la sala y las redefiniciones de "admin" y "member"
+	Roles map[string]RoomRole `json:"roles,omitempty" firestore:"roles,omitempty"`
+	// MemberRoles asigna a cada miembro el nombre de su rol
+	MemberRoles map[string]string `json:"memberRoles,omitempty" firestore:"memberRoles,omitempty"`
+	// JoinedAt guarda cuándo se unió cada miembro. Las salas anteriores no lo tienen para sus miembros iniciales
+	JoinedAt map[string]time.Time `json:"joinedAt,omitempty" firestore:"joinedAt,omitempty"`
+	// Categories son las etiquetas con las que se descubre la sala
+	Categories []string `json:"categories,omitempty" firestore:"categories,omitempty"`
+	// MemberCount se mantiene junto a Members para ordenar las salas sin leer sus listas de miembros
+	MemberCount int `json:"memberCount" firestore:"memberCount"`
+	// SlowModeSeconds es el intervalo mínimo entre mensajes de un mismo miembro. 0 desactiva el modo lento
+	SlowModeSeconds int `json:"slowModeSeconds" firestore:"slowModeSeconds"`
+	// AnnouncementOnly limita el timeline principal a quienes tienen la capacidad post_announcements
+	AnnouncementOnly bool `json:"announcementOnly" firestore:"announcementOnly"`
+	// AllowThreadReplies permite a los demás miembros responder en hilos de una sala de solo anuncios
+	AllowThreadReplies bool `json:"allowThreadReplies" firestore:"allowThreadReplies"`
+	// SpaceID es el espacio al que pertenece la sala como canal, vacío si no pertenece a ninguno
+	SpaceID string `json:"spaceId,omitempty" firestore:"spaceId,omitempty"`
+	// SpaceAdmins replica los admins del espacio, que tienen el rol de admin en todos sus canales
+	SpaceAdmins []string `json:"spaceAdmins,omitempty" firestore:"spaceAdmins,omitempty"`
+	// NotificationPreferences son las preferencias del usuario que consulta sus salas
+	NotificationPreferences *NotificationPreference `json:"notificationPreferences,omitempty" firestore:"-"`
+	// SearchTokens contiene los prefijos de las palabras del nombre y la descripción para la búsqueda
+	SearchTokens []string `json:"-" firestore:"searchTokens,omitempty"`
+	// ReportThreshold es la puntuación de reportes a partir de la cual un miembro no puede escribir. 0 usa el valor por defecto
+	ReportThreshold float64 `json:"reportThreshold" firestore:"reportThreshold"`
+	// ReportWindowHours es cuántas horas cuenta cada reporte en la puntuación. 0 usa el valor por defecto
+	ReportWindowHours int `json:"reportWindowHours" firestore:"reportWindowHours"`
+	// ReportScores guarda, por usuario reportado, los reportes que cuentan en su puntuación
+	ReportScores map[string][]WeightedReport `json:"-" firestore:"reportScores,omitempty"`
+	// Mutes y Bans contienen las sanciones activas impuestas por moderadores, por usuario
+	Mutes map[string]RoomSanction `json:"-" firestore:"mutes,omitempty"`
+	Bans  map[string]RoomSanction `json:"-" firestore:"bans,omitempty"`
+	// ContentFilter son las reglas con las que se revisan los mensajes. Solo se muestran a quien gestiona la sala
+	ContentFilter *ContentFilterSettings `json:"-" firestore:"contentFilter,omitempty"`
+}
+
+// OwnershipTransfer representa una transferencia de propiedad pendiente de confirmación por el nuevo propietario
+type OwnershipTransfer struct {
+	FromUserID  string    `json:"fromUserId" firestore:"fromUserId"`
+	ToUserID    string    `json:"toUserId" firestore:"toUserId"`
+	RequestedAt time.Time `json:"requestedAt" firestore:"requestedAt"`
+}
+
+// TransferOwnershipRequest represents the request body for starting an ownership transfer
+type TransferOwnershipRequest struct {
+	UserID string `json:"userId"`
 }
 
 // CreateRoomRequest represents the request body for creating a new chat room
@@ -24,5 +74,27 @@ type CreateRoomRequest struct {
 	Name        string   `json:"name"`
 	Description string   `json:"description,omitempty"`
 	IsPrivate   bool     `json:"isPrivate,omitempty"`
-	UserIDs     []string `json:"userIds,omitempty"` // IDs of users to be added to the room
+	UserIDs     []string `json:"userIds,omitempty"`    // IDs of users to be added to the room
+	Categories  []string `json:"categories,omitempty"` // Tags used to discover the room
 }
+
+// UpdateRoomRequest represents the request body for updating a chat room's settings.
+// Only the fields that are present are updated
+type UpdateRoomRequest struct {
+	Name        *string   `json:"name,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	ImageURL    *string   `json:"imageUrl,omitempty"`
+	IsPrivate   *bool     `json:"isPrivate,omitempty"`
+	Categories  *[]string `json:"categories,omitempty"`
+	// SlowModeSeconds entre 0 (desactivado) y MaxSlowModeSeconds
+	SlowModeSeconds    *int  `json:"slowModeSeconds,omitempty"`
+	AnnouncementOnly   *bool `json:"announcementOnly,omitempty"`
+	AllowThreadReplies *bool `json:"allowThreadReplies,omitempty"`
+	// ReportThreshold entre MinReportThreshold y MaxReportThreshold
+	ReportThreshold *float64 `json:"reportThreshold,omitempty"`
+	// ReportWindowHours entre 1 y MaxReportWindowHours
+	ReportWindowHours *int `json:"reportWindowHours,omitempty"`
+}
+
+// MaxSlowModeSeconds es el intervalo máximo del modo lento (6 horas)
+const MaxSlowModeSeconds = 6 * 60 * 60
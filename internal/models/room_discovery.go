@@ -0,0 +1,114 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Criterios de orden del descubrimiento de salas
+const (
+	RoomSortActivity = "activity"
+	RoomSortMembers  = "members"
+)
+
+const (
+	// MaxRoomCategories es el número máximo de categorías por sala
+	MaxRoomCategories = 5
+	// MaxRoomCategoryLength es la longitud máxima de una categoría
+	MaxRoomCategoryLength = 30
+	// minSearchTokenLength es la longitud mínima de un prefijo de búsqueda
+	minSearchTokenLength = 2
+	// maxSearchTokenLength limita los prefijos guardados de cada palabra
+	maxSearchTokenLength = 15
+)
+
+// RoomSummary es la información pública de una sala que se muestra en el descubrimiento
+type RoomSummary struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ImageURL    string    `json:"imageUrl"`
+	Categories  []string  `json:"categories"`
+	MemberCount int       `json:"memberCount"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// RoomDiscoveryQuery contiene los filtros del descubrimiento de salas
+type RoomDiscoveryQuery struct {
+	Search   string
+	Category string
+	Sort     string
+	Limit    int
+	Cursor   string
+}
+
+// PaginatedRoomsResponse representa una página del descubrimiento de salas
+type PaginatedRoomsResponse struct {
+	Rooms      []RoomSummary `json:"rooms"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+	HasMore    bool          `json:"hasMore"`
+}
+
+// Summary devuelve la información pública de la sala
+func (r *Room) Summary() RoomSummary {
+	categories := r.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+
+	return RoomSummary{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		ImageURL:    r.ImageURL,
+		Categories:  categories,
+		MemberCount: r.MemberCount,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+// RefreshSearchTokens recalcula los prefijos de búsqueda a partir del nombre y la descripción
+func (r *Room) RefreshSearchTokens() {
+	seen := make(map[string]bool)
+	tokens := []string{}
+	for _, word := range SearchTerms(r.Name + " " + r.Description) {
+		runes := []rune(word)
+		for length := minSearchTokenLength; length <= len(runes) && length <= maxSearchTokenLength; length++ {
+			prefix := string(runes[:length])
+			if !seen[prefix] {
+				seen[prefix] = true
+				tokens = append(tokens, prefix)
+			}
+		}
+	}
+	r.SearchTokens = tokens
+}
+
+// SearchTerms divide un texto en palabras en minúsculas, ignorando las demasiado cortas.
+// Las palabras más largas que un prefijo guardado se recortan para que sigan coincidiendo
+func SearchTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsNumber(c)
+	})
+
+	terms := []string{}
+	for _, word := range words {
+		runes := []rune(word)
+		if len(runes) < minSearchTokenLength {
+			continue
+		}
+		if len(runes) > maxSearchTokenLength {
+			word = string(runes[:maxSearchTokenLength])
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// NormalizeCategory convierte una categoría a su forma canónica: minúsculas y guiones en lugar de espacios
+func NormalizeCategory(category string) string {
+	return strings.Join(strings.Fields(strings.ToLower(category)), "-")
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// RoomMember es una entrada del directorio de miembros de una sala
+type RoomMember struct {
+	UserID      string     `json:"userId"`
+	DisplayName string     `json:"displayName"`
+	PhotoURL    string     `json:"photoUrl"`
+	Role        string     `json:"role"`
+	JoinedAt    *time.Time `json:"joinedAt,omitempty"` // Vacío para los miembros de salas anteriores al registro de fechas
+	Online      bool       `json:"online"`
+	Restricted  bool       `json:"restricted"`            // Alcanzó el límite de reportes y no puede escribir en la sala
+	ReportCount *int       `json:"reportCount,omitempty"` // Reportes dentro de la ventana de la sala. Solo para quienes gestionan reportes
+	ReportScore *float64   `json:"reportScore,omitempty"` // Suma de los pesos de esos reportes. Solo para quienes gestionan reportes
+}
+
+// PaginatedRoomMembersResponse representa una página del directorio de miembros
+type PaginatedRoomMembersResponse struct {
+	Members    []RoomMember `json:"members"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+	HasMore    bool         `json:"hasMore"`
+}
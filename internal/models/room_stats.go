@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+const (
+	// StatsDateLayout es el formato de la fecha (UTC) que identifica cada documento diario de estadísticas
+	StatsDateLayout = "2006-01-02"
+	// DefaultStatsDays es el número de días que cubren las estadísticas por defecto
+	DefaultStatsDays = 30
+	// MaxStatsDays es el número máximo de días que se pueden consultar
+	MaxStatsDays = 90
+	// TopPostersLimit es el número de usuarios que aparecen en el ranking de actividad
+	TopPostersLimit = 10
+)
+
+// RoomDailyStats son los contadores de un día de actividad de una sala. Se guardan en
+// rooms/{roomId}/stats/{YYYY-MM-DD} y se incrementan a medida que ocurren los eventos
+type RoomDailyStats struct {
+	Date          string         `firestore:"date"`
+	Messages      int            `firestore:"messages"`
+	Senders       map[string]int `firestore:"senders"` // Mensajes por usuario
+	Hours         map[string]int `firestore:"hours"`   // Mensajes por hora UTC ("00" a "23")
+	MembersJoined int            `firestore:"membersJoined"`
+	MembersLeft   int            `firestore:"membersLeft"`
+	Reports       int            `firestore:"reports"`
+}
+
+// DailyCount es un valor de una serie diaria
+type DailyCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// HourCount es el número de mensajes enviados en una hora del día (UTC)
+type HourCount struct {
+	Hour     int `json:"hour"`
+	Messages int `json:"messages"`
+}
+
+// TopPoster es un usuario del ranking de actividad de la sala
+type TopPoster struct {
+	UserID      string `json:"userId"`
+	DisplayName string `json:"displayName"`
+	Messages    int    `json:"messages"`
+}
+
+// MemberGrowthPoint es el número de miembros de la sala al final de un día
+type MemberGrowthPoint struct {
+	Date    string `json:"date"`
+	Joined  int    `json:"joined"`
+	Left    int    `json:"left"`
+	Members int    `json:"members"`
+}
+
+// RoomStatsResponse contiene las estadísticas de actividad de una sala en un periodo
+type RoomStatsResponse struct {
+	RoomID              string              `json:"roomId"`
+	From                string              `json:"from"`
+	To                  string              `json:"to"`
+	MessagesPerDay      []DailyCount        `json:"messagesPerDay"`
+	ActiveSendersPerDay []DailyCount        `json:"activeSendersPerDay"`
+	TopPosters          []TopPoster         `json:"topPosters"`
+	PeakHours           []HourCount         `json:"peakHours"` // Ordenadas de más a menos mensajes
+	MemberGrowth        []MemberGrowthPoint `json:"memberGrowth"`
+	ReportsPerDay       []DailyCount        `json:"reportsPerDay"`
+	TotalReports        int                 `json:"totalReports"` // Reportes pendientes de la sala en la colección reports
+	GeneratedAt         time.Time           `json:"generatedAt"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Tipos de sanción que los moderadores pueden imponer en una sala
+const (
+	SanctionMute = "mute"
+	SanctionBan  = "ban"
+)
+
+// Límites de las sanciones
+const (
+	MaxMuteSeconds = 30 * 24 * 60 * 60 // Un silencio dura como mucho 30 días
+	MaxBanSeconds  = 365 * 24 * 60 * 60
+	// MaxBanDeleteMessagesHours es hasta cuántas horas atrás se pueden borrar los mensajes del usuario al banearlo
+	MaxBanDeleteMessagesHours = 7 * 24
+)
+
+// RoomSanction es un silencio o un baneo impuesto por un moderador. Se guarda en rooms/{roomId}/sanctions
+// como historial y, mientras está activa, también en el mapa mutes o bans de la sala
+type RoomSanction struct {
+	ID        string     `json:"id" firestore:"id"`
+	RoomID    string     `json:"roomId" firestore:"roomId"`
+	UserID    string     `json:"userId" firestore:"userId"`
+	Type      string     `json:"type" firestore:"type"` // "mute" o "ban"
+	ActorID   string     `json:"actorId" firestore:"actorId"`
+	Reason    string     `json:"reason,omitempty" firestore:"reason,omitempty"`
+	CreatedAt time.Time  `json:"createdAt" firestore:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" firestore:"expiresAt"` // Vacío si el baneo es permanente
+	// Active es false cuando la sanción caducó o un moderador la levantó
+	Active      bool       `json:"active" firestore:"active"`
+	LiftedBy    string     `json:"liftedBy,omitempty" firestore:"liftedBy,omitempty"` // Vacío si caducó
+	LiftedAt    *time.Time `json:"liftedAt,omitempty" firestore:"liftedAt,omitempty"`
+	DisplayName string     `json:"displayName,omitempty" firestore:"-"`
+}
+
+// MuteMemberRequest represents the request body for muting a room member
+type MuteMemberRequest struct {
+	DurationSeconds int    `json:"durationSeconds"` // Between 1 and MaxMuteSeconds
+	Reason          string `json:"reason,omitempty"`
+}
+
+// BanMemberRequest represents the request body for banning a user from a room
+type BanMemberRequest struct {
+	DurationSeconds int    `json:"durationSeconds,omitempty"` // 0 bans permanently
+	Reason          string `json:"reason,omitempty"`
+	// DeleteMessagesHours deletes the user's messages from the last hours. 0 keeps them
+	DeleteMessagesHours int `json:"deleteMessagesHours,omitempty"`
+}
+
+// IsActiveAt indica si la sanción sigue en vigor en un momento dado. Las sanciones caducadas dejan de
+// aplicar aunque el proceso de caducidad aún no las haya desactivado
+func (s *RoomSanction) IsActiveAt(now time.Time) bool {
+	return s.Active && (s.ExpiresAt == nil || now.Before(*s.ExpiresAt))
+}
+
+// IsMuted indica si el usuario está silenciado en la sala
+func (r *Room) IsMuted(userID string, now time.Time) bool {
+	sanction, ok := r.Mutes[userID]
+	return ok && sanction.IsActiveAt(now)
+}
+
+// IsBanned indica si el usuario está baneado de la sala
+func (r *Room) IsBanned(userID string, now time.Time) bool {
+	sanction, ok := r.Bans[userID]
+	return ok && sanction.IsActiveAt(now)
+}
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Space agrupa varias salas de una comunidad como canales con miembros y admins compartidos.
+// Unirse al espacio da acceso a sus canales públicos y sus admins son admins en todos los canales
+type Space struct {
+	ID          string    `json:"id" firestore:"id"`
+	Name        string    `json:"name" firestore:"name"`
+	Description string    `json:"description" firestore:"description"`
+	ImageURL    string    `json:"imageUrl" firestore:"imageUrl"`
+	OwnerID     string    `json:"ownerId" firestore:"ownerId"`
+	Admins      []string  `json:"admins" firestore:"admins"` // Incluye al propietario
+	Members     []string  `json:"members" firestore:"members"`
+	MemberCount int       `json:"memberCount" firestore:"memberCount"`
+	Channels    []string  `json:"channels" firestore:"channels"` // IDs de las salas del espacio
+	CreatedAt   time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt" firestore:"updatedAt"`
+	IsDeleted   bool      `json:"isDeleted" firestore:"isDeleted"`
+}
+
+// SpaceSummary es la información de un espacio que se muestra en los listados
+type SpaceSummary struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	ImageURL     string    `json:"imageUrl"`
+	OwnerID      string    `json:"ownerId"`
+	MemberCount  int       `json:"memberCount"`
+	ChannelCount int       `json:"channelCount"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// SpaceResponse es un espacio junto con los canales que el usuario puede ver
+type SpaceResponse struct {
+	Space
+	ChannelRooms []RoomSummary `json:"channelRooms"`
+	Role         string        `json:"role,omitempty"` // Rol del usuario en el espacio, vacío si no es miembro
+}
+
+// CreateSpaceRequest represents the request body for creating a space
+type CreateSpaceRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"imageUrl,omitempty"`
+}
+
+// IsAdmin indica si el usuario es propietario o admin del espacio
+func (s *Space) IsAdmin(userID string) bool {
+	return s.OwnerID == userID || containsString(s.Admins, userID)
+}
+
+// IsMember indica si el usuario pertenece al espacio
+func (s *Space) IsMember(userID string) bool {
+	return s.IsAdmin(userID) || containsString(s.Members, userID)
+}
+
+// RoleOf devuelve el rol del usuario en el espacio, o una cadena vacía si no pertenece a él
+func (s *Space) RoleOf(userID string) string {
+	switch {
+	case s.OwnerID == userID:
+		return RoleOwner
+	case containsString(s.Admins, userID):
+		return RoleAdmin
+	case containsString(s.Members, userID):
+		return RoleMember
+	default:
+		return ""
+	}
+}
+
+// Summary devuelve la información del espacio para los listados
+func (s *Space) Summary() SpaceSummary {
+	return SpaceSummary{
+		ID:           s.ID,
+		Name:         s.Name,
+		Description:  s.Description,
+		ImageURL:     s.ImageURL,
+		OwnerID:      s.OwnerID,
+		MemberCount:  s.MemberCount,
+		ChannelCount: len(s.Channels),
+		CreatedAt:    s.CreatedAt,
+	}
+}
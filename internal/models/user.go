@@ -2,16 +2,81 @@ package models
 
 import "time"
 
+// Quién puede abrir chats directos con un usuario. Sin valor equivale a todos
+const (
+	DMPrivacyEveryone    = "everyone"
+	DMPrivacySharedRooms = "shared_rooms" // Usuarios con los que comparte alguna sala, además de sus contactos
+	DMPrivacyContacts    = "contacts"
+	DMPrivacyNobody      = "nobody"
+)
+
 // User representa un usuario en la aplicación
 type User struct {
-	UID         string `json:"uid" firestore:"uid"`
-	Email       string `json:"email" firestore:"email"`
-	DisplayName string `json:"displayName" firestore:"displayName"`
-	PhotoURL    string `json:"photoUrl" firestore:"photoUrl"`
-	Status      string `json:"status" firestore:"status"`
-	LastSeen    string `json:"lastSeen" firestore:"lastSeen"`
-	//BlockedUsers []string  `json:"blockedUsers" firestore:"blockedUsers"`
-	CreatedAt time.Time `json:"createdAt" firestore:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt" firestore:"updatedAt"`
-	IsDeleted bool      `json:"isDeleted" firestore:"isDeleted"`
+	UID          string    `json:"uid" firestore:"uid"`
+	Email        string    `json:"email" firestore:"email"`
+	DisplayName  string    `json:"displayName" firestore:"displayName"`
+	PhotoURL     string    `json:"photoUrl" firestore:"photoUrl"`
+	Status       string    `json:"status" firestore:"status"`
+	LastSeen     string    `json:"lastSeen" firestore:"lastSeen"`
+	BlockedUsers []string  `json:"blockedUsers,omitempty" firestore:"blockedUsers,omitempty"`
+	Contacts     []string  `json:"contacts,omitempty" firestore:"contacts,omitempty"` // Usuarios que pueden abrirle chats directos sin solicitud
+	DMPrivacy    string    `json:"dmPrivacy,omitempty" firestore:"dmPrivacy,omitempty"`
+	CreatedAt    time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt" firestore:"updatedAt"`
+	IsDeleted    bool      `json:"isDeleted" firestore:"isDeleted"`
+	// ReportRecord registra cómo se resolvieron los reportes del usuario, para ponderar los siguientes
+	ReportRecord ReporterRecord `json:"-" firestore:"reportRecord"`
+}
+
+// BlockedUser es un usuario bloqueado tal como se muestra a quien lo bloqueó
+type BlockedUser struct {
+	UserID      string `json:"userId"`
+	DisplayName string `json:"displayName"`
+	PhotoURL    string `json:"photoUrl,omitempty"`
+}
+
+// Contact es un contacto tal como se muestra al usuario que lo añadió
+type Contact struct {
+	UserID      string `json:"userId"`
+	DisplayName string `json:"displayName"`
+	PhotoURL    string `json:"photoUrl,omitempty"`
+}
+
+// PrivacySettings son los ajustes de privacidad del usuario
+type PrivacySettings struct {
+	DMPrivacy string `json:"dmPrivacy"` // everyone, shared_rooms, contacts o nobody
+}
+
+// UpdatePrivacySettingsRequest represents the request body for changing the privacy settings
+type UpdatePrivacySettingsRequest struct {
+	DMPrivacy string `json:"dmPrivacy"` // everyone, shared_rooms, contacts or nobody
+}
+
+// IsValidDMPrivacy indica si un valor es un ajuste de privacidad de chats directos válido
+func IsValidDMPrivacy(value string) bool {
+	switch value {
+	case DMPrivacyEveryone, DMPrivacySharedRooms, DMPrivacyContacts, DMPrivacyNobody:
+		return true
+	}
+	return false
+}
+
+// HasBlocked indica si el usuario ha bloqueado a otro
+func (u *User) HasBlocked(userID string) bool {
+	for _, id := range u.BlockedUsers {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// HasContact indica si el usuario tiene a otro entre sus contactos
+func (u *User) HasContact(userID string) bool {
+	for _, id := range u.Contacts {
+		if id == userID {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,41 @@
+package contentfilter
+
+// defaultWords es la lista de palabrotas e insultos en español e inglés que las salas pueden activar.
+// Se escriben sin disfrazar: la normalización ya detecta las variantes con acentos, números o símbolos
+var defaultWords = []string{
+	// Español
+	"mierda", "mierdas",
+	"puta", "putas", "puto", "putos", "putada", "hijueputa", "hdp",
+	"pendejo", "pendeja", "pendejos", "pendejas",
+	"cabrón", "cabrona", "cabrones",
+	"coño", "joder", "jodido", "jodida",
+	"gilipollas", "capullo",
+	"maricón", "maricones", "marica",
+	"culero", "culera",
+	"verga", "chingar", "chinga", "chingada", "chingado",
+	"malparido", "malparida",
+	"pelotudo", "pelotuda",
+	"mamahuevo", "mamaguevo",
+	"concha de tu madre", "concha tu madre", "ctm",
+	"sudaca", "negrata",
+
+	// Inglés
+	"fuck*", "motherfuck*",
+	"shit", "shits", "shitty", "shithead", "bullshit",
+	"bitch", "bitches", "bitchy", "son of a bitch",
+	"bastard", "bastards",
+	"asshole", "assholes", "dumbass", "jackass",
+	"dickhead", "cocksucker", "douchebag",
+	"cunt", "cunts", "twat", "wanker",
+	"whore", "whores", "slut", "sluts",
+	"faggot", "faggots", "nigger", "niggers", "nigga",
+}
+
+// DefaultRules devuelve las reglas de la lista por defecto con la acción indicada
+func DefaultRules(action string) []Rule {
+	rules := make([]Rule, 0, len(defaultWords))
+	for _, word := range defaultWords {
+		rules = append(rules, Rule{Type: RuleWord, Value: word, Action: action})
+	}
+	return rules
+}
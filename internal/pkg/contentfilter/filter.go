@@ -0,0 +1,288 @@
+// Package contentfilter revisa el texto de los mensajes con listas de palabras, expresiones regulares y
+// dominios de enlaces. Las palabras se comparan normalizadas para detectar las variantes con las que se
+// intenta esquivar el filtro: mayúsculas, acentos, sustituciones como "sh1t" o "@ss", letras alargadas,
+// caracteres invisibles y palabras deletreadas ("f.u.c.k")
+package contentfilter
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Acciones que puede aplicar una regla
+const (
+	ActionReject = "reject" // El mensaje no se envía
+	ActionMask   = "mask"   // El texto que coincide se sustituye por asteriscos
+	ActionFlag   = "flag"   // El mensaje se envía y queda reportado para revisión
+)
+
+// Tipos de regla
+const (
+	RuleWord   = "word"   // Palabra o frase; un "*" final admite cualquier terminación ("fuck*")
+	RuleRegex  = "regex"  // Expresión regular RE2, sin distinguir mayúsculas
+	RuleDomain = "domain" // Dominio de enlaces, incluidos sus subdominios
+)
+
+// MaxRuleLength es la longitud máxima del valor de una regla
+const MaxRuleLength = 200
+
+var (
+	// ErrInvalidRule se devuelve cuando una regla tiene un tipo, una acción o un valor no válidos
+	ErrInvalidRule = errors.New("invalid content filter rule")
+	// ErrInvalidPattern se devuelve cuando una expresión regular no compila
+	ErrInvalidPattern = errors.New("invalid content filter pattern")
+)
+
+// domainPattern valida un dominio ya normalizado
+var domainPattern = regexp.MustCompile(`^(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+(?:[a-z]{2,63}|xn--[a-z0-9-]+)$`)
+
+// linkPattern encuentra enlaces y dominios sueltos en el texto, también con el punto disfrazado como "[.]" o "(.)"
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://)?((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?(?:\.|\[\.\]|\(\.\)))+[a-z]{2,63})\b(?:[:/?#]\S*)?`)
+
+// Rule es una regla del filtro
+type Rule struct {
+	Type   string
+	Value  string
+	Action string
+}
+
+// Match es una coincidencia de una regla en el texto
+type Match struct {
+	Rule  Rule
+	Text  string // Texto original que coincide
+	start int
+	end   int
+}
+
+// Result es el resultado de revisar un texto
+type Result struct {
+	// Content es el texto con las coincidencias de las reglas mask sustituidas por asteriscos
+	Content string
+	Reject  bool // Alguna regla reject coincide
+	Flag    bool // Alguna regla flag coincide
+	Matches []Match
+}
+
+// Masked indica si se ha ocultado parte del texto
+func (r Result) Masked() bool {
+	for _, match := range r.Matches {
+		if match.Rule.Action == ActionMask {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter es un conjunto de reglas compiladas. Se puede usar desde varias goroutines
+type Filter struct {
+	words   []wordRule
+	regexes []regexRule
+	domains []domainRule
+}
+
+type wordRule struct {
+	rule   Rule
+	words  [][]run // Palabras de la frase, agrupadas en rachas
+	prefix bool
+}
+
+type regexRule struct {
+	rule Rule
+	re   *regexp.Regexp
+}
+
+type domainRule struct {
+	rule   Rule
+	domain string
+}
+
+// Normalize valida una regla y devuelve su forma canónica: los dominios sin esquema, ruta ni "www."
+func Normalize(rule Rule) (Rule, error) {
+	rule.Value = strings.TrimSpace(rule.Value)
+	if rule.Value == "" || utf8.RuneCountInString(rule.Value) > MaxRuleLength {
+		return rule, ErrInvalidRule
+	}
+
+	switch rule.Action {
+	case ActionReject, ActionMask, ActionFlag:
+	default:
+		return rule, ErrInvalidRule
+	}
+
+	switch rule.Type {
+	case RuleWord:
+		if len(tokenize(strings.TrimSuffix(rule.Value, "*"))) == 0 {
+			return rule, ErrInvalidRule
+		}
+	case RuleRegex:
+		if _, err := regexp.Compile("(?i)" + rule.Value); err != nil {
+			return rule, ErrInvalidPattern
+		}
+	case RuleDomain:
+		domain := normalizeDomain(rule.Value)
+		if !domainPattern.MatchString(domain) {
+			return rule, ErrInvalidRule
+		}
+		rule.Value = domain
+	default:
+		return rule, ErrInvalidRule
+	}
+
+	return rule, nil
+}
+
+// New compila un conjunto de reglas
+func New(rules []Rule) (*Filter, error) {
+	f := &Filter{}
+
+	for _, rule := range rules {
+		rule, err := Normalize(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		switch rule.Type {
+		case RuleWord:
+			value := rule.Value
+			prefix := strings.HasSuffix(value, "*")
+			compiled := wordRule{rule: rule, prefix: prefix}
+			for _, t := range tokenize(strings.TrimSuffix(value, "*")) {
+				compiled.words = append(compiled.words, runsOf(t.text))
+			}
+			f.words = append(f.words, compiled)
+		case RuleRegex:
+			f.regexes = append(f.regexes, regexRule{rule: rule, re: regexp.MustCompile("(?i)" + rule.Value)})
+		case RuleDomain:
+			f.domains = append(f.domains, domainRule{rule: rule, domain: rule.Value})
+		}
+	}
+
+	return f, nil
+}
+
+// Check revisa un texto con todas las reglas del filtro
+func (f *Filter) Check(text string) Result {
+	result := Result{Content: text}
+	if f == nil || strings.TrimSpace(text) == "" {
+		return result
+	}
+
+	if len(f.words) > 0 {
+		tokens := tokenize(text)
+		tokenRuns := make([][]run, len(tokens))
+		for i, t := range tokens {
+			tokenRuns[i] = runsOf(t.text)
+		}
+		joined := joinedTokens(tokens)
+
+		for _, rule := range f.words {
+			// Las frases se comparan palabra a palabra
+			for i := 0; i+len(rule.words) <= len(tokens); i++ {
+				if matchPhrase(rule, tokenRuns[i:i+len(rule.words)]) {
+					result.add(rule.rule, text, tokens[i].start, tokens[i+len(rule.words)-1].end)
+				}
+			}
+
+			// Las palabras sueltas también se buscan entre las letras deletreadas
+			if len(rule.words) == 1 {
+				for _, t := range joined {
+					if matchRuns(rule.words[0], runsOf(t.text), rule.prefix) {
+						result.add(rule.rule, text, t.start, t.end)
+					}
+				}
+			}
+		}
+	}
+
+	for _, rule := range f.regexes {
+		for _, loc := range rule.re.FindAllStringIndex(text, -1) {
+			if loc[1] > loc[0] {
+				result.add(rule.rule, text, loc[0], loc[1])
+			}
+		}
+	}
+
+	if len(f.domains) > 0 {
+		for _, loc := range linkPattern.FindAllStringSubmatchIndex(text, -1) {
+			host := strings.ToLower(text[loc[2]:loc[3]])
+			host = strings.NewReplacer("[.]", ".", "(.)", ".").Replace(host)
+			for _, rule := range f.domains {
+				if host == rule.domain || strings.HasSuffix(host, "."+rule.domain) {
+					result.add(rule.rule, text, loc[0], loc[1])
+				}
+			}
+		}
+	}
+
+	result.Content = mask(text, result.Matches)
+	return result
+}
+
+// add registra una coincidencia
+func (r *Result) add(rule Rule, text string, start, end int) {
+	switch rule.Action {
+	case ActionReject:
+		r.Reject = true
+	case ActionFlag:
+		r.Flag = true
+	}
+	r.Matches = append(r.Matches, Match{Rule: rule, Text: text[start:end], start: start, end: end})
+}
+
+// matchPhrase comprueba si las palabras del texto coinciden con las de una regla. El "*" solo afecta a la última
+func matchPhrase(rule wordRule, text [][]run) bool {
+	for i, word := range rule.words {
+		if !matchRuns(word, text[i], rule.prefix && i == len(rule.words)-1) {
+			return false
+		}
+	}
+	return true
+}
+
+// mask sustituye por asteriscos los caracteres visibles que coinciden con reglas mask
+func mask(text string, matches []Match) string {
+	masked := make([]bool, len(text))
+	hasMask := false
+	for _, match := range matches {
+		if match.Rule.Action != ActionMask {
+			continue
+		}
+		for i := match.start; i < match.end; i++ {
+			masked[i] = true
+		}
+		hasMask = true
+	}
+	if !hasMask {
+		return text
+	}
+
+	var b strings.Builder
+	for pos, r := range text {
+		if masked[pos] && !unicode.IsSpace(r) {
+			b.WriteRune('*')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// normalizeDomain quita el esquema, la ruta, el puerto y los prefijos "www." y "*." de un dominio
+func normalizeDomain(value string) string {
+	domain := strings.ToLower(strings.TrimSpace(value))
+	domain = strings.TrimPrefix(domain, "https://")
+	domain = strings.TrimPrefix(domain, "http://")
+	if i := strings.IndexAny(domain, "/?#"); i >= 0 {
+		domain = domain[:i]
+	}
+	if host, _, err := net.SplitHostPort(domain); err == nil {
+		domain = host
+	}
+	domain = strings.TrimPrefix(domain, "*.")
+	domain = strings.TrimPrefix(domain, "www.")
+	return strings.TrimSuffix(domain, ".")
+}
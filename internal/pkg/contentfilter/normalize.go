@@ -0,0 +1,191 @@
+package contentfilter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token es una palabra del texto ya normalizada, con su posición en el texto original
+type token struct {
+	text  string // Forma normalizada
+	start int    // Primer byte en el texto original
+	end   int    // Byte siguiente al último en el texto original
+}
+
+// char es un carácter de una palabra junto a su forma normalizada y su posición en el texto original
+type char struct {
+	original   rune
+	normalized rune
+	pos        int
+	size       int
+}
+
+// run es una racha de un mismo carácter, para que "fuuuck" coincida con "fuck"
+type run struct {
+	r rune
+	n int
+}
+
+// leet sustituye los números y símbolos con los que se disfrazan las letras
+var leet = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+	'8': 'b',
+	'@': 'a',
+	'$': 's',
+	'!': 'i',
+	'|': 'i',
+	'+': 't',
+	'€': 'e',
+}
+
+// edgeSymbols son los símbolos de leet que al principio o al final de una palabra son solo puntuación
+var edgeSymbols = map[rune]bool{'!': true, '|': true, '+': true}
+
+// foldedRunes quita los acentos, salvo la ñ, y convierte las letras cirílicas que se confunden con latinas
+var foldedRunes = map[rune]rune{
+	'á': 'a', 'à': 'a', 'ä': 'a', 'â': 'a', 'ã': 'a', 'å': 'a',
+	'é': 'e', 'è': 'e', 'ë': 'e', 'ê': 'e',
+	'í': 'i', 'ì': 'i', 'ï': 'i', 'î': 'i',
+	'ó': 'o', 'ò': 'o', 'ö': 'o', 'ô': 'o', 'õ': 'o',
+	'ú': 'u', 'ù': 'u', 'ü': 'u', 'û': 'u',
+	'ç': 'c',
+	'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'х': 'x', 'у': 'y', 'і': 'i', 'к': 'k', 'т': 't',
+}
+
+// maxJoinedRun es cuántos caracteres sueltos seguidos ("f u c k") se juntan como mucho para buscar palabras
+const maxJoinedRun = 32
+
+// isInvisible indica si un carácter no se ve, como los espacios de ancho cero que se meten dentro de las palabras
+func isInvisible(r rune) bool {
+	switch r {
+	case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff', '\u00ad':
+		return true
+	}
+	return false
+}
+
+// normalizeRune devuelve la forma de un carácter con la que se compara, y si forma parte de una palabra
+func normalizeRune(r rune) (rune, bool) {
+	if unicode.IsLetter(r) {
+		r = unicode.ToLower(r)
+		if folded, ok := foldedRunes[r]; ok {
+			return folded, true
+		}
+		return r, true
+	}
+	if replacement, ok := leet[r]; ok {
+		return replacement, true
+	}
+	if unicode.IsDigit(r) {
+		return r, true
+	}
+	return r, false
+}
+
+// tokenize divide un texto en palabras normalizadas. Las palabras sin ninguna letra, como los números, se descartan
+func tokenize(text string) []token {
+	var tokens []token
+	var chars []char
+
+	flush := func() {
+		// Los signos de exclamación y similares en los extremos son puntuación, no letras disfrazadas
+		for len(chars) > 0 && edgeSymbols[chars[0].original] {
+			chars = chars[1:]
+		}
+		for len(chars) > 0 && edgeSymbols[chars[len(chars)-1].original] {
+			chars = chars[:len(chars)-1]
+		}
+
+		hasLetter := false
+		var b strings.Builder
+		for _, c := range chars {
+			if unicode.IsLetter(c.original) {
+				hasLetter = true
+			}
+			b.WriteRune(c.normalized)
+		}
+		if hasLetter {
+			last := chars[len(chars)-1]
+			tokens = append(tokens, token{text: b.String(), start: chars[0].pos, end: last.pos + last.size})
+		}
+		chars = chars[:0]
+	}
+
+	for pos, r := range text {
+		if isInvisible(r) {
+			continue
+		}
+		normalized, ok := normalizeRune(r)
+		if !ok {
+			flush()
+			continue
+		}
+		chars = append(chars, char{original: r, normalized: normalized, pos: pos, size: utf8.RuneLen(r)})
+	}
+	flush()
+
+	return tokens
+}
+
+// joinedTokens junta las rachas de caracteres sueltos ("f u c k", "f.u.c.k") en todas sus subsecuencias de al
+// menos tres caracteres, para buscar en ellas palabras escritas letra a letra
+func joinedTokens(tokens []token) []token {
+	var joined []token
+
+	for i := 0; i < len(tokens); {
+		j := i
+		for j < len(tokens) && j-i < maxJoinedRun && utf8.RuneCountInString(tokens[j].text) == 1 {
+			j++
+		}
+		if j-i < 3 {
+			i = max(j, i+1)
+			continue
+		}
+
+		for from := i; from < j; from++ {
+			var b strings.Builder
+			for to := from; to < j; to++ {
+				b.WriteString(tokens[to].text)
+				if to-from >= 2 {
+					joined = append(joined, token{text: b.String(), start: tokens[from].start, end: tokens[to].end})
+				}
+			}
+		}
+		i = j
+	}
+
+	return joined
+}
+
+// runsOf agrupa los caracteres repetidos seguidos de una palabra
+func runsOf(word string) []run {
+	var runs []run
+	for _, r := range word {
+		if len(runs) > 0 && runs[len(runs)-1].r == r {
+			runs[len(runs)-1].n++
+			continue
+		}
+		runs = append(runs, run{r: r, n: 1})
+	}
+	return runs
+}
+
+// matchRuns comprueba si una palabra del texto coincide con una de la lista. Se admiten letras alargadas
+// ("fuuuck"), pero no que falten ("as" no coincide con "ass"). Con prefix basta con que la palabra empiece así
+func matchRuns(word, text []run, prefix bool) bool {
+	if len(text) < len(word) || (!prefix && len(text) != len(word)) {
+		return false
+	}
+	for i := range word {
+		if text[i].r != word[i].r || text[i].n < word[i].n {
+			return false
+		}
+	}
+	return true
+}
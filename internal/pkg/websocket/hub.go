@@ -1,15 +1,35 @@
 package websocket
 
 import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
 	"github.com/Parchat/backend/internal/config"
 	"github.com/Parchat/backend/internal/repositories"
+	"github.com/Parchat/backend/internal/services"
 )
 
 // BroadcastMessage contiene la información para transmitir un mensaje
 type BroadcastMessage struct {
 	Message    WebSocketMessage
-	RoomID     string // ID de la sala si es un mensaje de sala
-	DirectChat string // ID del chat directo si es un mensaje directo
+	RoomID     string   // ID de la sala si es un mensaje de sala
+	DirectChat string   // ID del chat directo si es un mensaje directo
+	UserIDs    []string // IDs de los usuarios destinatarios si es un aviso personal
+	SenderID   string   // Autor del mensaje; no se entrega a quienes lo han bloqueado
+}
+
+// blockedUsersUpdate contiene la nueva lista de usuarios bloqueados de un usuario
+type blockedUsersUpdate struct {
+	UserID     string
+	BlockedIDs []string
+}
+
+// roomSubscription identifica las conexiones de un usuario a una sala o a un chat directo
+type roomSubscription struct {
+	UserID string
+	RoomID string
 }
 
 // Hub mantiene el conjunto de clientes activos y transmite mensajes a los clientes
@@ -17,6 +37,10 @@ type Hub struct {
 	// Clientes registrados
 	clients map[*Client]bool
 
+	// Número de conexiones abiertas por usuario, para consultar la presencia desde otros goroutines
+	online   map[string]int
+	onlineMu sync.RWMutex
+
 	// Registra a un nuevo cliente
 	Register chan *Client
 
@@ -29,35 +53,84 @@ type Hub struct {
 	// Canal para transmitir mensajes a chats directos
 	BroadcastDirect chan BroadcastMessage
 
+	// Canal para enviar avisos a todas las conexiones de usuarios concretos
+	sendToUsers chan BroadcastMessage
+
+	// Canal para cancelar las suscripciones de un usuario a una sala
+	unsubscribeRoom chan roomSubscription
+
+	// Canal para cancelar las suscripciones de un usuario a un chat directo
+	unsubscribeDirect chan roomSubscription
+
+	// Canal para actualizar los usuarios bloqueados en todas las conexiones de un usuario
+	blockedUpdates chan blockedUsersUpdate
+
 	// Repositorios
+	userRepo       *repositories.UserRepository
 	messageRepo    *repositories.MessageRepository
 	roomRepo       *repositories.RoomRepository
 	directChatRepo *repositories.DirectChatRepository
 	reportRepo     *repositories.ReportRepository
 
+	// Verificador central de permisos de las salas
+	permissions *services.PermissionService
+
+	// Preferencias de notificación de los usuarios
+	notifications *services.NotificationService
+
+	// Reglas de los chats directos, como los ajustes de privacidad de los usuarios
+	directChats *services.DirectChatService
+
+	// Cifrado de extremo a extremo de los chats directos
+	keys *services.KeyService
+
+	// Filtros de contenido que revisan los mensajes antes de guardarlos
+	contentFilter *services.ContentFilterService
+
+	// Limitador del modo lento, compartido por todas las conexiones
+	slowMode *slowModeLimiter
+
 	// Firestore client
 	firestoreClient *config.FirestoreClient
 }
 
 // NewHub inicializa un nuevo Hub
 func NewHub(
+	userRepo *repositories.UserRepository,
 	messageRepo *repositories.MessageRepository,
 	roomRepo *repositories.RoomRepository,
 	directChatRepo *repositories.DirectChatRepository,
 	reportRepo *repositories.ReportRepository,
+	permissions *services.PermissionService,
+	notifications *services.NotificationService,
+	directChats *services.DirectChatService,
+	keys *services.KeyService,
+	contentFilter *services.ContentFilterService,
 	client *config.FirestoreClient,
 ) *Hub {
 	return &Hub{
-		clients:         make(map[*Client]bool),
-		Register:        make(chan *Client),
-		Unregister:      make(chan *Client),
-		Broadcast:       make(chan BroadcastMessage),
-		BroadcastDirect: make(chan BroadcastMessage),
-		messageRepo:     messageRepo,
-		roomRepo:        roomRepo,
-		directChatRepo:  directChatRepo,
-		reportRepo:      reportRepo,
-		firestoreClient: client,
+		clients:           make(map[*Client]bool),
+		online:            make(map[string]int),
+		Register:          make(chan *Client),
+		Unregister:        make(chan *Client),
+		Broadcast:         make(chan BroadcastMessage),
+		BroadcastDirect:   make(chan BroadcastMessage),
+		sendToUsers:       make(chan BroadcastMessage),
+		unsubscribeRoom:   make(chan roomSubscription),
+		unsubscribeDirect: make(chan roomSubscription),
+		blockedUpdates:    make(chan blockedUsersUpdate),
+		userRepo:          userRepo,
+		messageRepo:       messageRepo,
+		roomRepo:          roomRepo,
+		directChatRepo:    directChatRepo,
+		reportRepo:        reportRepo,
+		permissions:       permissions,
+		notifications:     notifications,
+		directChats:       directChats,
+		keys:              keys,
+		contentFilter:     contentFilter,
+		slowMode:          newSlowModeLimiter(),
+		firestoreClient:   client,
 	}
 }
 
@@ -67,35 +140,160 @@ func (h *Hub) Run() {
 		select {
 		case client := <-h.Register:
 			h.clients[client] = true
+			h.onlineMu.Lock()
+			h.online[client.userID]++
+			h.onlineMu.Unlock()
 		case client := <-h.Unregister:
 			if _, ok := h.clients[client]; ok {
-				delete(h.clients, client)
-				close(client.send)
+				h.removeClient(client)
 			}
 		case message := <-h.Broadcast:
 			// Difundir a todos los clientes que están en la sala
 			for client := range h.clients {
-				if client.IsInRoom(message.RoomID) {
+				if client.IsInRoom(message.RoomID) && !client.hasBlocked(message.SenderID) {
 					select {
 					case client.send <- message.Message:
 					default:
-						close(client.send)
-						delete(h.clients, client)
+						h.removeClient(client)
 					}
 				}
 			}
+		case message := <-h.sendToUsers:
+			// Enviar el aviso a todas las conexiones de los usuarios destinatarios
+			for client := range h.clients {
+				if !containsUser(message.UserIDs, client.userID) || client.hasBlocked(message.SenderID) {
+					continue
+				}
+				select {
+				case client.send <- message.Message:
+				default:
+					h.removeClient(client)
+				}
+			}
+		case sub := <-h.unsubscribeRoom:
+			// Dejar de enviar los mensajes de la sala a todas las conexiones del usuario
+			for client := range h.clients {
+				if client.userID == sub.UserID {
+					client.leaveRoom(sub.RoomID)
+				}
+			}
+		case sub := <-h.unsubscribeDirect:
+			// Dejar de enviar los mensajes del chat directo a todas las conexiones del usuario
+			for client := range h.clients {
+				if client.userID == sub.UserID {
+					client.leaveDirectChat(sub.RoomID)
+				}
+			}
+		case update := <-h.blockedUpdates:
+			// Aplicar la nueva lista de bloqueados a todas las conexiones del usuario
+			for client := range h.clients {
+				if client.userID == update.UserID {
+					client.setBlockedUsers(update.BlockedIDs)
+				}
+			}
 		case message := <-h.BroadcastDirect:
 			// Difundir a todos los clientes que están en el chat directo
 			for client := range h.clients {
-				if client.IsInDirectChat(message.DirectChat) {
+				if client.IsInDirectChat(message.DirectChat) && !client.hasBlocked(message.SenderID) {
 					select {
 					case client.send <- message.Message:
 					default:
-						close(client.send)
-						delete(h.clients, client)
+						h.removeClient(client)
 					}
 				}
 			}
 		}
 	}
 }
+
+// removeClient cierra la conexión de un cliente y actualiza la presencia de su usuario
+func (h *Hub) removeClient(client *Client) {
+	delete(h.clients, client)
+	close(client.send)
+
+	h.onlineMu.Lock()
+	defer h.onlineMu.Unlock()
+	if h.online[client.userID] <= 1 {
+		delete(h.online, client.userID)
+	} else {
+		h.online[client.userID]--
+	}
+}
+
+// IsOnline indica si el usuario tiene al menos una conexión WebSocket abierta
+func (h *Hub) IsOnline(userID string) bool {
+	h.onlineMu.RLock()
+	defer h.onlineMu.RUnlock()
+	return h.online[userID] > 0
+}
+
+// BroadcastToRoom envía un evento del servidor a todos los clientes que escuchan una sala
+func (h *Hub) BroadcastToRoom(roomID string, messageType MessageType, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Error marshaling %s event: %v", messageType, err)
+		return
+	}
+
+	h.Broadcast <- BroadcastMessage{
+		Message: WebSocketMessage{
+			Type:      messageType,
+			Payload:   payload,
+			Timestamp: time.Now(),
+		},
+		RoomID: roomID,
+	}
+}
+
+// RemoveUserFromRoom cancela las suscripciones de todas las conexiones de un usuario a una sala
+func (h *Hub) RemoveUserFromRoom(userID, roomID string) {
+	h.unsubscribeRoom <- roomSubscription{UserID: userID, RoomID: roomID}
+}
+
+// RemoveUserFromDirectChat cancela las suscripciones de todas las conexiones de un usuario a un chat directo
+func (h *Hub) RemoveUserFromDirectChat(userID, directChatID string) {
+	h.unsubscribeDirect <- roomSubscription{UserID: userID, RoomID: directChatID}
+}
+
+// UpdateBlockedUsers aplica la lista de usuarios bloqueados de un usuario a sus conexiones abiertas
+func (h *Hub) UpdateBlockedUsers(userID string, blockedIDs []string) {
+	h.blockedUpdates <- blockedUsersUpdate{UserID: userID, BlockedIDs: blockedIDs}
+}
+
+// SendToUsers envía un evento del servidor a todas las conexiones de los usuarios indicados
+func (h *Hub) SendToUsers(userIDs []string, messageType MessageType, data interface{}) {
+	h.sendToUsersFrom(userIDs, "", messageType, data)
+}
+
+// sendToUsersFrom envía a los usuarios indicados un evento originado por un usuario, salvo a quienes lo han bloqueado
+func (h *Hub) sendToUsersFrom(userIDs []string, senderID string, messageType MessageType, data interface{}) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Error marshaling %s event: %v", messageType, err)
+		return
+	}
+
+	h.sendToUsers <- BroadcastMessage{
+		Message: WebSocketMessage{
+			Type:      messageType,
+			Payload:   payload,
+			Timestamp: time.Now(),
+		},
+		UserIDs:  userIDs,
+		SenderID: senderID,
+	}
+}
+
+// containsUser verifica si un usuario está en la lista de destinatarios
+func containsUser(userIDs []string, userID string) bool {
+	for _, id := range userIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package websocket
+
+import (
+	"log"
+
+	"github.com/Parchat/backend/internal/models"
+)
+
+// notificationPreviewLength es el número máximo de caracteres del contenido que se incluye en una notificación
+const notificationPreviewLength = 100
+
+// NotifyUsers envía un evento del servidor relacionado con una conversación solo a los usuarios
+// cuyas preferencias de notificación lo permiten
+func (h *Hub) NotifyUsers(conversationType, conversationID string, userIDs []string, messageType MessageType, data interface{}) {
+	recipients, err := h.notifications.Recipients(conversationType, conversationID, userIDs, nil)
+	if err != nil {
+		log.Printf("Error filtering %s recipients for %s %s: %v", messageType, conversationType, conversationID, err)
+		return
+	}
+
+	filtered := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		filtered = append(filtered, recipient.UserID)
+	}
+
+	h.SendToUsers(filtered, messageType, data)
+}
+
+// notifyNewMessage envía un evento NOTIFICATION a los participantes conectados de una conversación, salvo
+// al autor, respetando sus preferencias. Los destinatarios se agrupan por las opciones que cambian el contenido.
+// Las preferencias se cargan en otra goroutine para no frenar la lectura de mensajes del autor
+func (h *Hub) notifyNewMessage(conversationType string, message *models.Message, participants []string) {
+	// Solo se notifica a quien está conectado, así que no hace falta leer las preferencias de los demás
+	userIDs := make([]string, 0, len(participants))
+	for _, userID := range participants {
+		if userID != message.UserID && h.IsOnline(userID) && !containsUser(userIDs, userID) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+
+	msg := *message
+	go h.deliverNotifications(conversationType, &msg, userIDs)
+}
+
+// deliverNotifications filtra los destinatarios de un mensaje según sus preferencias y les envía la notificación
+func (h *Hub) deliverNotifications(conversationType string, message *models.Message, userIDs []string) {
+	recipients, err := h.notifications.Recipients(conversationType, message.RoomID, userIDs, message.Mentions)
+	if err != nil {
+		log.Printf("Error filtering notification recipients for %s %s: %v", conversationType, message.RoomID, err)
+		return
+	}
+
+	type group struct {
+		mentioned bool
+		sound     bool
+	}
+	groups := make(map[group][]string)
+	for _, recipient := range recipients {
+		key := group{mentioned: recipient.Mentioned, sound: recipient.Sound}
+		groups[key] = append(groups[key], recipient.UserID)
+	}
+
+	// El servidor no puede leer los mensajes cifrados, así que sus notificaciones no llevan vista previa
+	preview := []rune(message.Content)
+	if message.Encrypted {
+		preview = nil
+	}
+	if len(preview) > notificationPreviewLength {
+		preview = preview[:notificationPreviewLength]
+	}
+
+	for key, userIDs := range groups {
+		h.sendToUsersFrom(userIDs, message.UserID, MessageTypeNotification, NotificationPayload{
+			ConversationType: conversationType,
+			ConversationID:   message.RoomID,
+			MessageID:        message.ID,
+			SenderID:         message.UserID,
+			SenderName:       message.DisplayName,
+			Preview:          string(preview),
+			Mentioned:        key.mentioned,
+			Sound:            key.sound,
+		})
+	}
+}
+
+// filterMentions conserva solo las menciones a participantes de la conversación, sin duplicados
+func filterMentions(mentions []string, isParticipant func(userID string) bool) []string {
+	var filtered []string
+	for _, userID := range mentions {
+		if isParticipant(userID) && !containsUser(filtered, userID) {
+			filtered = append(filtered, userID)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,56 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+)
+
+// slowModePruneThreshold es el número de entradas a partir del cual se descartan las que ya expiraron
+const slowModePruneThreshold = 10000
+
+// slowModeLimiter recuerda cuándo puede volver a escribir cada usuario en cada sala.
+// Vive en el Hub para que el límite se comparta entre todas las conexiones de un usuario
+type slowModeLimiter struct {
+	mu        sync.Mutex
+	nextAllow map[string]time.Time // clave: roomID + "/" + userID
+}
+
+// newSlowModeLimiter crea un limitador vacío
+func newSlowModeLimiter() *slowModeLimiter {
+	return &slowModeLimiter{
+		nextAllow: make(map[string]time.Time),
+	}
+}
+
+// allow reserva el siguiente mensaje del usuario en la sala si ya pasó el intervalo.
+// Si no, devuelve el tiempo que falta para poder escribir
+func (l *slowModeLimiter) allow(roomID, userID string, interval time.Duration) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	key := roomID + "/" + userID
+	if next, ok := l.nextAllow[key]; ok && now.Before(next) {
+		return false, next.Sub(now)
+	}
+
+	if len(l.nextAllow) >= slowModePruneThreshold {
+		for k, next := range l.nextAllow {
+			if !now.Before(next) {
+				delete(l.nextAllow, k)
+			}
+		}
+	}
+
+	l.nextAllow[key] = now.Add(interval)
+	return true, 0
+}
+
+// release devuelve el turno reservado por allow cuando el mensaje no llega a enviarse, para que un
+// mensaje rechazado o que falla al guardarse no obligue a esperar otro intervalo
+func (l *slowModeLimiter) release(roomID, userID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.nextAllow, roomID+"/"+userID)
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/Parchat/backend/internal/models"
-	"github.com/Parchat/backend/internal/services"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
@@ -38,8 +39,117 @@ const (
 	MessageTypeError          MessageType = "ERROR"
 	MessageTypeSuccess        MessageType = "SUCCESS"
 	MessageTypeRoomCreated    MessageType = "ROOM_CREATED"
+	MessageTypeRoomUpdated    MessageType = "ROOM_UPDATED"
+	MessageTypeRoomDeleted    MessageType = "ROOM_DELETED"
+
+	MessageTypeRoleUpdated                MessageType = "ROLE_UPDATED"
+	MessageTypeOwnershipTransferRequested MessageType = "OWNERSHIP_TRANSFER_REQUESTED"
+	MessageTypeOwnershipTransferCancelled MessageType = "OWNERSHIP_TRANSFER_CANCELLED"
+	MessageTypeOwnershipTransferred       MessageType = "OWNERSHIP_TRANSFERRED"
+	MessageTypeRoomRolesUpdated           MessageType = "ROOM_ROLES_UPDATED"
+	MessageTypeMessageDeleted             MessageType = "MESSAGE_DELETED"
+	MessageTypeJoinRequestReceived        MessageType = "JOIN_REQUEST_RECEIVED"
+	MessageTypeJoinRequestReviewed        MessageType = "JOIN_REQUEST_REVIEWED"
+	MessageTypeNotification               MessageType = "NOTIFICATION"
+	MessageTypeDirectChatUpdated          MessageType = "DIRECT_CHAT_UPDATED"
+	MessageTypeMessageRequestReceived     MessageType = "MESSAGE_REQUEST_RECEIVED"
+	MessageTypeMessageRequestAccepted     MessageType = "MESSAGE_REQUEST_ACCEPTED"
+	MessageTypeKeysChanged                MessageType = "KEYS_CHANGED"
+	MessageTypeMemberMuted                MessageType = "MEMBER_MUTED"
+	MessageTypeMemberUnmuted              MessageType = "MEMBER_UNMUTED"
+	MessageTypeMemberUnbanned             MessageType = "MEMBER_UNBANNED"
+	MessageTypeMemberWarned               MessageType = "MEMBER_WARNED"
 )
 
+// Códigos de los errores estructurados
+const (
+	ErrorCodeSlowMode        = "SLOW_MODE"
+	ErrorCodeContentRejected = "CONTENT_REJECTED"
+)
+
+// ErrorPayload es el contenido de un ERROR estructurado. Los errores sin código siguen enviándose como texto
+type ErrorPayload struct {
+	Code              string `json:"code"`
+	Message           string `json:"message"`
+	RoomID            string `json:"roomId,omitempty"`
+	RetryAfterSeconds int    `json:"retryAfterSeconds,omitempty"` // Segundos que faltan para poder volver a escribir
+}
+
+// RoleUpdatedPayload es el contenido de un evento ROLE_UPDATED
+type RoleUpdatedPayload struct {
+	RoomID  string `json:"roomId"`
+	UserID  string `json:"userId"`
+	Role    string `json:"role"`    // Nuevo rol del usuario
+	ActorID string `json:"actorId"` // Usuario que realizó el cambio
+}
+
+// RoomRolesUpdatedPayload es el contenido de un evento ROOM_ROLES_UPDATED
+type RoomRolesUpdatedPayload struct {
+	RoomID  string           `json:"roomId"`
+	Role    *models.RoomRole `json:"role,omitempty"` // Nueva definición del rol, vacía si se eliminó
+	Name    string           `json:"name"`
+	Deleted bool             `json:"deleted"`
+	ActorID string           `json:"actorId"`
+}
+
+// MessageDeletedPayload es el contenido de un evento MESSAGE_DELETED
+type MessageDeletedPayload struct {
+	RoomID    string `json:"roomId"`
+	MessageID string `json:"messageId"`
+	DeletedBy string `json:"deletedBy"`
+}
+
+// OwnershipPayload es el contenido de los eventos de transferencia de propiedad
+type OwnershipPayload struct {
+	RoomID          string `json:"roomId"`
+	PreviousOwnerID string `json:"previousOwnerId"`
+	NewOwnerID      string `json:"newOwnerId"`
+}
+
+// NotificationPayload es el contenido de un evento NOTIFICATION, que avisa de un mensaje nuevo
+// a los participantes de una conversación según sus preferencias de notificación
+type NotificationPayload struct {
+	ConversationType string `json:"conversationType"` // "room" o "direct"
+	ConversationID   string `json:"conversationId"`
+	MessageID        string `json:"messageId"`
+	SenderID         string `json:"senderId"`
+	SenderName       string `json:"senderName,omitempty"`
+	Preview          string `json:"preview"`
+	Mentioned        bool   `json:"mentioned"` // El destinatario fue mencionado en el mensaje
+	Sound            bool   `json:"sound"`     // El cliente debe reproducir sonido
+}
+
+// KeysChangedPayload es el contenido de un evento KEYS_CHANGED, que avisa a quienes comparten un chat
+// directo con el usuario de que deben volver a obtener sus claves
+type KeysChangedPayload struct {
+	UserID   string `json:"userId"`
+	DeviceID string `json:"deviceId"`
+	Reason   string `json:"reason"` // "device_added", "identity_changed" o "device_removed"
+}
+
+// SanctionPayload es el contenido de los eventos MEMBER_MUTED, MEMBER_UNMUTED y MEMBER_UNBANNED
+type SanctionPayload struct {
+	RoomID    string     `json:"roomId"`
+	UserID    string     `json:"userId"`
+	ActorID   string     `json:"actorId,omitempty"`   // Vacío si la sanción caducó
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"` // Fin del silencio
+}
+
+// WarningPayload es el contenido de un evento MEMBER_WARNED, que solo recibe el usuario advertido
+type WarningPayload struct {
+	RoomID   string `json:"roomId"`
+	ActionID string `json:"actionId"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// UserLeavePayload es el contenido de un evento USER_LEAVE
+type UserLeavePayload struct {
+	RoomID    string `json:"roomId"`
+	UserID    string `json:"userId"`
+	Reason    string `json:"reason"`              // "left", "removed" o "banned"
+	RemovedBy string `json:"removedBy,omitempty"` // ID del admin que expulsó o baneó al usuario
+}
+
 // WebSocketMessage representa el formato de mensaje que se intercambia entre cliente y servidor
 type WebSocketMessage struct {
 	Type      MessageType     `json:"type"`
@@ -55,6 +165,8 @@ type Client struct {
 	userID     string
 	rooms      map[string]bool // RoomIDs que el cliente está escuchando
 	directChat map[string]bool // DirectChatIDs que el cliente está escuchando
+	blocked    map[string]bool // Usuarios bloqueados cuyos mensajes no se entregan
+	mu         sync.RWMutex    // Protege rooms, directChat y blocked, que también modifica el hub
 }
 
 // NewClient crea un nuevo cliente
@@ -66,6 +178,7 @@ func NewClient(hub *Hub, conn *websocket.Conn, userID string) *Client {
 		userID:     userID,
 		rooms:      make(map[string]bool),
 		directChat: make(map[string]bool),
+		blocked:    make(map[string]bool),
 	}
 }
 
@@ -76,6 +189,13 @@ func (c *Client) ReadPump() {
 		c.conn.Close()
 	}()
 
+	// Cargar los usuarios bloqueados antes de suscribirse a ninguna conversación
+	blockedIDs, err := c.hub.userRepo.GetBlockedUsers(context.Background(), c.userID)
+	if err != nil {
+		log.Printf("Error loading blocked users for %s: %v", c.userID, err)
+	}
+	c.setBlockedUsers(blockedIDs)
+
 	c.conn.SetReadLimit(maxMessageSize)
 	c.conn.SetReadDeadline(time.Now().Add(readWait))
 	c.conn.SetPongHandler(func(string) error {
@@ -105,59 +225,122 @@ func (c *Client) ReadPump() {
 			if err := json.Unmarshal(wsMessage.Payload, &chatMsg); err != nil {
 				log.Printf("Error unmarshaling chat message: %v", err)
 				continue
-			} // Verificar si el usuario es parte de la sala antes de enviar el mensaje
-			if !c.hub.roomRepo.CanTalkInRoomWebSocket(chatMsg.RoomID, c.userID) {
-				errMsg := "No permission to send messages to this room"
-				errorPayload, _ := json.Marshal(errMsg)
+			}
+
+			// Las respuestas solo pueden colgar de un mensaje raíz existente de la misma sala
+			if chatMsg.ThreadID != "" {
+				root, err := c.hub.messageRepo.GetMessageByID(chatMsg.RoomID, chatMsg.ThreadID)
+				if err != nil || root.IsDeleted || root.ThreadID != "" {
+					errorPayload, _ := json.Marshal("Thread not found")
+					c.send <- WebSocketMessage{
+						Type:      MessageTypeError,
+						Payload:   errorPayload,
+						Timestamp: time.Now(),
+					}
+					continue
+				}
+			}
+
+			// Verificar que el rol del usuario le permite escribir, que no está baneado, silenciado ni
+			// restringido por reportes y, en las salas de solo anuncios, que puede publicar en el timeline o responder en hilos
+			room, err := c.hub.permissions.CheckSendMessage(chatMsg.RoomID, c.userID, chatMsg.ThreadID)
+			if err != nil {
+				errorPayload, _ := json.Marshal(err.Error())
 				c.send <- WebSocketMessage{
 					Type:      MessageTypeError,
 					Payload:   errorPayload,
 					Timestamp: time.Now(),
 				}
-				log.Printf("User %s attempted to send message to room %s without permission", c.userID, chatMsg.RoomID)
+				log.Printf("User %s attempted to send message to room %s without permission: %v", c.userID, chatMsg.RoomID, err)
 				continue
 			}
 
-			// Check if the user is banned from sending messages due to reports
-			room, err := c.hub.roomRepo.GetRoom(chatMsg.RoomID)
-			if err == nil && room.ReportedUsers != nil {
-				if reportCount, exists := room.ReportedUsers[c.userID]; exists && reportCount >= services.MaxReportsBeforeBan {
-					errMsg := "You have been banned from sending messages in this room due to reports"
-					errorPayload, _ := json.Marshal(errMsg)
+			// Aplicar el modo lento salvo a quienes pueden saltárselo. El turno se devuelve si el mensaje
+			// no llega a guardarse
+			slowModeReserved := false
+			if room.SlowModeSeconds > 0 && !c.hub.permissions.Can(room, c.userID, models.CapBypassSlowMode) {
+				interval := time.Duration(room.SlowModeSeconds) * time.Second
+				if ok, remaining := c.hub.slowMode.allow(room.ID, c.userID, interval); !ok {
+					errorPayload, _ := json.Marshal(ErrorPayload{
+						Code:              ErrorCodeSlowMode,
+						Message:           "Slow mode is enabled in this room",
+						RoomID:            room.ID,
+						RetryAfterSeconds: int(math.Ceil(remaining.Seconds())),
+					})
 					c.send <- WebSocketMessage{
 						Type:      MessageTypeError,
 						Payload:   errorPayload,
 						Timestamp: time.Now(),
 					}
-					log.Printf("User %s attempted to send message to room %s while banned", c.userID, chatMsg.RoomID)
 					continue
 				}
+				slowModeReserved = true
 			}
 
-			// Asignar ID y timestamps si no existen
-			if chatMsg.ID == "" {
-				chatMsg.ID = uuid.New().String()
-			}
+			// El ID y las fechas los asigna siempre el servidor: las estadísticas y el borrado de mensajes
+			// recientes de un baneo dependen de createdAt
+			chatMsg.ID = uuid.New().String()
 			now := time.Now()
-			if chatMsg.CreatedAt.IsZero() {
-				chatMsg.CreatedAt = now
-			}
+			chatMsg.CreatedAt = now
 			chatMsg.UpdatedAt = now
 
 			// Asegurarse que el userID es el correcto
 			chatMsg.UserID = c.userID
 
+			// Las salas no tienen cifrado de extremo a extremo
+			chatMsg.Encrypted = false
+			chatMsg.Envelopes = nil
+
+			// Solo se puede mencionar a miembros de la sala
+			chatMsg.Mentions = filterMentions(chatMsg.Mentions, func(userID string) bool {
+				return room.RoleOf(userID) != ""
+			})
+
+			// Revisar el texto con el filtro de contenido de la sala: rechazarlo, ocultar lo que coincide
+			// o guardarlo para reportarlo después
+			filterResult := c.hub.contentFilter.FilterRoomMessage(room, chatMsg.Content)
+			if filterResult.Reject {
+				if slowModeReserved {
+					c.hub.slowMode.release(room.ID, c.userID)
+				}
+				errorPayload, _ := json.Marshal(ErrorPayload{
+					Code:    ErrorCodeContentRejected,
+					Message: "Your message was blocked by the room's content filter",
+					RoomID:  room.ID,
+				})
+				c.send <- WebSocketMessage{
+					Type:      MessageTypeError,
+					Payload:   errorPayload,
+					Timestamp: time.Now(),
+				}
+				continue
+			}
+			originalContent := chatMsg.Content
+			chatMsg.Content = filterResult.Content
+
 			// Guardar el mensaje en Firestore
 			err = c.hub.messageRepo.SaveMessage(&chatMsg)
 			if err != nil {
 				log.Printf("Error saving message: %v", err)
+				if slowModeReserved {
+					c.hub.slowMode.release(room.ID, c.userID)
+				}
 				continue
 			}
 
-			// Actualizar el último mensaje en la sala
-			err = c.hub.roomRepo.UpdateLastMessage(chatMsg.RoomID, &chatMsg)
-			if err != nil {
-				log.Printf("Error updating last message: %v", err)
+			// Los mensajes marcados se entregan y quedan reportados en la cola de moderación
+			if filterResult.Flag {
+				if err := c.hub.contentFilter.FlagRoomMessage(room, &chatMsg, originalContent, filterResult); err != nil {
+					log.Printf("Error reporting message %s flagged by the content filter: %v", chatMsg.ID, err)
+				}
+			}
+
+			// Actualizar el último mensaje en la sala. Las respuestas en hilos no cuentan como actividad del timeline
+			if chatMsg.ThreadID == "" {
+				err = c.hub.roomRepo.UpdateLastMessage(chatMsg.RoomID, &chatMsg)
+				if err != nil {
+					log.Printf("Error updating last message: %v", err)
+				}
 			}
 
 			// Obtener el displayName del usuario
@@ -177,10 +360,14 @@ func (c *Client) ReadPump() {
 
 			// Transmitir mensaje a todos en la sala
 			c.hub.Broadcast <- BroadcastMessage{
-				Message: wsMessage,
-				RoomID:  chatMsg.RoomID,
+				Message:  wsMessage,
+				RoomID:   chatMsg.RoomID,
+				SenderID: c.userID,
 			}
 
+			// Avisar a los miembros según sus preferencias de notificación
+			c.hub.notifyNewMessage(models.ConversationTypeRoom, &chatMsg, room.Members)
+
 		case MessageTypeDirectChat:
 			var chatMsg models.Message
 			if err := json.Unmarshal(wsMessage.Payload, &chatMsg); err != nil {
@@ -189,7 +376,8 @@ func (c *Client) ReadPump() {
 			}
 
 			// Verificar si el usuario es parte del chat directo antes de enviar el mensaje
-			if !c.hub.directChatRepo.IsUserInDirectChat(chatMsg.RoomID, c.userID) {
+			directChat, err := c.hub.directChatRepo.GetDirectChat(chatMsg.RoomID)
+			if err != nil || !directChat.IsMember(c.userID) {
 				errMsg := "Not a member of this direct chat"
 				errorPayload, _ := json.Marshal(errMsg)
 				c.send <- WebSocketMessage{
@@ -201,19 +389,91 @@ func (c *Client) ReadPump() {
 				continue
 			}
 
-			// Asignar ID y timestamps si no existen
-			if chatMsg.ID == "" {
-				chatMsg.ID = uuid.New().String()
+			// Los ajustes de privacidad del destinatario pueden impedir nuevos envíos
+			if err := c.hub.directChats.CheckDirectMessagePrivacy(directChat, c.userID); err != nil {
+				errorPayload, _ := json.Marshal("This user does not accept direct messages from you")
+				c.send <- WebSocketMessage{
+					Type:      MessageTypeError,
+					Payload:   errorPayload,
+					Timestamp: time.Now(),
+				}
+				continue
 			}
-			now := time.Now()
-			if chatMsg.CreatedAt.IsZero() {
-				chatMsg.CreatedAt = now
+
+			// Una solicitud de mensajes rechazada ya no admite mensajes
+			if directChat.RequestStatus == models.MessageRequestDeclined {
+				errorPayload, _ := json.Marshal("This message request was declined")
+				c.send <- WebSocketMessage{
+					Type:      MessageTypeError,
+					Payload:   errorPayload,
+					Timestamp: time.Now(),
+				}
+				continue
 			}
+
+			// En un chat entre dos usuarios no se entregan mensajes si uno ha bloqueado al otro
+			if !directChat.IsGroup && c.isBlockedInDirectChat(directChat) {
+				errorPayload, _ := json.Marshal("You cannot send messages to this user")
+				c.send <- WebSocketMessage{
+					Type:      MessageTypeError,
+					Payload:   errorPayload,
+					Timestamp: time.Now(),
+				}
+				continue
+			}
+
+			// Los chats cifrados solo admiten sobres cifrados, que se guardan y reenvían sin leerlos.
+			// Los demás chats no admiten sobres
+			if directChat.Encrypted {
+				if err := c.hub.keys.ValidateEncryptedMessage(directChat, &chatMsg); err != nil {
+					errorPayload, _ := json.Marshal("Encrypted chats only accept valid encrypted messages")
+					c.send <- WebSocketMessage{
+						Type:      MessageTypeError,
+						Payload:   errorPayload,
+						Timestamp: time.Now(),
+					}
+					continue
+				}
+				chatMsg.Encrypted = true
+			} else {
+				chatMsg.Encrypted = false
+				chatMsg.Envelopes = nil
+			}
+
+			// El ID y las fechas los asigna siempre el servidor: las estadísticas y el borrado de mensajes
+			// recientes de un baneo dependen de createdAt
+			chatMsg.ID = uuid.New().String()
+			now := time.Now()
+			chatMsg.CreatedAt = now
 			chatMsg.UpdatedAt = now
 
 			// Asegurarse que el userID es el correcto
 			chatMsg.UserID = c.userID
 
+			// Solo se puede mencionar a participantes del chat
+			chatMsg.Mentions = filterMentions(chatMsg.Mentions, func(userID string) bool {
+				return directChat.IsMember(userID)
+			})
+
+			// Los chats no cifrados pasan por la lista de palabrotas por defecto. En los cifrados el servidor
+			// no puede leer el texto
+			if !directChat.Encrypted {
+				filterResult := c.hub.contentFilter.FilterDirectMessage(chatMsg.Content)
+				if filterResult.Reject {
+					errorPayload, _ := json.Marshal(ErrorPayload{
+						Code:    ErrorCodeContentRejected,
+						Message: "Your message was blocked by the content filter",
+					})
+					c.send <- WebSocketMessage{
+						Type:      MessageTypeError,
+						Payload:   errorPayload,
+						Timestamp: time.Now(),
+					}
+					continue
+				}
+				chatMsg.Content = filterResult.Content
+			}
+
 			// Guardar el mensaje en Firestore
 			err = c.hub.messageRepo.SaveDirectMessage(&chatMsg)
 			if err != nil {
@@ -246,6 +506,14 @@ func (c *Client) ReadPump() {
 			c.hub.BroadcastDirect <- BroadcastMessage{
 				Message:    wsMessage,
 				DirectChat: chatMsg.RoomID,
+				SenderID:   c.userID,
+			}
+
+			// Avisar a los participantes según sus preferencias de notificación. Los mensajes de una
+			// solicitud pendiente no generan notificaciones, tampoco para quienes no han aceptado la
+			// invitación a un grupo; el destinatario los ve en su bandeja de solicitudes
+			if directChat.RequestStatus != models.MessageRequestPending {
+				c.hub.notifyNewMessage(models.ConversationTypeDirect, &chatMsg, directChat.AcceptedUserIDs())
 			}
 
 		case MessageTypeJoinRoom:
@@ -255,27 +523,19 @@ func (c *Client) ReadPump() {
 				continue
 			}
 
-			// Verificar si el usuario tiene permiso para unirse a la sala
-			// if c.hub.roomRepo.CanJoinRoomWebSocket(roomID, c.userID) {
-			c.rooms[roomID] = true
-			log.Printf("User %s joined room %s", c.userID, roomID)
-			// 	// Enviar mensaje de confirmación al usuario
-			// 	// successMsg := "Successfully joined room"
-			// 	// successPayload, _ := json.Marshal(successMsg)
-			// 	// c.send <- WebSocketMessage{
-			// 	// 	Type:      MessageTypeSuccess,
-			// 	// 	Payload:   successPayload,
-			// 	// 	Timestamp: time.Now(),
-			// 	// }
-			// } else {
-			// 	errMsg := "No permission to join this room"
-			// 	errorPayload, _ := json.Marshal(errMsg)
-			// 	c.send <- WebSocketMessage{
-			// 		Type:      MessageTypeError,
-			// 		Payload:   errorPayload,
-			// 		Timestamp: time.Now(),
-			// 	}
-			// }
+			// Verificar si el usuario tiene permiso para escuchar la sala. Solo los miembros pueden hacerlo
+			if c.hub.roomRepo.CanJoinRoomWebSocket(roomID, c.userID) {
+				c.joinRoom(roomID)
+				log.Printf("User %s joined room %s", c.userID, roomID)
+			} else {
+				errMsg := "No permission to join this room"
+				errorPayload, _ := json.Marshal(errMsg)
+				c.send <- WebSocketMessage{
+					Type:      MessageTypeError,
+					Payload:   errorPayload,
+					Timestamp: time.Now(),
+				}
+			}
 
 		// Manejar cuando un usuario quiere escuchar un chat directo
 		case MessageTypeJoinDirectChat:
@@ -287,7 +547,7 @@ func (c *Client) ReadPump() {
 
 			// Verificar si el usuario es parte del chat directo
 			if c.hub.directChatRepo.IsUserInDirectChat(directChatID, c.userID) {
-				c.directChat[directChatID] = true
+				c.joinDirectChat(directChatID)
 				log.Printf("User %s joined direct chat %s", c.userID, directChatID)
 				// Enviar mensaje de confirmación al usuario
 				// successMsg := "Successfully joined direct chat"
@@ -357,12 +617,82 @@ func (c *Client) WritePump() {
 
 // IsInRoom comprueba si el cliente está en una sala específica
 func (c *Client) IsInRoom(roomID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	_, ok := c.rooms[roomID]
 	return ok
 }
 
 // IsInDirectChat comprueba si el cliente está en un chat directo específico
 func (c *Client) IsInDirectChat(directChatID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	_, ok := c.directChat[directChatID]
 	return ok
 }
+
+// joinRoom suscribe al cliente a los mensajes de una sala
+func (c *Client) joinRoom(roomID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rooms[roomID] = true
+}
+
+// leaveRoom cancela la suscripción del cliente a una sala
+func (c *Client) leaveRoom(roomID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.rooms, roomID)
+}
+
+// joinDirectChat suscribe al cliente a los mensajes de un chat directo
+func (c *Client) joinDirectChat(directChatID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.directChat[directChatID] = true
+}
+
+// isBlockedInDirectChat comprueba si el usuario del cliente y el otro participante de un chat se han bloqueado
+func (c *Client) isBlockedInDirectChat(directChat *models.DirectChat) bool {
+	for _, userID := range directChat.UserIDs {
+		if userID == c.userID {
+			continue
+		}
+		blocked, err := c.hub.userRepo.IsBlockedBetween(context.Background(), c.userID, userID)
+		if err != nil {
+			log.Printf("Error checking blocks between %s and %s: %v", c.userID, userID, err)
+			return true
+		}
+		if blocked {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBlocked comprueba si el usuario del cliente ha bloqueado a otro
+func (c *Client) hasBlocked(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.blocked[userID]
+}
+
+// setBlockedUsers reemplaza la lista de usuarios bloqueados del cliente
+func (c *Client) setBlockedUsers(userIDs []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blocked = make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		c.blocked[id] = true
+	}
+}
+
+// leaveDirectChat cancela la suscripción del cliente a un chat directo
+func (c *Client) leaveDirectChat(directChatID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.directChat, directChatID)
+}
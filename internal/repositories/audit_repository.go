@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// AuditRepository maneja el registro de auditoría de las acciones de moderación y administración.
+// Solo permite añadir y consultar entradas
+type AuditRepository struct {
+	FirestoreClient *config.FirestoreClient
+}
+
+// NewAuditRepository crea una nueva instancia de AuditRepository
+func NewAuditRepository(client *config.FirestoreClient) *AuditRepository {
+	return &AuditRepository{
+		FirestoreClient: client,
+	}
+}
+
+// AppendEntry añade una entrada al registro. Create falla si el documento ya existe, así que
+// una entrada nunca sobrescribe a otra
+func (r *AuditRepository) AppendEntry(entry *models.AuditEntry) error {
+	ctx := context.Background()
+
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	_, err := r.FirestoreClient.Client.
+		Collection("auditLog").Doc(entry.ID).
+		Create(ctx, entry)
+
+	if err != nil {
+		return fmt.Errorf("error appending audit entry: %v", err)
+	}
+
+	return nil
+}
+
+// QueryEntries obtiene las entradas que cumplen los filtros, de la más reciente a la más antigua.
+// El cursor es la fecha de creación (Unix en nanosegundos) de la última entrada de la página anterior
+func (r *AuditRepository) QueryEntries(q models.AuditLogQuery) ([]models.AuditEntry, string, error) {
+	ctx := context.Background()
+
+	query := r.FirestoreClient.Client.Collection("auditLog").Query
+	if q.RoomID != "" {
+		query = query.Where("roomId", "==", q.RoomID)
+	}
+	if q.ActorID != "" {
+		query = query.Where("actorId", "==", q.ActorID)
+	}
+	if q.TargetID != "" {
+		query = query.Where("targetId", "==", q.TargetID)
+	}
+	if q.Action != "" {
+		query = query.Where("action", "==", q.Action)
+	}
+	if !q.From.IsZero() {
+		query = query.Where("createdAt", ">=", q.From)
+	}
+	if !q.To.IsZero() {
+		query = query.Where("createdAt", "<", q.To)
+	}
+
+	query = query.OrderBy("createdAt", firestore.Desc).Limit(q.Limit)
+
+	if q.Cursor != "" {
+		if nanos, err := strconv.ParseInt(q.Cursor, 10, 64); err == nil {
+			query = query.StartAfter(time.Unix(0, nanos))
+		}
+	}
+
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting audit entries: %v", err)
+	}
+
+	entries := make([]models.AuditEntry, 0, len(docs))
+	var nextCursor string
+	for i, doc := range docs {
+		var entry models.AuditEntry
+		if err := doc.DataTo(&entry); err != nil {
+			return nil, "", fmt.Errorf("error decoding audit entry: %v", err)
+		}
+		entries = append(entries, entry)
+
+		// Guardar el último timestamp para el cursor de siguiente página
+		if i == len(docs)-1 && len(docs) == q.Limit {
+			nextCursor = strconv.FormatInt(entry.CreatedAt.UnixNano(), 10)
+		}
+	}
+
+	return entries, nextCursor, nil
+}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// BookmarkRepository maneja las operaciones de base de datos para los mensajes guardados
+type BookmarkRepository struct {
+	FirestoreClient *config.FirestoreClient
+}
+
+// NewBookmarkRepository crea una nueva instancia de BookmarkRepository
+func NewBookmarkRepository(client *config.FirestoreClient) *BookmarkRepository {
+	return &BookmarkRepository{
+		FirestoreClient: client,
+	}
+}
+
+// bookmarks devuelve la colección de mensajes guardados de un usuario
+func (r *BookmarkRepository) bookmarks(userID string) *firestore.CollectionRef {
+	return r.FirestoreClient.Client.Collection("users").Doc(userID).Collection("bookmarks")
+}
+
+// CreateBookmark guarda un nuevo mensaje guardado en Firestore
+func (r *BookmarkRepository) CreateBookmark(bookmark *models.Bookmark) error {
+	ctx := context.Background()
+
+	// Asignar ID si no tiene uno
+	if bookmark.ID == "" {
+		bookmark.ID = uuid.New().String()
+	}
+
+	bookmark.CreatedAt = time.Now()
+
+	_, err := r.bookmarks(bookmark.UserID).Doc(bookmark.ID).Set(ctx, bookmark)
+	if err != nil {
+		return fmt.Errorf("error creating bookmark: %v", err)
+	}
+
+	return nil
+}
+
+// GetBookmark obtiene un mensaje guardado de un usuario por ID
+func (r *BookmarkRepository) GetBookmark(userID, bookmarkID string) (*models.Bookmark, error) {
+	ctx := context.Background()
+
+	docSnap, err := r.bookmarks(userID).Doc(bookmarkID).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var bookmark models.Bookmark
+	if err := docSnap.DataTo(&bookmark); err != nil {
+		return nil, err
+	}
+
+	return &bookmark, nil
+}
+
+// FindBookmarkByMessage busca si un usuario ya guardó un mensaje de una conversación. Devuelve nil si no existe
+func (r *BookmarkRepository) FindBookmarkByMessage(userID, chatType, chatID, messageID string) (*models.Bookmark, error) {
+	ctx := context.Background()
+
+	docs, err := r.bookmarks(userID).
+		Where("chatType", "==", chatType).
+		Where("chatId", "==", chatID).
+		Where("messageId", "==", messageID).
+		Limit(1).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error checking for existing bookmark: %v", err)
+	}
+
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	var bookmark models.Bookmark
+	if err := docs[0].DataTo(&bookmark); err != nil {
+		return nil, err
+	}
+
+	return &bookmark, nil
+}
+
+// DeleteBookmark elimina un mensaje guardado de un usuario
+func (r *BookmarkRepository) DeleteBookmark(userID, bookmarkID string) error {
+	ctx := context.Background()
+
+	_, err := r.bookmarks(userID).Doc(bookmarkID).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("error deleting bookmark: %v", err)
+	}
+
+	return nil
+}
+
+// GetUserBookmarks obtiene los mensajes guardados de un usuario, del más reciente al más antiguo.
+// El cursor es la fecha de creación (Unix en nanosegundos) del último elemento de la página anterior
+func (r *BookmarkRepository) GetUserBookmarks(userID string, limit int, cursor string) ([]models.Bookmark, string, error) {
+	ctx := context.Background()
+
+	query := r.bookmarks(userID).
+		OrderBy("createdAt", firestore.Desc).
+		Limit(limit)
+
+	if cursor != "" {
+		if nanos, err := strconv.ParseInt(cursor, 10, 64); err == nil {
+			query = query.StartAfter(time.Unix(0, nanos))
+		}
+	}
+
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, "", fmt.Errorf("error obtaining bookmarks: %v", err)
+	}
+
+	var bookmarks []models.Bookmark
+	var nextCursor string
+
+	for i, doc := range docs {
+		var bookmark models.Bookmark
+		if err := doc.DataTo(&bookmark); err != nil {
+			return nil, "", fmt.Errorf("error decoding bookmark: %v", err)
+		}
+		bookmarks = append(bookmarks, bookmark)
+
+		// Guardar el último timestamp para el cursor de siguiente página
+		if i == len(docs)-1 && len(docs) == limit {
+			nextCursor = strconv.FormatInt(bookmark.CreatedAt.UnixNano(), 10)
+		}
+	}
+
+	return bookmarks, nextCursor, nil
+}
@@ -2,6 +2,9 @@ package repositories
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"time"
 
 	"cloud.google.com/go/firestore"
@@ -24,7 +27,7 @@ func NewDirectChatRepository(client *config.FirestoreClient, userRepo *UserRepos
 	}
 }
 
-// CreateDirectChat crea un nuevo chat directo entre dos usuarios
+// CreateDirectChat crea un nuevo chat directo entre dos usuarios o de grupo
 func (r *DirectChatRepository) CreateDirectChat(directChat *models.DirectChat) error {
 	ctx := context.Background()
 
@@ -38,6 +41,9 @@ func (r *DirectChatRepository) CreateDirectChat(directChat *models.DirectChat) e
 	directChat.CreatedAt = now
 	directChat.UpdatedAt = now
 
+	// Guardar el conjunto de participantes para reutilizar el chat
+	directChat.ParticipantKey = models.DirectChatParticipantKey(directChat.UserIDs, directChat.IsGroup)
+
 	// Guarda el chat directo en Firestore
 	_, err := r.FirestoreClient.Client.Collection("directChats").Doc(directChat.ID).Set(ctx, directChat)
 	if err != nil {
@@ -70,8 +76,11 @@ func (r *DirectChatRepository) UpdateLastMessage(directChatID string, message *m
 	ctx := context.Background()
 
 	// Usar firestore.Update correctamente
+	// Un mensaje nuevo devuelve el chat a la lista de todos los participantes que lo archivaron o borraron
 	_, err := r.FirestoreClient.Client.Collection("directChats").Doc(directChatID).Update(ctx, []firestore.Update{
 		{Path: "lastMessage", Value: message},
+		{Path: "archivedBy", Value: firestore.Delete},
+		{Path: "hiddenBy", Value: firestore.Delete},
 		{Path: "updatedAt", Value: time.Now()},
 	})
 
@@ -85,14 +94,8 @@ func (r *DirectChatRepository) IsUserInDirectChat(directChatID string, userID st
 		return false
 	}
 
-	// Verificar si el usuario está en la lista de userIDs
-	for _, id := range chat.UserIDs {
-		if id == userID {
-			return true
-		}
-	}
-
-	return false
+	// Las invitaciones a grupos pendientes de aceptar no cuentan
+	return chat.IsMember(userID)
 }
 
 // GetUserDirectChats obtiene todos los chats directos de un usuario
@@ -113,51 +116,105 @@ func (r *DirectChatRepository) GetUserDirectChats(userID string) ([]models.Direc
 		if err := doc.DataTo(&chat); err != nil {
 			return nil, err
 		}
-		// Obtener los nombres actualizados de los usuarios
-		chat.DisplayNames = make([]string, len(chat.UserIDs))
-
-		// Como solo hay dos usuarios en un chat directo
-		// Obtenemos el nombre del primer usuario
-		user0, err := r.UserRepo.GetUserByID(ctx, chat.UserIDs[0])
-		if err == nil && user0 != nil {
-			chat.DisplayNames[0] = user0.DisplayName
-		} else {
-			chat.DisplayNames[0] = "Usuario Desconocido"
+		if chat.IsDeleted {
+			continue
 		}
+		chats = append(chats, chat)
+	}
 
-		// Obtenemos el nombre del segundo usuario
-		user1, err := r.UserRepo.GetUserByID(ctx, chat.UserIDs[1])
-		if err == nil && user1 != nil {
-			chat.DisplayNames[1] = user1.DisplayName
-		} else {
-			chat.DisplayNames[1] = "Usuario Desconocido"
+	// Obtener los nombres actualizados de todos los participantes en una sola lectura
+	var participantIDs []string
+	for _, chat := range chats {
+		participantIDs = append(participantIDs, chat.UserIDs...)
+	}
+	users, err := r.UserRepo.GetUsersByIDs(ctx, participantIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range chats {
+		setParticipantNames(&chats[i], userID, users)
+	}
+
+	return chats, nil
+}
+
+// AttachParticipantNames rellena los nombres de los participantes de un chat. Si se indica un usuario,
+// este queda al final de la lista
+func (r *DirectChatRepository) AttachParticipantNames(chat *models.DirectChat, userID string) error {
+	users, err := r.UserRepo.GetUsersByIDs(context.Background(), chat.UserIDs)
+	if err != nil {
+		return err
+	}
+
+	setParticipantNames(chat, userID, users)
+	return nil
+}
+
+// setParticipantNames rellena los nombres de los participantes de un chat. Los demás participantes
+// quedan primero, en su orden, y el usuario actual al final, tanto en UserIDs como en DisplayNames
+func setParticipantNames(chat *models.DirectChat, userID string, users map[string]*models.User) {
+	userIDs := make([]string, 0, len(chat.UserIDs))
+	for _, id := range chat.UserIDs {
+		if id != userID {
+			userIDs = append(userIDs, id)
 		}
+	}
+	if len(userIDs) < len(chat.UserIDs) {
+		userIDs = append(userIDs, userID)
+	}
 
-		// Si el usuario actual es el primer usuario, intercambiamos tanto los nombres como los IDs
-		if chat.UserIDs[0] == userID {
-			// Intercambiar DisplayNames
-			chat.DisplayNames[0], chat.DisplayNames[1] = chat.DisplayNames[1], chat.DisplayNames[0]
-			// Intercambiar UserIDs
-			chat.UserIDs[0], chat.UserIDs[1] = chat.UserIDs[1], chat.UserIDs[0]
+	chat.UserIDs = userIDs
+	chat.DisplayNames = make([]string, len(userIDs))
+	for i, id := range userIDs {
+		if user, ok := users[id]; ok {
+			chat.DisplayNames[i] = user.DisplayName
+		} else {
+			chat.DisplayNames[i] = "Usuario Desconocido"
 		}
-		// Ahora tanto en UserIDs como en DisplayNames, el otro usuario está primero y el usuario actual después
+	}
+}
 
-		chats = append(chats, chat)
+// FindDirectChatByParticipants busca un chat activo con exactamente ese conjunto de participantes
+func (r *DirectChatRepository) FindDirectChatByParticipants(userIDs []string, isGroup bool) (*models.DirectChat, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.Collection("directChats").
+		Where("participantKey", "==", models.DirectChatParticipantKey(userIDs, isGroup)).
+		Where("isDeleted", "==", false).
+		Limit(1).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, nil
 	}
 
-	return chats, nil
+	var chat models.DirectChat
+	if err := docs[0].DataTo(&chat); err != nil {
+		return nil, err
+	}
+
+	return &chat, nil
 }
 
-// FindOrCreateDirectChat encuentra un chat directo entre dos usuarios o lo crea si no existe
-func (r *DirectChatRepository) FindOrCreateDirectChat(userID1, userID2 string) (*models.DirectChat, error) {
-	// Primero intentamos encontrar un chat existente
+// FindDirectChatBetween busca el chat directo entre dos usuarios. Devuelve nil si no existe
+func (r *DirectChatRepository) FindDirectChatBetween(userID1, userID2 string) (*models.DirectChat, error) {
+	// Primero intentamos encontrar un chat existente por su conjunto de participantes
+	chat, err := r.FindDirectChatByParticipants([]string{userID1, userID2}, false)
+	if err != nil || chat != nil {
+		return chat, err
+	}
+
+	// Los chats creados antes de guardar la clave de participantes se buscan entre los del usuario
 	userChats, err := r.GetUserDirectChats(userID1)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, chat := range userChats {
-		if len(chat.UserIDs) == 2 {
+		if !chat.IsGroup && len(chat.UserIDs) == 2 {
 			// Verificar si el otro usuario está en este chat
 			if (chat.UserIDs[0] == userID1 && chat.UserIDs[1] == userID2) ||
 				(chat.UserIDs[0] == userID2 && chat.UserIDs[1] == userID1) {
@@ -166,16 +223,188 @@ func (r *DirectChatRepository) FindOrCreateDirectChat(userID1, userID2 string) (
 		}
 	}
 
-	// No se encontró, crear uno nuevo
-	newChat := &models.DirectChat{
-		ID:      uuid.New().String(),
-		UserIDs: []string{userID1, userID2},
+	return nil, nil
+}
+
+// SetArchived archiva o desarchiva un chat directo para un participante
+func (r *DirectChatRepository) SetArchived(directChatID, userID string, archived bool) error {
+	ctx := context.Background()
+
+	var value interface{} = firestore.ArrayRemove(userID)
+	if archived {
+		value = firestore.ArrayUnion(userID)
 	}
 
-	err = r.CreateDirectChat(newChat)
+	_, err := r.FirestoreClient.Client.Collection("directChats").Doc(directChatID).Update(ctx, []firestore.Update{
+		{Path: "archivedBy", Value: value},
+	})
+
+	return err
+}
+
+// HideForUser borra un chat directo solo para un participante: lo oculta de su lista y le oculta
+// los mensajes anteriores a clearedBefore. Los demás participantes no se ven afectados
+func (r *DirectChatRepository) HideForUser(directChatID, userID string, clearedBefore time.Time) error {
+	ctx := context.Background()
+
+	_, err := r.FirestoreClient.Client.Collection("directChats").Doc(directChatID).Update(ctx, []firestore.Update{
+		{Path: "archivedBy", Value: firestore.ArrayRemove(userID)},
+		{Path: "hiddenBy", Value: firestore.ArrayUnion(userID)},
+		{FieldPath: firestore.FieldPath{"clearedBefore", userID}, Value: clearedBefore},
+	})
+
+	return err
+}
+
+// SetRequestStatus cambia el estado de la solicitud de mensajes de un chat directo
+func (r *DirectChatRepository) SetRequestStatus(directChatID string, status string) error {
+	ctx := context.Background()
+
+	_, err := r.FirestoreClient.Client.Collection("directChats").Doc(directChatID).Update(ctx, []firestore.Update{
+		{Path: "requestStatus", Value: status},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+
+	return err
+}
+
+// EnableEncryption marca un chat directo como cifrado de extremo a extremo. No se puede deshacer
+func (r *DirectChatRepository) EnableEncryption(directChatID string) error {
+	ctx := context.Background()
+
+	_, err := r.FirestoreClient.Client.Collection("directChats").Doc(directChatID).Update(ctx, []firestore.Update{
+		{Path: "encrypted", Value: true},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+
+	return err
+}
+
+// FindOrCreateGroupDirectChat encuentra un chat de grupo con esos participantes o lo crea si no existe.
+// La búsqueda y la creación van en una transacción que lee y escribe un documento de bloqueo por conjunto de
+// participantes, para que dos peticiones simultáneas no creen dos grupos iguales
+func (r *DirectChatRepository) FindOrCreateGroupDirectChat(creatorID string, userIDs []string, title string, pendingInvites map[string]string) (*models.DirectChat, bool, error) {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+
+	participantKey := models.DirectChatParticipantKey(userIDs, true)
+	hash := sha256.Sum256([]byte(participantKey))
+	lockRef := client.Collection("directChatKeys").Doc(hex.EncodeToString(hash[:]))
+
+	var chat *models.DirectChat
+	var created bool
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		chat, created = nil, false
+
+		// Leer el bloqueo, aunque no exista, hace que las transacciones que crean el mismo grupo choquen
+		if lockDoc, err := tx.Get(lockRef); err != nil && lockDoc.Exists() {
+			return err
+		}
+
+		docs, err := tx.Documents(client.Collection("directChats").
+			Where("participantKey", "==", participantKey).
+			Where("isDeleted", "==", false).
+			Limit(1)).GetAll()
+		if err != nil {
+			return err
+		}
+		if len(docs) > 0 {
+			var existing models.DirectChat
+			if err := docs[0].DataTo(&existing); err != nil {
+				return err
+			}
+			chat = &existing
+			return nil
+		}
+
+		now := time.Now()
+		newChat := &models.DirectChat{
+			ID:             uuid.New().String(),
+			UserIDs:        userIDs,
+			IsGroup:        true,
+			Title:          title,
+			CreatedBy:      creatorID,
+			ParticipantKey: participantKey,
+			// Los participantes que no tienen al creador como contacto reciben el grupo como solicitud
+			PendingInvites: pendingInvites,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+
+		if err := tx.Set(lockRef, map[string]interface{}{
+			"directChatId": newChat.ID,
+			"updatedAt":    now,
+		}); err != nil {
+			return err
+		}
+		if err := tx.Create(client.Collection("directChats").Doc(newChat.ID), newChat); err != nil {
+			return err
+		}
+
+		chat, created = newChat, true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return chat, created, nil
+}
+
+// UpdateParticipants cambia los participantes de un chat de grupo dentro de una transacción.
+// La función recibe el chat leído en la transacción y lo modifica; la clave de participantes se recalcula
+func (r *DirectChatRepository) UpdateParticipants(directChatID string, update func(chat *models.DirectChat) error) (*models.DirectChat, error) {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+
+	var updated models.DirectChat
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		ref := client.Collection("directChats").Doc(directChatID)
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return fmt.Errorf("direct chat not found")
+		}
+
+		var chat models.DirectChat
+		if err := doc.DataTo(&chat); err != nil {
+			return err
+		}
+		if chat.IsDeleted {
+			return fmt.Errorf("direct chat not found")
+		}
+
+		if err := update(&chat); err != nil {
+			return err
+		}
+
+		chat.ParticipantKey = models.DirectChatParticipantKey(chat.UserIDs, chat.IsGroup)
+		chat.UpdatedAt = time.Now()
+		updated = chat
+
+		return tx.Update(ref, []firestore.Update{
+			{Path: "userIds", Value: chat.UserIDs},
+			{Path: "participantKey", Value: chat.ParticipantKey},
+			{Path: "createdBy", Value: chat.CreatedBy},
+			{Path: "pendingInvites", Value: chat.PendingInvites},
+			{Path: "isDeleted", Value: chat.IsDeleted},
+			{Path: "updatedAt", Value: chat.UpdatedAt},
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return newChat, nil
+	return &updated, nil
+}
+
+// UpdateTitle cambia el título de un chat de grupo
+func (r *DirectChatRepository) UpdateTitle(directChatID string, title string) error {
+	ctx := context.Background()
+
+	_, err := r.FirestoreClient.Client.Collection("directChats").Doc(directChatID).Update(ctx, []firestore.Update{
+		{Path: "title", Value: title},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+
+	return err
 }
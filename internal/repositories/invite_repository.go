@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+)
+
+// InviteRepository maneja las operaciones de base de datos para las invitaciones a salas
+type InviteRepository struct {
+	FirestoreClient *config.FirestoreClient
+}
+
+// NewInviteRepository crea una nueva instancia de InviteRepository
+func NewInviteRepository(client *config.FirestoreClient) *InviteRepository {
+	return &InviteRepository{
+		FirestoreClient: client,
+	}
+}
+
+// CreateInvite guarda una nueva invitación usando su código como ID del documento.
+// Falla si el código ya existe
+func (r *InviteRepository) CreateInvite(invite *models.Invite) error {
+	ctx := context.Background()
+
+	_, err := r.FirestoreClient.Client.Collection("invites").Doc(invite.Code).Create(ctx, invite)
+	return err
+}
+
+// GetInvite obtiene una invitación por su código
+func (r *InviteRepository) GetInvite(code string) (*models.Invite, error) {
+	ctx := context.Background()
+
+	doc, err := r.FirestoreClient.Client.Collection("invites").Doc(code).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("invite not found")
+	}
+
+	var invite models.Invite
+	if err := doc.DataTo(&invite); err != nil {
+		return nil, err
+	}
+
+	return &invite, nil
+}
+
+// GetRoomInvites obtiene las invitaciones de una sala que no han sido revocadas, de la más reciente a la más antigua
+func (r *InviteRepository) GetRoomInvites(roomID string) ([]models.Invite, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.Collection("invites").
+		Where("roomId", "==", roomID).
+		Where("revoked", "==", false).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting invites: %v", err)
+	}
+
+	invites := make([]models.Invite, 0, len(docs))
+	for _, doc := range docs {
+		var invite models.Invite
+		if err := doc.DataTo(&invite); err != nil {
+			return nil, err
+		}
+		invites = append(invites, invite)
+	}
+
+	sort.Slice(invites, func(i, j int) bool {
+		return invites[i].CreatedAt.After(invites[j].CreatedAt)
+	})
+
+	return invites, nil
+}
+
+// RevokeInvite marca una invitación como revocada
+func (r *InviteRepository) RevokeInvite(code string) error {
+	ctx := context.Background()
+
+	_, err := r.FirestoreClient.Client.Collection("invites").Doc(code).Update(ctx, []firestore.Update{
+		{Path: "revoked", Value: true},
+	})
+
+	return err
+}
+
+// RedeemInvite valida una invitación, suma un uso y añade al usuario como miembro de la sala en una sola transacción,
+// para que dos usuarios no puedan superar el máximo de usos al mismo tiempo
+func (r *InviteRepository) RedeemInvite(code, userID string) (*models.Invite, error) {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+
+	var invite models.Invite
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		inviteRef := client.Collection("invites").Doc(code)
+		inviteDoc, err := tx.Get(inviteRef)
+		if err != nil {
+			return fmt.Errorf("invite not found")
+		}
+		if err := inviteDoc.DataTo(&invite); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		switch {
+		case invite.Revoked:
+			return fmt.Errorf("invite has been revoked")
+		case invite.IsExpired(now):
+			return fmt.Errorf("invite has expired")
+		case invite.IsExhausted():
+			return fmt.Errorf("invite has reached its maximum uses")
+		}
+
+		roomRef := client.Collection("rooms").Doc(invite.RoomID)
+		roomDoc, err := tx.Get(roomRef)
+		if err != nil {
+			return fmt.Errorf("room not found")
+		}
+
+		var room models.Room
+		if err := roomDoc.DataTo(&room); err != nil {
+			return err
+		}
+		updates, err := memberJoinUpdates(&room, userID, now)
+		if err != nil {
+			return err
+		}
+
+		// Como al unirse directamente, los canales de un espacio solo admiten a sus miembros
+		if room.SpaceID != "" {
+			spaceDoc, err := tx.Get(client.Collection("spaces").Doc(room.SpaceID))
+			if err != nil {
+				return fmt.Errorf("room not found")
+			}
+			var space models.Space
+			if err := spaceDoc.DataTo(&space); err != nil {
+				return err
+			}
+			if !space.IsMember(userID) {
+				return fmt.Errorf("user is not a member of the space")
+			}
+		}
+
+		if err := tx.Update(inviteRef, []firestore.Update{
+			{Path: "uses", Value: firestore.Increment(1)},
+		}); err != nil {
+			return err
+		}
+
+		return tx.Update(roomRef, updates)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recordMembershipChange(client, invite.RoomID, 1, 0)
+
+	invite.Uses++
+	return &invite, nil
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+)
+
+// JoinRequestRepository maneja las operaciones de base de datos para las solicitudes de unión a salas
+type JoinRequestRepository struct {
+	FirestoreClient *config.FirestoreClient
+}
+
+// NewJoinRequestRepository crea una nueva instancia de JoinRequestRepository
+func NewJoinRequestRepository(client *config.FirestoreClient) *JoinRequestRepository {
+	return &JoinRequestRepository{
+		FirestoreClient: client,
+	}
+}
+
+// joinRequests devuelve la colección de solicitudes de una sala
+func (r *JoinRequestRepository) joinRequests(roomID string) *firestore.CollectionRef {
+	return r.FirestoreClient.Client.Collection("rooms").Doc(roomID).Collection("joinRequests")
+}
+
+// SaveJoinRequest crea o reemplaza la solicitud de un usuario para unirse a una sala
+func (r *JoinRequestRepository) SaveJoinRequest(request *models.JoinRequest) error {
+	ctx := context.Background()
+
+	_, err := r.joinRequests(request.RoomID).Doc(request.UserID).Set(ctx, request)
+	if err != nil {
+		return fmt.Errorf("error saving join request: %v", err)
+	}
+
+	return nil
+}
+
+// GetJoinRequest obtiene la solicitud de un usuario para unirse a una sala
+func (r *JoinRequestRepository) GetJoinRequest(roomID, userID string) (*models.JoinRequest, error) {
+	ctx := context.Background()
+
+	doc, err := r.joinRequests(roomID).Doc(userID).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var request models.JoinRequest
+	if err := doc.DataTo(&request); err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// GetPendingJoinRequests obtiene las solicitudes pendientes de una sala, de la más antigua a la más reciente
+func (r *JoinRequestRepository) GetPendingJoinRequests(roomID string) ([]models.JoinRequest, error) {
+	ctx := context.Background()
+
+	docs, err := r.joinRequests(roomID).
+		Where("status", "==", models.JoinRequestPending).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting join requests: %v", err)
+	}
+
+	requests := make([]models.JoinRequest, 0, len(docs))
+	for _, doc := range docs {
+		var request models.JoinRequest
+		if err := doc.DataTo(&request); err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].CreatedAt.Before(requests[j].CreatedAt)
+	})
+
+	return requests, nil
+}
+
+// ReviewJoinRequest guarda la decisión tomada sobre una solicitud
+func (r *JoinRequestRepository) ReviewJoinRequest(roomID, userID, status, reviewerID string) error {
+	ctx := context.Background()
+
+	_, err := r.joinRequests(roomID).Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "status", Value: status},
+		{Path: "reviewedBy", Value: reviewerID},
+		{Path: "reviewedAt", Value: time.Now()},
+	})
+
+	return err
+}
+
+// DeleteJoinRequest elimina la solicitud de un usuario
+func (r *JoinRequestRepository) DeleteJoinRequest(roomID, userID string) error {
+	ctx := context.Background()
+
+	_, err := r.joinRequests(roomID).Doc(userID).Delete(ctx)
+	return err
+}
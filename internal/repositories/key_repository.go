@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+)
+
+// KeyRepository maneja las claves públicas de cifrado de extremo a extremo de los dispositivos.
+// Nunca guarda claves privadas ni texto de los mensajes
+type KeyRepository struct {
+	FirestoreClient *config.FirestoreClient
+}
+
+// NewKeyRepository crea una nueva instancia de KeyRepository
+func NewKeyRepository(client *config.FirestoreClient) *KeyRepository {
+	return &KeyRepository{
+		FirestoreClient: client,
+	}
+}
+
+// devicesRef devuelve la colección de dispositivos de un usuario
+func (r *KeyRepository) devicesRef(userID string) *firestore.CollectionRef {
+	return r.FirestoreClient.Client.Collection("users").Doc(userID).Collection("devices")
+}
+
+// SaveDeviceKeys guarda o reemplaza las claves de un dispositivo
+func (r *KeyRepository) SaveDeviceKeys(device *models.DeviceKeys) error {
+	ctx := context.Background()
+
+	_, err := r.devicesRef(device.UserID).Doc(device.DeviceID).Set(ctx, device)
+	return err
+}
+
+// GetDeviceKeys obtiene las claves de un dispositivo. Devuelve nil si no existe
+func (r *KeyRepository) GetDeviceKeys(userID, deviceID string) (*models.DeviceKeys, error) {
+	ctx := context.Background()
+
+	doc, err := r.devicesRef(userID).Doc(deviceID).Get(ctx)
+	if err != nil {
+		if !doc.Exists() {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var device models.DeviceKeys
+	if err := doc.DataTo(&device); err != nil {
+		return nil, err
+	}
+
+	return &device, nil
+}
+
+// GetUserDevices obtiene las claves de todos los dispositivos de un usuario
+func (r *KeyRepository) GetUserDevices(userID string) ([]models.DeviceKeys, error) {
+	ctx := context.Background()
+
+	docs, err := r.devicesRef(userID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting devices: %v", err)
+	}
+
+	devices := make([]models.DeviceKeys, 0, len(docs))
+	for _, doc := range docs {
+		var device models.DeviceKeys
+		if err := doc.DataTo(&device); err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+
+	return devices, nil
+}
+
+// DeleteDevice borra las claves de un dispositivo y sus claves de un solo uso
+func (r *KeyRepository) DeleteDevice(userID, deviceID string) error {
+	if err := r.DeleteOneTimePreKeys(userID, deviceID); err != nil {
+		return err
+	}
+
+	_, err := r.devicesRef(userID).Doc(deviceID).Delete(context.Background())
+	return err
+}
+
+// DeleteOneTimePreKeys borra todas las claves de un solo uso de un dispositivo
+func (r *KeyRepository) DeleteOneTimePreKeys(userID, deviceID string) error {
+	ctx := context.Background()
+
+	prekeys, err := r.devicesRef(userID).Doc(deviceID).Collection("prekeys").Documents(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+	for _, doc := range prekeys {
+		if _, err := doc.Ref.Delete(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddOneTimePreKeys añade claves de un solo uso a un dispositivo. Una clave con un ID ya usado la reemplaza
+func (r *KeyRepository) AddOneTimePreKeys(userID, deviceID string, keys []models.OneTimePreKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+	prekeysRef := r.devicesRef(userID).Doc(deviceID).Collection("prekeys")
+
+	return client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, key := range keys {
+			if err := tx.Set(prekeysRef.Doc(strconv.Itoa(key.KeyID)), key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ClaimOneTimePreKey entrega y borra una clave de un solo uso de un dispositivo, de forma que nunca se
+// entregue dos veces. Devuelve nil si el dispositivo no tiene claves disponibles
+func (r *KeyRepository) ClaimOneTimePreKey(userID, deviceID string) (*models.OneTimePreKey, error) {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+	query := r.devicesRef(userID).Doc(deviceID).Collection("prekeys").Limit(1)
+
+	var claimed *models.OneTimePreKey
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = nil
+
+		docs, err := tx.Documents(query).GetAll()
+		if err != nil {
+			return err
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+
+		var key models.OneTimePreKey
+		if err := docs[0].DataTo(&key); err != nil {
+			return err
+		}
+		claimed = &key
+
+		return tx.Delete(docs[0].Ref)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// CountOneTimePreKeys cuenta las claves de un solo uso que le quedan a un dispositivo
+func (r *KeyRepository) CountOneTimePreKeys(userID, deviceID string) (int, error) {
+	ctx := context.Background()
+
+	query := r.devicesRef(userID).Doc(deviceID).Collection("prekeys").Query
+	result, err := query.NewAggregationQuery().
+		WithCount("total").
+		Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error counting prekeys: %v", err)
+	}
+
+	count, ok := result["total"].(*firestorepb.Value)
+	if !ok {
+		return 0, fmt.Errorf("error counting prekeys: unexpected result")
+	}
+
+	return int(count.GetIntegerValue()), nil
+}
+
+// TouchDevice actualiza la fecha de modificación de un dispositivo
+func (r *KeyRepository) TouchDevice(userID, deviceID string) error {
+	ctx := context.Background()
+
+	_, err := r.devicesRef(userID).Doc(deviceID).Update(ctx, []firestore.Update{
+		{Path: "updatedAt", Value: time.Now()},
+	})
+	return err
+}
@@ -37,6 +37,8 @@ func (r *MessageRepository) SaveMessage(message *models.Message) error {
 		return err
 	}
 
+	recordMessageStats(r.FirestoreClient.Client, message)
+
 	return nil
 }
 
@@ -79,6 +81,108 @@ func (r *MessageRepository) GetMessageByID(roomID, messageID string) (*models.Me
 	return &message, nil
 }
 
+// SoftDeleteMessage marca un mensaje de sala como eliminado y borra su contenido
+func (r *MessageRepository) SoftDeleteMessage(roomID, messageID string) error {
+	ctx := context.Background()
+
+	_, err := r.FirestoreClient.Client.
+		Collection("rooms").Doc(roomID).
+		Collection("messages").Doc(messageID).
+		Update(ctx, []firestore.Update{
+			{Path: "isDeleted", Value: true},
+			{Path: "content", Value: ""},
+			{Path: "updatedAt", Value: time.Now()},
+		})
+
+	return err
+}
+
+// SoftDeleteUserMessagesSince marca como eliminados los mensajes de un usuario en una sala enviados
+// desde una fecha, y devuelve sus IDs. Se filtra por createdAt, que asigna siempre el servidor al recibir
+// el mensaje, así que un usuario no puede escapar del borrado poniendo fechas antiguas a sus mensajes
+func (r *MessageRepository) SoftDeleteUserMessagesSince(roomID, userID string, since time.Time) ([]string, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.
+		Collection("rooms").Doc(roomID).
+		Collection("messages").
+		Where("userId", "==", userID).
+		Where("createdAt", ">=", since).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting user messages: %v", err)
+	}
+
+	now := time.Now()
+	deleted := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		var message models.Message
+		if err := doc.DataTo(&message); err != nil || message.IsDeleted {
+			continue
+		}
+
+		_, err := doc.Ref.Update(ctx, []firestore.Update{
+			{Path: "isDeleted", Value: true},
+			{Path: "content", Value: ""},
+			{Path: "updatedAt", Value: now},
+		})
+		if err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, message.ID)
+	}
+
+	return deleted, nil
+}
+
+// GetThreadReplies obtiene las respuestas de un hilo de la más antigua a la más reciente
+func (r *MessageRepository) GetThreadReplies(roomID, threadID string, limit int) ([]models.Message, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.
+		Collection("rooms").Doc(roomID).
+		Collection("messages").
+		Where("threadId", "==", threadID).
+		OrderBy("createdAt", firestore.Asc).
+		Limit(limit).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error obtaining thread replies: %v", err)
+	}
+
+	replies := make([]models.Message, 0, len(docs))
+	for _, doc := range docs {
+		var message models.Message
+		if err := doc.DataTo(&message); err != nil {
+			return nil, fmt.Errorf("error decoding message: %v", err)
+		}
+		replies = append(replies, message)
+	}
+
+	return replies, nil
+}
+
+// GetDirectMessageByID obtiene un mensaje de un chat directo por su ID
+func (r *MessageRepository) GetDirectMessageByID(directChatID, messageID string) (*models.Message, error) {
+	ctx := context.Background()
+
+	doc, err := r.FirestoreClient.Client.
+		Collection("directChats").Doc(directChatID).
+		Collection("messages").Doc(messageID).
+		Get(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting message: %v", err)
+	}
+
+	var message models.Message
+	if err := doc.DataTo(&message); err != nil {
+		return nil, fmt.Errorf("error converting document to message: %v", err)
+	}
+
+	return &message, nil
+}
+
 func (r *MessageRepository) GetRoomMessages(roomID string, limit int, cursor string) ([]models.MessageResponse, string, error) {
 	ctx := context.Background()
 
@@ -161,8 +265,9 @@ func (r *MessageRepository) GetRoomMessages(roomID string, limit int, cursor str
 	return response, nextCursor, nil
 }
 
-// GetDirectChatMessagesSimple obtiene los mensajes de un chat directo sin paginación
-func (r *MessageRepository) GetDirectChatMessagesSimple(directChatID string, limit int) ([]models.MessageResponse, error) {
+// GetDirectChatMessagesSimple obtiene los mensajes de un chat directo sin paginación. Si clearedBefore
+// no está vacío, solo se devuelven los mensajes posteriores a esa fecha
+func (r *MessageRepository) GetDirectChatMessagesSimple(directChatID string, limit int, clearedBefore time.Time) ([]models.MessageResponse, error) {
 	ctx := context.Background()
 
 	var messages []models.Message
@@ -175,6 +280,11 @@ func (r *MessageRepository) GetDirectChatMessagesSimple(directChatID string, lim
 		OrderBy("createdAt", firestore.Desc).
 		Limit(limit)
 
+	// Ocultar el historial que el usuario borró
+	if !clearedBefore.IsZero() {
+		messagesRef = messagesRef.Where("createdAt", ">", clearedBefore)
+	}
+
 	docs, err := messagesRef.Documents(ctx).GetAll()
 	if err != nil {
 		return nil, err
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+)
+
+// NotificationRepository maneja las operaciones de base de datos de las preferencias de notificación
+type NotificationRepository struct {
+	FirestoreClient *config.FirestoreClient
+}
+
+// NewNotificationRepository crea una nueva instancia de NotificationRepository
+func NewNotificationRepository(client *config.FirestoreClient) *NotificationRepository {
+	return &NotificationRepository{
+		FirestoreClient: client,
+	}
+}
+
+// preferenceRef devuelve la referencia al documento de preferencias de un usuario para una conversación
+func (r *NotificationRepository) preferenceRef(userID, conversationType, conversationID string) *firestore.DocumentRef {
+	return r.FirestoreClient.Client.
+		Collection("users").Doc(userID).
+		Collection("notificationPrefs").Doc(models.NotificationPreferenceKey(conversationType, conversationID))
+}
+
+// SavePreference guarda las preferencias de un usuario para una conversación
+func (r *NotificationRepository) SavePreference(userID string, pref *models.NotificationPreference) error {
+	ctx := context.Background()
+
+	_, err := r.preferenceRef(userID, pref.ConversationType, pref.ConversationID).Set(ctx, pref)
+	return err
+}
+
+// GetUserPreferences obtiene todas las preferencias configuradas por un usuario
+func (r *NotificationRepository) GetUserPreferences(userID string) ([]models.NotificationPreference, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.
+		Collection("users").Doc(userID).
+		Collection("notificationPrefs").
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting notification preferences: %v", err)
+	}
+
+	prefs := make([]models.NotificationPreference, 0, len(docs))
+	for _, doc := range docs {
+		var pref models.NotificationPreference
+		if err := doc.DataTo(&pref); err != nil {
+			return nil, err
+		}
+		prefs = append(prefs, pref)
+	}
+
+	return prefs, nil
+}
+
+// GetPreferencesForUsers obtiene en una sola lectura las preferencias de varios usuarios para una conversación.
+// Los usuarios que no las han configurado no aparecen en el resultado
+func (r *NotificationRepository) GetPreferencesForUsers(userIDs []string, conversationType, conversationID string) (map[string]*models.NotificationPreference, error) {
+	prefs := make(map[string]*models.NotificationPreference)
+	if len(userIDs) == 0 {
+		return prefs, nil
+	}
+
+	refs := make([]*firestore.DocumentRef, 0, len(userIDs))
+	for _, userID := range userIDs {
+		refs = append(refs, r.preferenceRef(userID, conversationType, conversationID))
+	}
+
+	docs, err := r.FirestoreClient.Client.GetAll(context.Background(), refs)
+	if err != nil {
+		return nil, fmt.Errorf("error getting notification preferences: %v", err)
+	}
+
+	for i, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		var pref models.NotificationPreference
+		if err := doc.DataTo(&pref); err != nil {
+			return nil, err
+		}
+		prefs[userIDs[i]] = &pref
+	}
+
+	return prefs, nil
+}
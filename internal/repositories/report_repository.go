@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/Parchat/backend/internal/config"
@@ -42,6 +44,8 @@ func (r *ReportRepository) CreateReport(report *models.Report) error {
 		return fmt.Errorf("error creating report: %v", err)
 	}
 
+	recordReportStats(r.FirestoreClient.Client, report)
+
 	return nil
 }
 
@@ -98,32 +102,264 @@ func (r *ReportRepository) GetReportedUsersInRoom(roomID string) (map[string]int
 	return reportedUsers, nil
 }
 
-// DeleteReportsForUserInRoom deletes all reports for a user in a specific room
-func (r *ReportRepository) DeleteReportsForUserInRoom(roomID, userID string) error {
+// GetOpenReportsForUserInRoom gets the open reports against a user in a specific room
+func (r *ReportRepository) GetOpenReportsForUserInRoom(roomID, userID string) ([]models.Report, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.
+		Collection("reports").
+		Where("roomId", "==", roomID).
+		Where("reportedId", "==", userID).
+		Where("status", "==", models.ReportStatusOpen).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting reports: %v", err)
+	}
+
+	reports := make([]models.Report, 0, len(docs))
+	for _, doc := range docs {
+		var report models.Report
+		if err := doc.DataTo(&report); err != nil {
+			log.Printf("Error converting document to report: %v", err)
+			continue
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// GetRoomReports gets a page of the reports in a room with a given status, oldest first.
+// The cursor is the creation time (Unix nanoseconds) of the last report of the previous page
+func (r *ReportRepository) GetRoomReports(roomID, status string, limit int, cursor string) ([]models.Report, string, error) {
 	ctx := context.Background()
 
-	// Query reports for this user in this room
 	query := r.FirestoreClient.Client.
 		Collection("reports").
 		Where("roomId", "==", roomID).
-		Where("reportedId", "==", userID)
+		Where("status", "==", status).
+		OrderBy("createdAt", firestore.Asc).
+		Limit(limit)
+
+	if cursor != "" {
+		if nanos, err := strconv.ParseInt(cursor, 10, 64); err == nil {
+			query = query.StartAfter(time.Unix(0, nanos))
+		}
+	}
 
-	// Get all matching documents
 	docs, err := query.Documents(ctx).GetAll()
 	if err != nil {
-		return fmt.Errorf("error getting reports to delete: %v", err)
+		return nil, "", fmt.Errorf("error getting reports: %v", err)
 	}
 
-	// Use a transaction instead of batch (as batch is deprecated)
-	return r.FirestoreClient.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
-		// Delete each document in the transaction
-		for _, doc := range docs {
-			if err := tx.Delete(doc.Ref); err != nil {
-				return fmt.Errorf("error adding delete operation to transaction: %v", err)
+	reports := make([]models.Report, 0, len(docs))
+	var nextCursor string
+	for i, doc := range docs {
+		var report models.Report
+		if err := doc.DataTo(&report); err != nil {
+			return nil, "", fmt.Errorf("error converting document to report: %v", err)
+		}
+		reports = append(reports, report)
+
+		// Keep the last timestamp as the cursor for the next page
+		if i == len(docs)-1 && len(docs) == limit {
+			nextCursor = strconv.FormatInt(report.CreatedAt.UnixNano(), 10)
+		}
+	}
+
+	return reports, nextCursor, nil
+}
+
+// GetReportsByIDs gets reports by their IDs. Reports that do not exist are left out
+func (r *ReportRepository) GetReportsByIDs(reportIDs []string) ([]models.Report, error) {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+
+	refs := make([]*firestore.DocumentRef, 0, len(reportIDs))
+	for _, reportID := range reportIDs {
+		refs = append(refs, client.Collection("reports").Doc(reportID))
+	}
+
+	docs, err := client.GetAll(ctx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("error getting reports: %v", err)
+	}
+
+	reports := make([]models.Report, 0, len(docs))
+	for _, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		var report models.Report
+		if err := doc.DataTo(&report); err != nil {
+			return nil, fmt.Errorf("error converting document to report: %v", err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// ResolveReports saves a moderation action and marks the reports it resolves with the given status.
+// The resolved reports no longer count toward the reported user's score in the room. The reports are
+// read again inside the transaction, so two moderators cannot resolve the same report
+func (r *ReportRepository) ResolveReports(action *models.ModerationAction, status string) error {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+	roomRef := client.Collection("rooms").Doc(action.RoomID)
+
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(roomRef)
+		if err != nil {
+			return fmt.Errorf("room not found")
+		}
+
+		var room models.Room
+		if err := doc.DataTo(&room); err != nil {
+			return err
+		}
+
+		reportRefs := make([]*firestore.DocumentRef, 0, len(action.ReportIDs))
+		for _, reportID := range action.ReportIDs {
+			reportRefs = append(reportRefs, client.Collection("reports").Doc(reportID))
+		}
+		reportDocs, err := tx.GetAll(reportRefs)
+		if err != nil {
+			return err
+		}
+		for _, reportDoc := range reportDocs {
+			if !reportDoc.Exists() {
+				return fmt.Errorf("report not found")
+			}
+			var report models.Report
+			if err := reportDoc.DataTo(&report); err != nil {
+				return err
+			}
+			if report.Status != "" && report.Status != models.ReportStatusOpen {
+				return fmt.Errorf("report already resolved")
 			}
 		}
-		return nil
+
+		resolved := make(map[string]bool, len(action.ReportIDs))
+		for _, reportID := range action.ReportIDs {
+			resolved[reportID] = true
+		}
+		scores := []models.WeightedReport{}
+		for _, report := range room.ReportScores[action.TargetID] {
+			if !resolved[report.ReportID] {
+				scores = append(scores, report)
+			}
+		}
+
+		if err := tx.Set(roomRef.Collection("moderationActions").Doc(action.ID), action); err != nil {
+			return err
+		}
+
+		for _, reportRef := range reportRefs {
+			err := tx.Update(reportRef, []firestore.Update{
+				{Path: "status", Value: status},
+				{Path: "actionId", Value: action.ID},
+				{Path: "resolvedBy", Value: action.ActorID},
+				{Path: "resolvedAt", Value: action.CreatedAt},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, ok := room.ReportScores[action.TargetID]; !ok {
+			return nil
+		}
+		return tx.Update(roomRef, []firestore.Update{
+			{FieldPath: firestore.FieldPath{"reportScores", action.TargetID}, Value: scores},
+		})
 	})
+	if err != nil {
+		if err.Error() == "report already resolved" || err.Error() == "report not found" {
+			return err
+		}
+		return fmt.Errorf("error resolving reports: %v", err)
+	}
+
+	return nil
+}
+
+// GetModerationActions gets the latest moderation actions of a room, newest first
+func (r *ReportRepository) GetModerationActions(roomID string, limit int) ([]models.ModerationAction, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.
+		Collection("rooms").Doc(roomID).
+		Collection("moderationActions").
+		OrderBy("createdAt", firestore.Desc).
+		Limit(limit).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting moderation actions: %v", err)
+	}
+
+	actions := make([]models.ModerationAction, 0, len(docs))
+	for _, doc := range docs {
+		var action models.ModerationAction
+		if err := doc.DataTo(&action); err != nil {
+			return nil, fmt.Errorf("error converting document to moderation action: %v", err)
+		}
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+// reportStatusMigration is the document that records that the report status migration has finished
+const reportStatusMigration = "reportStatus"
+
+// MigrateReports marks the reports created before reports had a status as open. Firestore cannot query
+// for a missing field, so the whole collection is scanned once and the run is recorded in migrations;
+// a report that changed since it was read is left for the next boot
+func (r *ReportRepository) MigrateReports() error {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+	markerRef := client.Collection("migrations").Doc(reportStatusMigration)
+
+	if marker, err := markerRef.Get(ctx); err == nil && marker.Exists() {
+		return nil
+	}
+
+	iter := client.Collection("reports").Documents(ctx)
+	defer iter.Stop()
+
+	failed := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error iterating reports: %v", err)
+		}
+
+		if status, err := doc.DataAt("status"); err == nil && status != nil && status != "" {
+			continue
+		}
+
+		_, err = doc.Ref.Update(ctx, []firestore.Update{
+			{Path: "status", Value: models.ReportStatusOpen},
+		}, firestore.LastUpdateTime(doc.UpdateTime))
+		if err != nil {
+			log.Printf("Error migrating report %s: %v", doc.Ref.ID, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return nil
+	}
+
+	if _, err := markerRef.Set(ctx, map[string]interface{}{"completedAt": time.Now()}); err != nil {
+		return fmt.Errorf("error recording report migration: %v", err)
+	}
+
+	return nil
 }
 
 // HasUserReportedMessage checks if a user has already reported a specific message
@@ -145,19 +381,51 @@ func (r *ReportRepository) HasUserReportedMessage(reporterID, messageID string)
 	return len(docs) > 0, nil
 }
 
-// UpdateRoomReportedUsers updates the reported users map in a room
-func (r *ReportRepository) UpdateRoomReportedUsers(roomID string, reportedUsers map[string]int) error {
+// AddWeightedReport adds a report to a user's score in a room. Reports that are already outside
+// the room's window are dropped in the same transaction, so the list does not grow forever
+func (r *ReportRepository) AddWeightedReport(roomID, reportedID string, report models.WeightedReport) error {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+	roomRef := client.Collection("rooms").Doc(roomID)
+
+	return client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(roomRef)
+		if err != nil {
+			return fmt.Errorf("error getting room: %v", err)
+		}
+
+		var room models.Room
+		if err := doc.DataTo(&room); err != nil {
+			return err
+		}
+
+		since := report.CreatedAt.Add(-room.ReportWindow())
+		reports := []models.WeightedReport{}
+		for _, existing := range room.ReportScores[reportedID] {
+			if existing.CreatedAt.After(since) {
+				reports = append(reports, existing)
+			}
+		}
+		reports = append(reports, report)
+
+		return tx.Update(roomRef, []firestore.Update{
+			{FieldPath: firestore.FieldPath{"reportScores", reportedID}, Value: reports},
+		})
+	})
+}
+
+// ClearReportScore removes a user's report score in a room
+func (r *ReportRepository) ClearReportScore(roomID, userID string) error {
 	ctx := context.Background()
 
-	// Update the reportedUsers field in the room document
 	_, err := r.FirestoreClient.Client.
 		Collection("rooms").Doc(roomID).
 		Update(ctx, []firestore.Update{
-			{Path: "reportedUsers", Value: reportedUsers},
+			{FieldPath: firestore.FieldPath{"reportScores", userID}, Value: firestore.Delete},
 		})
 
 	if err != nil {
-		return fmt.Errorf("error updating room reported users: %v", err)
+		return fmt.Errorf("error clearing report score: %v", err)
 	}
 
 	return nil
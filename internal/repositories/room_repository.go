@@ -2,13 +2,17 @@ package repositories
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"log"
 	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/Parchat/backend/internal/config"
 	"github.com/Parchat/backend/internal/models"
 	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
 )
 
 // RoomRepository maneja las operaciones de base de datos para las salas
@@ -43,6 +47,8 @@ func (r *RoomRepository) CreateRoom(room *models.Room) error {
 		return err
 	}
 
+	recordMembershipChange(r.FirestoreClient.Client, room.ID, len(room.Members), 0)
+
 	return nil
 }
 
@@ -77,49 +83,118 @@ func (r *RoomRepository) UpdateLastMessage(roomID string, message *models.Messag
 	return err
 }
 
-// HasRoomAccess verifica si un usuario tiene acceso a una sala
-func (r *RoomRepository) HasRoomAccess(room *models.Room, userID string) bool {
-	// Verificar si el usuario es miembro
-	for _, member := range room.Members {
-		if member == userID {
-			return true
-		}
-	}
+// UpdateRoomSettings actualiza los datos editables de una sala
+func (r *RoomRepository) UpdateRoomSettings(room *models.Room) error {
+	ctx := context.Background()
 
-	// Verificar si el usuario es admin
-	for _, admin := range room.Admins {
-		if admin == userID {
-			return true
-		}
-	}
+	room.UpdatedAt = time.Now()
+
+	_, err := r.FirestoreClient.Client.Collection("rooms").Doc(room.ID).Update(ctx, []firestore.Update{
+		{Path: "name", Value: room.Name},
+		{Path: "description", Value: room.Description},
+		{Path: "imageUrl", Value: room.ImageURL},
+		{Path: "isPrivate", Value: room.IsPrivate},
+		{Path: "categories", Value: room.Categories},
+		{Path: "searchTokens", Value: room.SearchTokens},
+		{Path: "slowModeSeconds", Value: room.SlowModeSeconds},
+		{Path: "announcementOnly", Value: room.AnnouncementOnly},
+		{Path: "allowThreadReplies", Value: room.AllowThreadReplies},
+		{Path: "reportThreshold", Value: room.ReportThreshold},
+		{Path: "reportWindowHours", Value: room.ReportWindowHours},
+		{Path: "updatedAt", Value: room.UpdatedAt},
+	})
+
+	return err
+}
+
+// UpdateContentFilter reemplaza la configuración del filtro de contenido de una sala
+func (r *RoomRepository) UpdateContentFilter(roomID string, settings *models.ContentFilterSettings) error {
+	ctx := context.Background()
+
+	_, err := r.FirestoreClient.Client.Collection("rooms").Doc(roomID).Update(ctx, []firestore.Update{
+		{Path: "contentFilter", Value: settings},
+		{Path: "updatedAt", Value: time.Now()},
+	})
 
-	// Verificar si el usuario es el propietario
-	return room.OwnerID == userID
+	return err
+}
+
+// SoftDeleteRoom marca una sala como eliminada sin borrar sus datos
+func (r *RoomRepository) SoftDeleteRoom(roomID string) error {
+	ctx := context.Background()
+
+	_, err := r.FirestoreClient.Client.Collection("rooms").Doc(roomID).Update(ctx, []firestore.Update{
+		{Path: "isDeleted", Value: true},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+
+	return err
+}
+
+// HasRoomAccess verifica si un usuario tiene acceso a una sala, es decir, si tiene algún rol en ella
+func (r *RoomRepository) HasRoomAccess(room *models.Room, userID string) bool {
+	return room.RoleOf(userID) != ""
 }
 
 // CanJoinRoomWebSocket verifica si un usuario puede conectarse a una sala por WebSocket
 func (r *RoomRepository) CanJoinRoomWebSocket(roomID string, userID string) bool {
 	room, err := r.GetRoom(roomID)
-	if err != nil {
+	if err != nil || room.IsDeleted {
 		return false
 	}
 
-	// Si la sala no es privada, cualquiera puede unirse
-	if !room.IsPrivate {
-		return true
+	// Los usuarios baneados no pueden escuchar la sala
+	if room.IsBanned(userID, time.Now()) {
+		return false
 	}
 
+	// Solo los miembros escuchan la sala, también en las públicas, para que una expulsión corte la suscripción
 	return r.HasRoomAccess(room, userID)
 }
 
-// CanTalkInRoom verifica si un usuario puede hablar en una sala
-func (r *RoomRepository) CanTalkInRoomWebSocket(roomID string, userID string) bool {
-	room, err := r.GetRoom(roomID)
+// ShareRoom indica si dos usuarios son miembros de alguna sala activa en común. Solo lee los IDs de las
+// salas de cada uno, sin sus listas de miembros, porque se comprueba antes de cada envío de un chat directo
+func (r *RoomRepository) ShareRoom(userID1, userID2 string) (bool, error) {
+	rooms1, err := r.activeRoomIDs(userID1)
+	if err != nil || len(rooms1) == 0 {
+		return false, err
+	}
+
+	rooms2, err := r.activeRoomIDs(userID2)
 	if err != nil {
-		return false
+		return false, err
 	}
 
-	return r.HasRoomAccess(room, userID)
+	for roomID := range rooms2 {
+		if rooms1[roomID] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// activeRoomIDs devuelve los IDs de las salas no eliminadas de las que es miembro un usuario
+func (r *RoomRepository) activeRoomIDs(userID string) (map[string]bool, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.Collection("rooms").
+		Where("members", "array-contains", userID).
+		Select("isDeleted").
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		if deleted, _ := doc.Data()["isDeleted"].(bool); deleted {
+			continue
+		}
+		ids[doc.Ref.ID] = true
+	}
+
+	return ids, nil
 }
 
 // GetUserRooms obtiene todas las salas a las que pertenece un usuario
@@ -140,6 +215,9 @@ func (r *RoomRepository) GetUserRooms(userID string) ([]models.Room, error) {
 		if err := doc.DataTo(&room); err != nil {
 			return nil, err
 		}
+		if room.IsDeleted {
+			continue
+		}
 		rooms = append(rooms, room)
 	}
 
@@ -152,7 +230,7 @@ func (r *RoomRepository) GetUserRooms(userID string) ([]models.Room, error) {
 
 	for _, doc := range adminDocs {
 		var room models.Room
-		if err := doc.DataTo(&room); err != nil {
+		if err := doc.DataTo(&room); err != nil || room.IsDeleted {
 			continue // Ya podría estar en la lista
 		}
 
@@ -179,7 +257,7 @@ func (r *RoomRepository) GetUserRooms(userID string) ([]models.Room, error) {
 
 	for _, doc := range ownerDocs {
 		var room models.Room
-		if err := doc.DataTo(&room); err != nil {
+		if err := doc.DataTo(&room); err != nil || room.IsDeleted {
 			continue
 		}
 
@@ -200,16 +278,362 @@ func (r *RoomRepository) GetUserRooms(userID string) ([]models.Room, error) {
 	return rooms, nil
 }
 
-// GetAllRooms obtiene todas las salas ordenadas por fecha de actualización
-func (r *RoomRepository) GetAllRooms() ([]models.Room, error) {
+// roomCursor es la posición de la última sala leída en el descubrimiento
+type roomCursor struct {
+	UpdatedAt   time.Time `json:"u"`
+	MemberCount int       `json:"m"`
+	ID          string    `json:"id"`
+}
+
+// maxDiscoveryScanFactor limita cuántos documentos se leen por página cuando hay filtros que se aplican en memoria
+const maxDiscoveryScanFactor = 10
+
+// DiscoverRooms obtiene una página de salas públicas y no eliminadas.
+// Firestore solo admite un filtro array-contains por consulta, así que se filtra por el término de búsqueda
+// más largo (o por la categoría) y el resto de filtros se aplican en memoria
+func (r *RoomRepository) DiscoverRooms(q models.RoomDiscoveryQuery) ([]models.Room, string, error) {
 	ctx := context.Background()
 
-	// Consultar todas las salas ordenadas por updatedAt descendente
 	query := r.FirestoreClient.Client.Collection("rooms").
-		//Where("isDeleted", "==", false).
-		OrderBy("updatedAt", firestore.Desc)
+		Where("isDeleted", "==", false).
+		Where("isPrivate", "==", false)
+
+	terms := models.SearchTerms(q.Search)
+	primaryTerm := ""
+	for _, term := range terms {
+		if len(term) > len(primaryTerm) {
+			primaryTerm = term
+		}
+	}
+
+	switch {
+	case primaryTerm != "":
+		query = query.Where("searchTokens", "array-contains", primaryTerm)
+	case q.Category != "":
+		query = query.Where("categories", "array-contains", q.Category)
+	}
+
+	if q.Sort == models.RoomSortMembers {
+		query = query.OrderBy("memberCount", firestore.Desc)
+	}
+	query = query.OrderBy("updatedAt", firestore.Desc).OrderBy(firestore.DocumentID, firestore.Desc)
+
+	if q.Cursor != "" {
+		cursor, err := decodeRoomCursor(q.Cursor)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid cursor")
+		}
+		if q.Sort == models.RoomSortMembers {
+			query = query.StartAfter(cursor.MemberCount, cursor.UpdatedAt, cursor.ID)
+		} else {
+			query = query.StartAfter(cursor.UpdatedAt, cursor.ID)
+		}
+	}
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	rooms := []models.Room{}
+	var last *models.Room
+	maxScan := q.Limit * maxDiscoveryScanFactor
+	for scanned := 0; ; scanned++ {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return rooms, "", nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		// Ya hay una página completa o se alcanzó el límite de lectura: hay más resultados después de last
+		if len(rooms) == q.Limit || scanned == maxScan {
+			return rooms, encodeRoomCursor(last), nil
+		}
+
+		var room models.Room
+		if err := doc.DataTo(&room); err != nil {
+			return nil, "", err
+		}
+		last = &room
+
+		if matchesDiscoveryFilters(&room, terms, q.Category) {
+			rooms = append(rooms, room)
+		}
+	}
+}
+
+// matchesDiscoveryFilters aplica en memoria los filtros que no forman parte de la consulta
+func matchesDiscoveryFilters(room *models.Room, terms []string, category string) bool {
+	for _, term := range terms {
+		if !containsValue(room.SearchTokens, term) {
+			return false
+		}
+	}
+
+	return category == "" || containsValue(room.Categories, category)
+}
+
+// encodeRoomCursor codifica la posición de una sala como cursor opaco
+func encodeRoomCursor(room *models.Room) string {
+	data, _ := json.Marshal(roomCursor{
+		UpdatedAt:   room.UpdatedAt,
+		MemberCount: room.MemberCount,
+		ID:          room.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeRoomCursor decodifica un cursor generado por encodeRoomCursor
+func decodeRoomCursor(value string) (*roomCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor roomCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+// containsValue verifica si un slice contiene un valor
+func containsValue(slice []string, value string) bool {
+	for _, item := range slice {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// memberJoinUpdates comprueba que un usuario puede entrar en una sala y devuelve los cambios que lo añaden
+// como miembro. Lo comparten todas las vías de entrada, también las que lo escriben dentro de una transacción
+func memberJoinUpdates(room *models.Room, userID string, now time.Time) ([]firestore.Update, error) {
+	if room.IsDeleted {
+		return nil, fmt.Errorf("room not found")
+	}
+
+	// Ninguna vía de entrada (invitaciones, solicitudes o espacios) admite a usuarios baneados
+	if room.IsBanned(userID, now) {
+		return nil, fmt.Errorf("user is banned from the room")
+	}
+
+	if room.RoleOf(userID) != "" {
+		return nil, fmt.Errorf("user is already a member of the room")
+	}
+
+	return []firestore.Update{
+		{Path: "members", Value: firestore.ArrayUnion(userID)},
+		{FieldPath: firestore.FieldPath{"memberRoles", userID}, Value: models.RoleMember},
+		{FieldPath: firestore.FieldPath{"joinedAt", userID}, Value: now},
+		{Path: "memberCount", Value: firestore.Increment(1)},
+		{Path: "updatedAt", Value: now},
+	}, nil
+}
+
+// AddMemberToRoom añade un usuario como miembro de una sala. La comprobación y la escritura van en una
+// transacción para que dos uniones simultáneas no cuenten dos veces al mismo miembro
+func (r *RoomRepository) AddMemberToRoom(roomID string, userID string) error {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+	roomRef := client.Collection("rooms").Doc(roomID)
+
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(roomRef)
+		if err != nil {
+			return err
+		}
+
+		var room models.Room
+		if err := doc.DataTo(&room); err != nil {
+			return err
+		}
+
+		updates, err := memberJoinUpdates(&room, userID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		return tx.Update(roomRef, updates)
+	})
+	if err != nil {
+		return err
+	}
+
+	recordMembershipChange(client, roomID, 1, 0)
+
+	return nil
+}
 
-	docs, err := query.Documents(ctx).GetAll()
+// RemoveMemberFromRoom quita a un usuario de una sala dentro de una transacción. El contador de miembros
+// solo baja si estaba en la lista de miembros, para que dos salidas simultáneas no lo resten dos veces
+func (r *RoomRepository) RemoveMemberFromRoom(roomID string, userID string) error {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+	roomRef := client.Collection("rooms").Doc(roomID)
+
+	wasMember := false
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(roomRef)
+		if err != nil {
+			return fmt.Errorf("room not found")
+		}
+
+		var room models.Room
+		if err := doc.DataTo(&room); err != nil {
+			return err
+		}
+
+		_, hasRole := room.MemberRoles[userID]
+		wasMember = containsValue(room.Members, userID)
+		if !wasMember && !hasRole && !containsValue(room.Admins, userID) {
+			return fmt.Errorf("user is not a member of the room")
+		}
+
+		updates := []firestore.Update{
+			{Path: "members", Value: firestore.ArrayRemove(userID)},
+			{Path: "admins", Value: firestore.ArrayRemove(userID)},
+			{FieldPath: firestore.FieldPath{"memberRoles", userID}, Value: firestore.Delete},
+			{FieldPath: firestore.FieldPath{"adminSince", userID}, Value: firestore.Delete},
+			{FieldPath: firestore.FieldPath{"joinedAt", userID}, Value: firestore.Delete},
+			{Path: "updatedAt", Value: time.Now()},
+		}
+		if wasMember {
+			updates = append(updates, firestore.Update{Path: "memberCount", Value: firestore.Increment(-1)})
+		}
+
+		return tx.Update(roomRef, updates)
+	})
+	if err != nil {
+		return err
+	}
+
+	if wasMember {
+		recordMembershipChange(client, roomID, 0, 1)
+	}
+
+	return nil
+}
+
+// SetMemberRole asigna un rol a un miembro de la sala. La lista de admins se mantiene
+// sincronizada con el rol "admin" para las consultas existentes, y quien solo estaba en la
+// lista de admins pasa a la de miembros para no perder el acceso al dejar de ser admin
+func (r *RoomRepository) SetMemberRole(roomID string, userID string, role string) error {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+	roomRef := client.Collection("rooms").Doc(roomID)
+
+	return client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(roomRef)
+		if err != nil {
+			return fmt.Errorf("room not found")
+		}
+
+		var room models.Room
+		if err := doc.DataTo(&room); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		updates := []firestore.Update{
+			{FieldPath: firestore.FieldPath{"memberRoles", userID}, Value: role},
+			{Path: "updatedAt", Value: now},
+		}
+
+		if !containsValue(room.Members, userID) {
+			updates = append(updates,
+				firestore.Update{Path: "members", Value: firestore.ArrayUnion(userID)},
+				firestore.Update{Path: "memberCount", Value: firestore.Increment(1)},
+			)
+		}
+
+		if role == models.RoleAdmin {
+			updates = append(updates,
+				firestore.Update{Path: "admins", Value: firestore.ArrayUnion(userID)},
+				firestore.Update{FieldPath: firestore.FieldPath{"adminSince", userID}, Value: now},
+			)
+		} else {
+			updates = append(updates,
+				firestore.Update{Path: "admins", Value: firestore.ArrayRemove(userID)},
+				firestore.Update{FieldPath: firestore.FieldPath{"adminSince", userID}, Value: firestore.Delete},
+			)
+		}
+
+		return tx.Update(roomRef, updates)
+	})
+}
+
+// SaveRoomRole crea o actualiza la definición de un rol de la sala
+func (r *RoomRepository) SaveRoomRole(roomID string, role models.RoomRole) error {
+	ctx := context.Background()
+
+	_, err := r.FirestoreClient.Client.Collection("rooms").Doc(roomID).Update(ctx, []firestore.Update{
+		{FieldPath: firestore.FieldPath{"roles", role.Name}, Value: role},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+
+	return err
+}
+
+// DeleteRoomRole elimina la definición de un rol de la sala y devuelve a sus miembros al rol "member"
+func (r *RoomRepository) DeleteRoomRole(roomID string, roleName string, affectedUserIDs []string) error {
+	ctx := context.Background()
+
+	updates := []firestore.Update{
+		{FieldPath: firestore.FieldPath{"roles", roleName}, Value: firestore.Delete},
+		{Path: "updatedAt", Value: time.Now()},
+	}
+	for _, userID := range affectedUserIDs {
+		updates = append(updates, firestore.Update{FieldPath: firestore.FieldPath{"memberRoles", userID}, Value: models.RoleMember})
+	}
+
+	_, err := r.FirestoreClient.Client.Collection("rooms").Doc(roomID).Update(ctx, updates)
+
+	return err
+}
+
+// SetOwnershipTransfer guarda la transferencia de propiedad pendiente de una sala, o la elimina si es nil
+func (r *RoomRepository) SetOwnershipTransfer(roomID string, transfer *models.OwnershipTransfer) error {
+	ctx := context.Background()
+
+	var value interface{} = firestore.Delete
+	if transfer != nil {
+		value = transfer
+	}
+
+	_, err := r.FirestoreClient.Client.Collection("rooms").Doc(roomID).Update(ctx, []firestore.Update{
+		{Path: "ownershipTransfer", Value: value},
+	})
+
+	return err
+}
+
+// TransferOwnership asigna un nuevo propietario a la sala. El nuevo propietario queda como admin y miembro,
+// y el anterior se mantiene como admin
+func (r *RoomRepository) TransferOwnership(roomID string, newOwnerID string, previousOwnerID string) error {
+	ctx := context.Background()
+
+	now := time.Now()
+	_, err := r.FirestoreClient.Client.Collection("rooms").Doc(roomID).Update(ctx, []firestore.Update{
+		{Path: "ownerId", Value: newOwnerID},
+		{Path: "members", Value: firestore.ArrayUnion(newOwnerID)},
+		{FieldPath: firestore.FieldPath{"memberRoles", newOwnerID}, Value: models.RoleOwner},
+		{FieldPath: firestore.FieldPath{"memberRoles", previousOwnerID}, Value: models.RoleAdmin},
+		{Path: "admins", Value: firestore.ArrayUnion(newOwnerID, previousOwnerID)},
+		{FieldPath: firestore.FieldPath{"adminSince", previousOwnerID}, Value: now},
+		{Path: "ownershipTransfer", Value: firestore.Delete},
+		{Path: "updatedAt", Value: now},
+	})
+
+	return err
+}
+
+// GetRoomsOwnedBy obtiene las salas no eliminadas de las que un usuario es propietario
+func (r *RoomRepository) GetRoomsOwnedBy(userID string) ([]models.Room, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.Collection("rooms").Where("ownerId", "==", userID).Documents(ctx).GetAll()
 	if err != nil {
 		return nil, err
 	}
@@ -217,8 +641,8 @@ func (r *RoomRepository) GetAllRooms() ([]models.Room, error) {
 	var rooms []models.Room
 	for _, doc := range docs {
 		var room models.Room
-		if err := doc.DataTo(&room); err != nil {
-			return nil, err
+		if err := doc.DataTo(&room); err != nil || room.IsDeleted {
+			continue
 		}
 		rooms = append(rooms, room)
 	}
@@ -226,33 +650,125 @@ func (r *RoomRepository) GetAllRooms() ([]models.Room, error) {
 	return rooms, nil
 }
 
-// AddMemberToRoom añade un usuario como miembro de una sala
-func (r *RoomRepository) AddMemberToRoom(roomID string, userID string) error {
+// GetSpaceChannels obtiene los canales no eliminados de un espacio
+func (r *RoomRepository) GetSpaceChannels(spaceID string) ([]models.Room, error) {
 	ctx := context.Background()
 
-	// Obtener la sala
-	room, err := r.GetRoom(roomID)
+	docs, err := r.FirestoreClient.Client.Collection("rooms").Where("spaceId", "==", spaceID).Documents(ctx).GetAll()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Verificar si el usuario es miembro
-	for _, member := range room.Members {
-		if member == userID {
-			return fmt.Errorf("user is already a member of the room")
+	var rooms []models.Room
+	for _, doc := range docs {
+		var room models.Room
+		if err := doc.DataTo(&room); err != nil || room.IsDeleted {
+			continue
 		}
+		rooms = append(rooms, room)
 	}
 
-	// Si la sala es privada, no puede unirse
-	// if room.IsPrivate {
-	// 	return fmt.Errorf("room is private, user cannot join")
-	// }
+	return rooms, nil
+}
 
-	// Añadir el usuario como miembro y actualizar la fecha de modificación
-	_, err = r.FirestoreClient.Client.Collection("rooms").Doc(roomID).Update(ctx, []firestore.Update{
-		{Path: "members", Value: firestore.ArrayUnion(userID)},
+// SetSpaceAdmin añade o quita a un usuario de los admins heredados del espacio de un canal
+func (r *RoomRepository) SetSpaceAdmin(roomID string, userID string, isAdmin bool) error {
+	ctx := context.Background()
+
+	var value interface{} = firestore.ArrayRemove(userID)
+	if isAdmin {
+		value = firestore.ArrayUnion(userID)
+	}
+
+	_, err := r.FirestoreClient.Client.Collection("rooms").Doc(roomID).Update(ctx, []firestore.Update{
+		{Path: "spaceAdmins", Value: value},
 		{Path: "updatedAt", Value: time.Now()},
 	})
 
 	return err
 }
+
+// MigrateRooms completa los campos de las salas creadas antes de que existieran:
+// los roles predefinidos a partir del propietario y las listas de admins y miembros,
+// y el número de miembros, las categorías y los prefijos de búsqueda del descubrimiento.
+// Solo se escribe si la sala no cambió desde que se leyó; las que cambiaron se migran en el siguiente arranque
+func (r *RoomRepository) MigrateRooms() error {
+	ctx := context.Background()
+
+	iter := r.FirestoreClient.Client.Collection("rooms").Documents(ctx)
+	defer iter.Stop()
+
+	migrated := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error iterating rooms: %v", err)
+		}
+
+		var room models.Room
+		if err := doc.DataTo(&room); err != nil {
+			log.Printf("Error converting document to room: %v", err)
+			continue
+		}
+
+		var updates []firestore.Update
+
+		if room.MemberRoles == nil {
+			memberRoles := make(map[string]string)
+			for _, memberID := range room.Members {
+				memberRoles[memberID] = models.RoleMember
+			}
+			for _, adminID := range room.Admins {
+				memberRoles[adminID] = models.RoleAdmin
+			}
+			if room.OwnerID != "" {
+				memberRoles[room.OwnerID] = models.RoleOwner
+			}
+			updates = append(updates, firestore.Update{Path: "memberRoles", Value: memberRoles})
+		}
+
+		if room.SearchTokens == nil {
+			room.RefreshSearchTokens()
+			updates = append(updates,
+				firestore.Update{Path: "searchTokens", Value: room.SearchTokens},
+				firestore.Update{Path: "memberCount", Value: len(room.Members)},
+			)
+			if room.Categories == nil {
+				updates = append(updates, firestore.Update{Path: "categories", Value: []string{}})
+			}
+		}
+
+		// Los contadores de reportes antiguos no tenían fecha: pasan a ser reportes de peso 1 que
+		// empiezan a caducar desde la migración
+		if room.ReportedUsers != nil {
+			now := time.Now()
+			for userID, count := range room.ReportedUsers {
+				reports := room.ReportScores[userID]
+				for i := 0; i < count; i++ {
+					reports = append(reports, models.WeightedReport{Weight: 1, CreatedAt: now})
+				}
+				updates = append(updates, firestore.Update{FieldPath: firestore.FieldPath{"reportScores", userID}, Value: reports})
+			}
+			updates = append(updates, firestore.Update{Path: "reportedUsers", Value: firestore.Delete})
+		}
+
+		if len(updates) == 0 {
+			continue
+		}
+
+		if _, err := doc.Ref.Update(ctx, updates, firestore.LastUpdateTime(doc.UpdateTime)); err != nil {
+			log.Printf("Error migrating room %s: %v", room.ID, err)
+			continue
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		log.Printf("Migrated %d rooms", migrated)
+	}
+
+	return nil
+}
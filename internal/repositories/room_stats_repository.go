@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+)
+
+// RoomStatsRepository lee los contadores diarios de actividad de las salas. Los repositorios
+// de mensajes, salas, invitaciones y reportes los incrementan al guardar cada evento
+type RoomStatsRepository struct {
+	FirestoreClient *config.FirestoreClient
+}
+
+// NewRoomStatsRepository crea una nueva instancia de RoomStatsRepository
+func NewRoomStatsRepository(client *config.FirestoreClient) *RoomStatsRepository {
+	return &RoomStatsRepository{
+		FirestoreClient: client,
+	}
+}
+
+// GetDailyStats obtiene los contadores de los días entre from y to (inclusive), en orden cronológico.
+// Los días sin actividad no tienen documento
+func (r *RoomStatsRepository) GetDailyStats(roomID string, from, to time.Time) ([]models.RoomDailyStats, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.
+		Collection("rooms").Doc(roomID).
+		Collection("stats").
+		Where("date", ">=", from.UTC().Format(models.StatsDateLayout)).
+		Where("date", "<=", to.UTC().Format(models.StatsDateLayout)).
+		OrderBy("date", firestore.Asc).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting room stats: %v", err)
+	}
+
+	stats := make([]models.RoomDailyStats, 0, len(docs))
+	for _, doc := range docs {
+		var day models.RoomDailyStats
+		if err := doc.DataTo(&day); err != nil {
+			return nil, err
+		}
+		stats = append(stats, day)
+	}
+
+	return stats, nil
+}
+
+// CountRoomReports cuenta los reportes de la sala en la colección reports sin leer los documentos
+func (r *RoomStatsRepository) CountRoomReports(roomID string) (int, error) {
+	ctx := context.Background()
+
+	query := r.FirestoreClient.Client.Collection("reports").Where("roomId", "==", roomID)
+	result, err := query.NewAggregationQuery().
+		WithCount("total").
+		Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error counting reports: %v", err)
+	}
+
+	count, ok := result["total"].(*firestorepb.Value)
+	if !ok {
+		return 0, fmt.Errorf("error counting reports: unexpected result")
+	}
+
+	return int(count.GetIntegerValue()), nil
+}
+
+// recordMessageStats suma un mensaje del usuario al día y la hora en que se envió
+func recordMessageStats(client *firestore.Client, message *models.Message) {
+	at := message.CreatedAt.UTC()
+	err := incrementRoomStats(client, message.RoomID, at, map[string]interface{}{
+		"messages": firestore.Increment(1),
+		"senders":  map[string]interface{}{message.UserID: firestore.Increment(1)},
+		"hours":    map[string]interface{}{at.Format("15"): firestore.Increment(1)},
+	})
+	if err != nil {
+		log.Printf("Error recording message stats for room %s: %v", message.RoomID, err)
+	}
+}
+
+// recordReportStats suma un reporte al día en que se creó
+func recordReportStats(client *firestore.Client, report *models.Report) {
+	err := incrementRoomStats(client, report.RoomID, report.CreatedAt, map[string]interface{}{
+		"reports": firestore.Increment(1),
+	})
+	if err != nil {
+		log.Printf("Error recording report stats for room %s: %v", report.RoomID, err)
+	}
+}
+
+// recordMembershipChange suma entradas o salidas de miembros al día actual.
+// Como el resto de contadores, un error solo se registra para no romper la operación principal
+func recordMembershipChange(client *firestore.Client, roomID string, joined, left int) {
+	fields := map[string]interface{}{}
+	if joined > 0 {
+		fields["membersJoined"] = firestore.Increment(joined)
+	}
+	if left > 0 {
+		fields["membersLeft"] = firestore.Increment(left)
+	}
+
+	if err := incrementRoomStats(client, roomID, time.Now(), fields); err != nil {
+		log.Printf("Error recording membership stats for room %s: %v", roomID, err)
+	}
+}
+
+// incrementRoomStats aplica incrementos al documento del día, creándolo si no existe
+func incrementRoomStats(client *firestore.Client, roomID string, at time.Time, fields map[string]interface{}) error {
+	ctx := context.Background()
+
+	date := at.UTC().Format(models.StatsDateLayout)
+	fields["date"] = date
+
+	_, err := client.
+		Collection("rooms").Doc(roomID).
+		Collection("stats").Doc(date).
+		Set(ctx, fields, firestore.MergeAll)
+
+	return err
+}
@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+)
+
+// SanctionRepository maneja los silencios y baneos impuestos por los moderadores de las salas
+type SanctionRepository struct {
+	FirestoreClient *config.FirestoreClient
+}
+
+// NewSanctionRepository crea una nueva instancia de SanctionRepository
+func NewSanctionRepository(client *config.FirestoreClient) *SanctionRepository {
+	return &SanctionRepository{
+		FirestoreClient: client,
+	}
+}
+
+// sanctionField devuelve el mapa de la sala en el que se guardan las sanciones activas de un tipo
+func sanctionField(sanctionType string) string {
+	if sanctionType == models.SanctionBan {
+		return "bans"
+	}
+	return "mutes"
+}
+
+// activeSanctions devuelve las sanciones activas de un tipo de una sala
+func activeSanctions(room *models.Room, sanctionType string) map[string]models.RoomSanction {
+	if sanctionType == models.SanctionBan {
+		return room.Bans
+	}
+	return room.Mutes
+}
+
+// CreateSanction guarda una sanción en el historial de la sala y la activa. Si el usuario ya tenía una
+// sanción activa del mismo tipo, esta la reemplaza y la anterior queda levantada por el mismo moderador
+func (r *SanctionRepository) CreateSanction(sanction *models.RoomSanction) error {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+	roomRef := client.Collection("rooms").Doc(sanction.RoomID)
+	sanctionsRef := roomRef.Collection("sanctions")
+
+	return client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(roomRef)
+		if err != nil {
+			return fmt.Errorf("room not found")
+		}
+
+		var room models.Room
+		if err := doc.DataTo(&room); err != nil {
+			return err
+		}
+
+		if previous, ok := activeSanctions(&room, sanction.Type)[sanction.UserID]; ok && previous.ID != "" {
+			err := tx.Update(sanctionsRef.Doc(previous.ID), []firestore.Update{
+				{Path: "active", Value: false},
+				{Path: "liftedBy", Value: sanction.ActorID},
+				{Path: "liftedAt", Value: sanction.CreatedAt},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Set(sanctionsRef.Doc(sanction.ID), sanction); err != nil {
+			return err
+		}
+
+		return tx.Update(roomRef, []firestore.Update{
+			{FieldPath: firestore.FieldPath{sanctionField(sanction.Type), sanction.UserID}, Value: sanction},
+		})
+	})
+}
+
+// LiftSanction levanta la sanción activa de un tipo de un usuario. Devuelve nil si no tenía ninguna
+func (r *SanctionRepository) LiftSanction(roomID, userID, sanctionType, actorID string) (*models.RoomSanction, error) {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+	roomRef := client.Collection("rooms").Doc(roomID)
+
+	var lifted *models.RoomSanction
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		lifted = nil
+
+		doc, err := tx.Get(roomRef)
+		if err != nil {
+			return fmt.Errorf("room not found")
+		}
+
+		var room models.Room
+		if err := doc.DataTo(&room); err != nil {
+			return err
+		}
+
+		sanction, ok := activeSanctions(&room, sanctionType)[userID]
+		if !ok {
+			return nil
+		}
+
+		now := time.Now()
+		if sanction.ID != "" {
+			err := tx.Update(roomRef.Collection("sanctions").Doc(sanction.ID), []firestore.Update{
+				{Path: "active", Value: false},
+				{Path: "liftedBy", Value: actorID},
+				{Path: "liftedAt", Value: now},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		sanction.Active = false
+		sanction.LiftedBy = actorID
+		sanction.LiftedAt = &now
+		lifted = &sanction
+
+		return tx.Update(roomRef, []firestore.Update{
+			{FieldPath: firestore.FieldPath{sanctionField(sanctionType), userID}, Value: firestore.Delete},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lifted, nil
+}
+
+// GetExpiredSanctions obtiene las sanciones de todas las salas que siguen activas pero ya caducaron.
+// Los baneos permanentes no tienen fecha de fin y nunca aparecen
+func (r *SanctionRepository) GetExpiredSanctions(now time.Time) ([]models.RoomSanction, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.CollectionGroup("sanctions").
+		Where("active", "==", true).
+		Where("expiresAt", "<=", now).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting expired sanctions: %v", err)
+	}
+
+	sanctions := make([]models.RoomSanction, 0, len(docs))
+	for _, doc := range docs {
+		var sanction models.RoomSanction
+		if err := doc.DataTo(&sanction); err != nil {
+			return nil, err
+		}
+		sanctions = append(sanctions, sanction)
+	}
+
+	return sanctions, nil
+}
+
+// ExpireSanction desactiva una sanción caducada. Solo la quita de la sala si sigue siendo la sanción
+// activa del usuario, para no levantar una posterior. Devuelve si se quitó de la sala
+func (r *SanctionRepository) ExpireSanction(sanction models.RoomSanction) (bool, error) {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+	roomRef := client.Collection("rooms").Doc(sanction.RoomID)
+
+	removed := false
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		removed = false
+
+		doc, err := tx.Get(roomRef)
+		if err != nil {
+			return err
+		}
+
+		var room models.Room
+		if err := doc.DataTo(&room); err != nil {
+			return err
+		}
+
+		if current, ok := activeSanctions(&room, sanction.Type)[sanction.UserID]; ok && current.ID == sanction.ID {
+			err := tx.Update(roomRef, []firestore.Update{
+				{FieldPath: firestore.FieldPath{sanctionField(sanction.Type), sanction.UserID}, Value: firestore.Delete},
+			})
+			if err != nil {
+				return err
+			}
+			removed = true
+		}
+
+		return tx.Update(roomRef.Collection("sanctions").Doc(sanction.ID), []firestore.Update{
+			{Path: "active", Value: false},
+		})
+	})
+
+	return removed, err
+}
+
+// GetRoomSanctions obtiene el historial de sanciones de una sala, de la más reciente a la más antigua
+func (r *SanctionRepository) GetRoomSanctions(roomID string, limit int) ([]models.RoomSanction, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.
+		Collection("rooms").Doc(roomID).
+		Collection("sanctions").
+		OrderBy("createdAt", firestore.Desc).
+		Limit(limit).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting sanctions: %v", err)
+	}
+
+	sanctions := make([]models.RoomSanction, 0, len(docs))
+	for _, doc := range docs {
+		var sanction models.RoomSanction
+		if err := doc.DataTo(&sanction); err != nil {
+			return nil, err
+		}
+		sanctions = append(sanctions, sanction)
+	}
+
+	return sanctions, nil
+}
@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// SpaceRepository maneja las operaciones de base de datos para los espacios
+type SpaceRepository struct {
+	FirestoreClient *config.FirestoreClient
+}
+
+// NewSpaceRepository crea una nueva instancia de SpaceRepository
+func NewSpaceRepository(client *config.FirestoreClient) *SpaceRepository {
+	return &SpaceRepository{
+		FirestoreClient: client,
+	}
+}
+
+// CreateSpace crea un nuevo espacio en Firestore
+func (r *SpaceRepository) CreateSpace(space *models.Space) error {
+	ctx := context.Background()
+
+	if space.ID == "" {
+		space.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	space.CreatedAt = now
+	space.UpdatedAt = now
+
+	_, err := r.FirestoreClient.Client.Collection("spaces").Doc(space.ID).Set(ctx, space)
+	return err
+}
+
+// GetSpace obtiene un espacio por ID
+func (r *SpaceRepository) GetSpace(spaceID string) (*models.Space, error) {
+	ctx := context.Background()
+
+	doc, err := r.FirestoreClient.Client.Collection("spaces").Doc(spaceID).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("space not found")
+	}
+
+	var space models.Space
+	if err := doc.DataTo(&space); err != nil {
+		return nil, err
+	}
+
+	return &space, nil
+}
+
+// ListSpaces obtiene los espacios no eliminados, de los que tienen más miembros a los que tienen menos
+func (r *SpaceRepository) ListSpaces(limit int) ([]models.Space, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.Collection("spaces").
+		Where("isDeleted", "==", false).
+		OrderBy("memberCount", firestore.Desc).
+		Limit(limit).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting spaces: %v", err)
+	}
+
+	return decodeSpaces(docs)
+}
+
+// GetUserSpaces obtiene los espacios a los que pertenece un usuario
+func (r *SpaceRepository) GetUserSpaces(userID string) ([]models.Space, error) {
+	ctx := context.Background()
+
+	docs, err := r.FirestoreClient.Client.Collection("spaces").
+		Where("members", "array-contains", userID).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting spaces: %v", err)
+	}
+
+	spaces, err := decodeSpaces(docs)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]models.Space, 0, len(spaces))
+	for _, space := range spaces {
+		if !space.IsDeleted {
+			active = append(active, space)
+		}
+	}
+
+	return active, nil
+}
+
+// decodeSpaces convierte los documentos de Firestore en espacios
+func decodeSpaces(docs []*firestore.DocumentSnapshot) ([]models.Space, error) {
+	spaces := make([]models.Space, 0, len(docs))
+	for _, doc := range docs {
+		var space models.Space
+		if err := doc.DataTo(&space); err != nil {
+			return nil, err
+		}
+		spaces = append(spaces, space)
+	}
+	return spaces, nil
+}
+
+// AddMemberToSpace añade un usuario como miembro de un espacio
+func (r *SpaceRepository) AddMemberToSpace(spaceID string, userID string) error {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+
+	return client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		ref := client.Collection("spaces").Doc(spaceID)
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return fmt.Errorf("space not found")
+		}
+
+		var space models.Space
+		if err := doc.DataTo(&space); err != nil {
+			return err
+		}
+		if space.IsDeleted {
+			return fmt.Errorf("space not found")
+		}
+		if space.IsMember(userID) {
+			return fmt.Errorf("user is already a member of the space")
+		}
+
+		return tx.Update(ref, []firestore.Update{
+			{Path: "members", Value: firestore.ArrayUnion(userID)},
+			{Path: "memberCount", Value: firestore.Increment(1)},
+			{Path: "updatedAt", Value: time.Now()},
+		})
+	})
+}
+
+// RemoveMemberFromSpace quita a un usuario de los miembros y admins de un espacio dentro de una
+// transacción, para que dos salidas simultáneas no resten dos veces del contador de miembros
+func (r *SpaceRepository) RemoveMemberFromSpace(spaceID string, userID string) error {
+	ctx := context.Background()
+	client := r.FirestoreClient.Client
+
+	return client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		ref := client.Collection("spaces").Doc(spaceID)
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return fmt.Errorf("space not found")
+		}
+
+		var space models.Space
+		if err := doc.DataTo(&space); err != nil {
+			return err
+		}
+		if space.IsDeleted {
+			return fmt.Errorf("space not found")
+		}
+		if !space.IsMember(userID) {
+			return fmt.Errorf("user is not a member of the space")
+		}
+
+		updates := []firestore.Update{
+			{Path: "members", Value: firestore.ArrayRemove(userID)},
+			{Path: "admins", Value: firestore.ArrayRemove(userID)},
+			{Path: "updatedAt", Value: time.Now()},
+		}
+		if containsValue(space.Members, userID) {
+			updates = append(updates, firestore.Update{Path: "memberCount", Value: firestore.Increment(-1)})
+		}
+
+		return tx.Update(ref, updates)
+	})
+}
+
+// SetSpaceAdmin añade o quita a un miembro de los admins del espacio
+func (r *SpaceRepository) SetSpaceAdmin(spaceID string, userID string, isAdmin bool) error {
+	ctx := context.Background()
+
+	var value interface{} = firestore.ArrayRemove(userID)
+	if isAdmin {
+		value = firestore.ArrayUnion(userID)
+	}
+
+	_, err := r.FirestoreClient.Client.Collection("spaces").Doc(spaceID).Update(ctx, []firestore.Update{
+		{Path: "admins", Value: value},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+
+	return err
+}
+
+// AddChannel registra una sala como canal del espacio
+func (r *SpaceRepository) AddChannel(spaceID string, roomID string) error {
+	ctx := context.Background()
+
+	_, err := r.FirestoreClient.Client.Collection("spaces").Doc(spaceID).Update(ctx, []firestore.Update{
+		{Path: "channels", Value: firestore.ArrayUnion(roomID)},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"cloud.google.com/go/firestore"
 	"github.com/Parchat/backend/internal/config"
 	"github.com/Parchat/backend/internal/models"
 )
@@ -55,3 +56,136 @@ func (r *UserRepository) GetUserByID(ctx context.Context, userID string) (*model
 
 	return &user, nil
 }
+
+// MarkUserDeleted marca a un usuario como eliminado sin borrar sus datos
+func (r *UserRepository) MarkUserDeleted(ctx context.Context, userID string) error {
+	_, err := r.FirestoreClient.Client.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "isDeleted", Value: true},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+
+	return err
+}
+
+// GetUsersByIDs obtiene varios usuarios en una sola lectura. Los usuarios que no existen no aparecen en el resultado
+func (r *UserRepository) GetUsersByIDs(ctx context.Context, userIDs []string) (map[string]*models.User, error) {
+	users := make(map[string]*models.User)
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+
+	refs := make([]*firestore.DocumentRef, 0, len(userIDs))
+	for _, userID := range userIDs {
+		refs = append(refs, r.FirestoreClient.Client.Collection("users").Doc(userID))
+	}
+
+	docs, err := r.FirestoreClient.Client.GetAll(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+
+		var user models.User
+		if err := doc.DataTo(&user); err != nil {
+			return nil, err
+		}
+		users[doc.Ref.ID] = &user
+	}
+
+	return users, nil
+}
+
+// SetUserBlocked añade o quita a un usuario de la lista de bloqueados de otro
+func (r *UserRepository) SetUserBlocked(ctx context.Context, userID, targetID string, blocked bool) error {
+	var value interface{} = firestore.ArrayRemove(targetID)
+	if blocked {
+		value = firestore.ArrayUnion(targetID)
+	}
+
+	_, err := r.FirestoreClient.Client.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "blockedUsers", Value: value},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+
+	return err
+}
+
+// SetContact añade o quita a un usuario de los contactos de otro
+func (r *UserRepository) SetContact(ctx context.Context, userID, contactID string, isContact bool) error {
+	var value interface{} = firestore.ArrayRemove(contactID)
+	if isContact {
+		value = firestore.ArrayUnion(contactID)
+	}
+
+	_, err := r.FirestoreClient.Client.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "contacts", Value: value},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+
+	return err
+}
+
+// UpdateDMPrivacy cambia quién puede abrir chats directos con un usuario
+func (r *UserRepository) UpdateDMPrivacy(ctx context.Context, userID, privacy string) error {
+	_, err := r.FirestoreClient.Client.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "dmPrivacy", Value: privacy},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+
+	return err
+}
+
+// GetBlockedUsers obtiene los IDs de los usuarios que un usuario ha bloqueado
+func (r *UserRepository) GetBlockedUsers(ctx context.Context, userID string) ([]string, error) {
+	user, err := r.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, nil
+	}
+
+	return user.BlockedUsers, nil
+}
+
+// IsBlockedBetween indica si alguno de los dos usuarios ha bloqueado al otro
+func (r *UserRepository) IsBlockedBetween(ctx context.Context, userID1, userID2 string) (bool, error) {
+	users, err := r.GetUsersByIDs(ctx, []string{userID1, userID2})
+	if err != nil {
+		return false, err
+	}
+
+	if user, ok := users[userID1]; ok && user.HasBlocked(userID2) {
+		return true, nil
+	}
+	if user, ok := users[userID2]; ok && user.HasBlocked(userID1) {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// RecordReportFiled suma un reporte enviado al historial de reportes del usuario
+func (r *UserRepository) RecordReportFiled(ctx context.Context, userID string) error {
+	_, err := r.FirestoreClient.Client.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "reportRecord.filed", Value: firestore.Increment(1)},
+	})
+	return err
+}
+
+// RecordReportsDismissed suma reportes descartados por los moderadores al historial de cada usuario que los envió
+func (r *UserRepository) RecordReportsDismissed(ctx context.Context, dismissed map[string]int) error {
+	for userID, count := range dismissed {
+		_, err := r.FirestoreClient.Client.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+			{Path: "reportRecord.dismissed", Value: firestore.Increment(count)},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -19,6 +19,19 @@ func NewRouter(
 	webSocketHandler *handlers.WebSocketHandler,
 	authMw *authMiddleware.AuthMiddleware,
 	moderationHandler *handlers.ModerationHandler,
+	bookmarkHandler *handlers.BookmarkHandler,
+	roomAdminHandler *handlers.RoomAdminHandler,
+	inviteHandler *handlers.InviteHandler,
+	joinRequestHandler *handlers.JoinRequestHandler,
+	spaceHandler *handlers.SpaceHandler,
+	roomStatsHandler *handlers.RoomStatsHandler,
+	notificationHandler *handlers.NotificationHandler,
+	groupChatHandler *handlers.GroupChatHandler,
+	messageRequestHandler *handlers.MessageRequestHandler,
+	keyHandler *handlers.KeyHandler,
+	sanctionHandler *handlers.SanctionHandler,
+	auditHandler *handlers.AuditHandler,
+	contentFilterHandler *handlers.ContentFilterHandler,
 ) *chi.Mux {
 	r := chi.NewRouter()
 
@@ -51,6 +64,51 @@ func NewRouter(
 			r.Group(func(r chi.Router) {
 				r.Use(authMw.VerifyToken)                       // Aplicar middleware de autenticación
 				r.Post("/create", userHandler.EnsureUserExists) // Nueva ruta para asegurar que el usuario exista
+				r.Delete("/me", userHandler.DeleteAccount)
+
+				r.Get("/notification-preferences", notificationHandler.GetUserPreferences)
+				r.Get("/privacy", userHandler.GetPrivacySettings)
+				r.Put("/privacy", userHandler.UpdatePrivacySettings)
+
+				// Usuarios bloqueados
+				r.Route("/blocks", func(r chi.Router) {
+					r.Get("/", userHandler.GetBlockedUsers)
+					r.Post("/{userId}", userHandler.BlockUser)
+					r.Delete("/{userId}", userHandler.UnblockUser)
+				})
+
+				// Contactos
+				r.Route("/contacts", func(r chi.Router) {
+					r.Get("/", userHandler.GetContacts)
+					r.Post("/{userId}", userHandler.AddContact)
+					r.Delete("/{userId}", userHandler.RemoveContact)
+				})
+
+				// Claves de cifrado de extremo a extremo
+				r.Route("/devices", func(r chi.Router) {
+					r.Get("/", keyHandler.GetDevices)
+					r.Put("/{deviceId}/keys", keyHandler.PublishDeviceKeys)
+					r.Post("/{deviceId}/prekeys", keyHandler.UploadPreKeys)
+					r.Delete("/{deviceId}", keyHandler.DeleteDevice)
+				})
+				r.Get("/keys/{userId}", keyHandler.GetKeyBundle)
+
+				// Mensajes guardados
+				r.Route("/bookmarks", func(r chi.Router) {
+					r.Get("/", bookmarkHandler.GetBookmarks)
+					r.Post("/", bookmarkHandler.AddBookmark)
+					r.Delete("/{bookmarkId}", bookmarkHandler.RemoveBookmark)
+				})
+			})
+		})
+
+		// Rutas de invitaciones: la vista previa es pública, unirse requiere token
+		r.Route("/invites", func(r chi.Router) {
+			r.Get("/{code}", inviteHandler.PreviewInvite)
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMw.VerifyToken)
+				r.Post("/{code}/join", inviteHandler.JoinWithInvite)
 			})
 		})
 
@@ -64,6 +122,13 @@ func NewRouter(
 			})
 		})
 
+		// Rutas de los administradores de la plataforma
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(authMw.VerifyToken)
+			r.Get("/audit-log", auditHandler.GetAuditLog)
+			r.Get("/audit-log/export", auditHandler.ExportAuditLog)
+		})
+
 		// Rutas de chat (protegidas)
 		r.Route("/chat", func(r chi.Router) {
 			// WebSocket endpoint
@@ -76,24 +141,99 @@ func NewRouter(
 				r.Route("/rooms", func(r chi.Router) {
 					r.Post("/", chatHandler.CreateRoom)
 					r.Get("/me", chatHandler.GetUserRooms)
-					r.Get("/", chatHandler.GetAllRooms)
+					r.Get("/", chatHandler.DiscoverRooms)
 					r.Get("/{roomId}", chatHandler.GetRoom)
+					r.Put("/{roomId}", chatHandler.UpdateRoom)
+					r.Delete("/{roomId}", chatHandler.DeleteRoom)
 					r.Get("/{roomId}/messages", chatHandler.GetRoomMessagesSimple)
 					r.Get("/{roomId}/messages/paginated", chatHandler.GetRoomMessages)
+					r.Delete("/{roomId}/messages/{messageId}", chatHandler.DeleteMessage)
+					r.Get("/{roomId}/messages/{messageId}/thread", chatHandler.GetThread)
 					r.Post("/{roomId}/join", chatHandler.JoinRoom)
+					r.Post("/{roomId}/leave", chatHandler.LeaveRoom)
+					r.Get("/{roomId}/members", chatHandler.GetRoomMembers)
+					r.Delete("/{roomId}/members/{userId}", chatHandler.RemoveMember)
+					r.Get("/{roomId}/stats", roomStatsHandler.GetRoomStats)
+					r.Get("/{roomId}/notifications", notificationHandler.GetRoomPreference)
+					r.Put("/{roomId}/notifications", notificationHandler.UpdateRoomPreference)
+
+					// Gestión de roles y propietario
+					r.Get("/{roomId}/roles", roomAdminHandler.GetRoomRoles)
+					r.Put("/{roomId}/roles/{roleName}", roomAdminHandler.SaveRoomRole)
+					r.Delete("/{roomId}/roles/{roleName}", roomAdminHandler.DeleteRoomRole)
+					r.Put("/{roomId}/members/{userId}/role", roomAdminHandler.AssignRole)
+					r.Post("/{roomId}/admins/{userId}", roomAdminHandler.PromoteAdmin)
+					r.Delete("/{roomId}/admins/{userId}", roomAdminHandler.DemoteAdmin)
+					r.Post("/{roomId}/ownership/transfer", roomAdminHandler.RequestOwnershipTransfer)
+					r.Delete("/{roomId}/ownership/transfer", roomAdminHandler.CancelOwnershipTransfer)
+					r.Post("/{roomId}/ownership/accept", roomAdminHandler.AcceptOwnershipTransfer)
+
+					// Invitaciones
+					r.Post("/{roomId}/invites", inviteHandler.CreateInvite)
+					r.Get("/{roomId}/invites", inviteHandler.GetRoomInvites)
+					r.Delete("/{roomId}/invites/{code}", inviteHandler.RevokeInvite)
+
+					// Solicitudes para unirse a salas privadas
+					r.Post("/{roomId}/join-requests", joinRequestHandler.RequestToJoin)
+					r.Get("/{roomId}/join-requests", joinRequestHandler.GetPendingJoinRequests)
+					r.Delete("/{roomId}/join-requests/me", joinRequestHandler.CancelJoinRequest)
+					r.Post("/{roomId}/join-requests/{userId}/approve", joinRequestHandler.ApproveJoinRequest)
+					r.Post("/{roomId}/join-requests/{userId}/reject", joinRequestHandler.RejectJoinRequest)
 
 					// Moderation routes
 					r.Post("/{roomId}/report", moderationHandler.ReportMessage)
 					r.Get("/{roomId}/banned-users", moderationHandler.GetBannedUsers)
 					r.Post("/{roomId}/clear-reports", moderationHandler.ClearUserReports)
+					r.Get("/{roomId}/reports", moderationHandler.GetReportQueue)
+					r.Post("/{roomId}/reports/resolve", moderationHandler.ResolveReports)
+					r.Get("/{roomId}/moderation-actions", moderationHandler.GetModerationActions)
+					r.Get("/{roomId}/audit-log", auditHandler.GetRoomAuditLog)
+
+					// Silencios y baneos
+					r.Post("/{roomId}/members/{userId}/mute", sanctionHandler.MuteMember)
+					r.Delete("/{roomId}/members/{userId}/mute", sanctionHandler.UnmuteMember)
+					r.Post("/{roomId}/bans/{userId}", sanctionHandler.BanUser)
+					r.Delete("/{roomId}/bans/{userId}", sanctionHandler.UnbanUser)
+					r.Get("/{roomId}/sanctions", sanctionHandler.GetRoomSanctions)
+
+					// Filtro de contenido
+					r.Get("/{roomId}/content-filter", contentFilterHandler.GetContentFilter)
+					r.Put("/{roomId}/content-filter", contentFilterHandler.UpdateContentFilter)
+				})
+
+				// Rutas de espacios y sus canales
+				r.Route("/spaces", func(r chi.Router) {
+					r.Post("/", spaceHandler.CreateSpace)
+					r.Get("/", spaceHandler.ListSpaces)
+					r.Get("/me", spaceHandler.GetUserSpaces)
+					r.Get("/{spaceId}", spaceHandler.GetSpace)
+					r.Post("/{spaceId}/join", spaceHandler.JoinSpace)
+					r.Post("/{spaceId}/leave", spaceHandler.LeaveSpace)
+					r.Post("/{spaceId}/channels", spaceHandler.CreateChannel)
+					r.Put("/{spaceId}/members/{userId}/role", spaceHandler.SetMemberRole)
 				})
 
 				// Rutas de chats directos
 				r.Route("/direct", func(r chi.Router) {
+					r.Post("/group", groupChatHandler.CreateGroupDirectChat)
 					r.Post("/{otherUserId}", chatHandler.CreateDirectChat)
 					r.Get("/me", chatHandler.GetUserDirectChats)
+					r.Get("/requests", messageRequestHandler.GetMessageRequests)
 					r.Get("/{chatId}", chatHandler.GetChat)
+					r.Put("/{chatId}", groupChatHandler.UpdateDirectChat)
+					r.Delete("/{chatId}", chatHandler.DeleteDirectChat)
+					r.Post("/{chatId}/archive", chatHandler.ArchiveDirectChat)
+					r.Delete("/{chatId}/archive", chatHandler.UnarchiveDirectChat)
+					r.Post("/{chatId}/participants", groupChatHandler.AddParticipant)
+					r.Delete("/{chatId}/participants/{userId}", groupChatHandler.RemoveParticipant)
+					r.Post("/{chatId}/leave", groupChatHandler.LeaveDirectChat)
+					r.Post("/{chatId}/accept", messageRequestHandler.AcceptMessageRequest)
+					r.Post("/{chatId}/decline", messageRequestHandler.DeclineMessageRequest)
+					r.Post("/{chatId}/block", messageRequestHandler.BlockMessageRequest)
+					r.Post("/{chatId}/encryption", keyHandler.EnableDirectChatEncryption)
 					r.Get("/{chatId}/messages", chatHandler.GetDirectChatMessages)
+					r.Get("/{chatId}/notifications", notificationHandler.GetDirectChatPreference)
+					r.Put("/{chatId}/notifications", notificationHandler.UpdateDirectChatPreference)
 				})
 			})
 		})
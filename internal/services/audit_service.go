@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/repositories"
+)
+
+// AuditService maneja el registro de auditoría de las acciones de moderación y administración.
+// Los admins de una sala consultan el registro de su sala y los administradores de la plataforma el completo
+type AuditService struct {
+	AuditRepo   *repositories.AuditRepository
+	RoomRepo    *repositories.RoomRepository
+	Permissions *PermissionService
+	Config      *config.Config
+}
+
+// NewAuditService crea una nueva instancia de AuditService
+func NewAuditService(
+	auditRepo *repositories.AuditRepository,
+	roomRepo *repositories.RoomRepository,
+	permissions *PermissionService,
+	cfg *config.Config,
+) *AuditService {
+	return &AuditService{
+		AuditRepo:   auditRepo,
+		RoomRepo:    roomRepo,
+		Permissions: permissions,
+		Config:      cfg,
+	}
+}
+
+// Record añade una entrada al registro sin interrumpir la operación si falla
+func (s *AuditService) Record(entry *models.AuditEntry) {
+	if err := s.AuditRepo.AppendEntry(entry); err != nil {
+		log.Printf("Error recording audit entry %s in room %s: %v", entry.Action, entry.RoomID, err)
+	}
+}
+
+// GetRoomAuditLog devuelve una página del registro de una sala. Requiere la capacidad manage_room
+func (s *AuditService) GetRoomAuditLog(roomID, userID string, q models.AuditLogQuery) (*models.PaginatedAuditLogResponse, error) {
+	room, err := s.RoomRepo.GetRoom(roomID)
+	if err != nil || room.IsDeleted {
+		return nil, fmt.Errorf("room not found")
+	}
+
+	if !s.Permissions.Can(room, userID, models.CapManageRoom) {
+		return nil, fmt.Errorf("user is not allowed to view the audit log")
+	}
+
+	q.RoomID = roomID
+	return s.queryAuditLog(q, models.MaxAuditLogLimit)
+}
+
+// GetAuditLog devuelve una página del registro completo. Solo para administradores de la plataforma
+func (s *AuditService) GetAuditLog(userID string, q models.AuditLogQuery) (*models.PaginatedAuditLogResponse, error) {
+	if !s.Config.IsPlatformAdmin(userID) {
+		return nil, fmt.Errorf("user is not a platform admin")
+	}
+
+	return s.queryAuditLog(q, models.MaxAuditLogLimit)
+}
+
+// ExportAuditLog devuelve de una vez las entradas que cumplen los filtros, hasta MaxAuditLogExport.
+// Solo para administradores de la plataforma
+func (s *AuditService) ExportAuditLog(userID string, q models.AuditLogQuery) ([]models.AuditEntry, error) {
+	if !s.Config.IsPlatformAdmin(userID) {
+		return nil, fmt.Errorf("user is not a platform admin")
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = models.MaxAuditLogExport
+	}
+	page, err := s.queryAuditLog(q, models.MaxAuditLogExport)
+	if err != nil {
+		return nil, err
+	}
+
+	return page.Entries, nil
+}
+
+// queryAuditLog valida los filtros y consulta el registro
+func (s *AuditService) queryAuditLog(q models.AuditLogQuery, maxLimit int) (*models.PaginatedAuditLogResponse, error) {
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return nil, fmt.Errorf("invalid date range")
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = models.DefaultAuditLogLimit
+	}
+	if q.Limit > maxLimit {
+		q.Limit = maxLimit
+	}
+
+	entries, nextCursor, err := s.AuditRepo.QueryEntries(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaginatedAuditLogResponse{
+		Entries:    entries,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	}, nil
+}
+
+// changedFields devuelve solo los campos que cambiaron entre dos estados, para guardarlos como antes y después
+func changedFields(before, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	changedBefore := map[string]interface{}{}
+	changedAfter := map[string]interface{}{}
+	for key, value := range after {
+		if !reflect.DeepEqual(before[key], value) {
+			changedBefore[key] = before[key]
+			changedAfter[key] = value
+		}
+	}
+	return changedBefore, changedAfter
+}
+
+// roomSettings devuelve la configuración de una sala que se puede cambiar con UpdateRoom
+func roomSettings(room *models.Room) map[string]interface{} {
+	return map[string]interface{}{
+		"name":               room.Name,
+		"description":        room.Description,
+		"imageUrl":           room.ImageURL,
+		"isPrivate":          room.IsPrivate,
+		"categories":         append([]string{}, room.Categories...),
+		"slowModeSeconds":    room.SlowModeSeconds,
+		"announcementOnly":   room.AnnouncementOnly,
+		"allowThreadReplies": room.AllowThreadReplies,
+		"reportThreshold":    room.ReportThreshold,
+		"reportWindowHours":  room.ReportWindowHours,
+	}
+}
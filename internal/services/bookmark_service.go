@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/repositories"
+)
+
+// BookmarkService maneja la lógica de negocio relacionada con los mensajes guardados
+type BookmarkService struct {
+	BookmarkRepo   *repositories.BookmarkRepository
+	MessageRepo    *repositories.MessageRepository
+	RoomRepo       *repositories.RoomRepository
+	DirectChatRepo *repositories.DirectChatRepository
+	UserRepo       *repositories.UserRepository
+	Config         *config.Config
+}
+
+// NewBookmarkService crea una nueva instancia de BookmarkService
+func NewBookmarkService(
+	bookmarkRepo *repositories.BookmarkRepository,
+	messageRepo *repositories.MessageRepository,
+	roomRepo *repositories.RoomRepository,
+	directChatRepo *repositories.DirectChatRepository,
+	userRepo *repositories.UserRepository,
+	cfg *config.Config,
+) *BookmarkService {
+	return &BookmarkService{
+		BookmarkRepo:   bookmarkRepo,
+		MessageRepo:    messageRepo,
+		RoomRepo:       roomRepo,
+		DirectChatRepo: directChatRepo,
+		UserRepo:       userRepo,
+		Config:         cfg,
+	}
+}
+
+// AddBookmark guarda un mensaje de una conversación a la que pertenece el usuario
+func (s *BookmarkService) AddBookmark(userID string, req *models.CreateBookmarkRequest) (*models.Bookmark, error) {
+	if !s.hasConversationAccess(userID, req.ChatType, req.ChatID) {
+		return nil, fmt.Errorf("user does not belong to this conversation")
+	}
+
+	message, err := s.getMessage(req.ChatType, req.ChatID, req.MessageID)
+	if err != nil || message.IsDeleted {
+		return nil, fmt.Errorf("message not found")
+	}
+
+	// Un mensaje solo puede guardarse una vez por usuario
+	existing, err := s.BookmarkRepo.FindBookmarkByMessage(userID, req.ChatType, req.ChatID, req.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("message is already bookmarked")
+	}
+
+	bookmark := &models.Bookmark{
+		UserID:    userID,
+		MessageID: req.MessageID,
+		ChatID:    req.ChatID,
+		ChatType:  req.ChatType,
+		Note:      req.Note,
+		Message:   message,
+	}
+
+	if err := s.BookmarkRepo.CreateBookmark(bookmark); err != nil {
+		return nil, err
+	}
+
+	return bookmark, nil
+}
+
+// RemoveBookmark elimina un mensaje guardado del usuario
+func (s *BookmarkService) RemoveBookmark(userID, bookmarkID string) error {
+	if _, err := s.BookmarkRepo.GetBookmark(userID, bookmarkID); err != nil {
+		return fmt.Errorf("bookmark not found")
+	}
+
+	return s.BookmarkRepo.DeleteBookmark(userID, bookmarkID)
+}
+
+// GetUserBookmarks obtiene los mensajes guardados del usuario con paginación.
+// Los mensajes eliminados o de conversaciones que el usuario abandonó se marcan como no disponibles
+// y se omite su contenido
+func (s *BookmarkService) GetUserBookmarks(userID string, limit int, cursor string) (*models.PaginatedBookmarksResponse, error) {
+	bookmarks, nextCursor, err := s.BookmarkRepo.GetUserBookmarks(userID, limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.PaginatedBookmarksResponse{
+		Bookmarks:  []models.BookmarkResponse{},
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	}
+
+	// Cachear el acceso por conversación y los nombres de usuario para evitar lecturas duplicadas
+	accessCache := make(map[string]bool)
+	userDataCache := make(map[string]string) // userId -> displayName
+	ctx := context.Background()
+
+	for _, bookmark := range bookmarks {
+		item := models.BookmarkResponse{
+			Bookmark: bookmark,
+			Link:     s.conversationLink(bookmark.ChatType, bookmark.ChatID),
+		}
+
+		key := bookmark.ChatType + "/" + bookmark.ChatID
+		hasAccess, cached := accessCache[key]
+		if !cached {
+			hasAccess = s.hasConversationAccess(userID, bookmark.ChatType, bookmark.ChatID)
+			accessCache[key] = hasAccess
+		}
+
+		if hasAccess {
+			message, err := s.getMessage(bookmark.ChatType, bookmark.ChatID, bookmark.MessageID)
+			item.Available = err == nil && !message.IsDeleted
+		}
+
+		if !item.Available || item.Message == nil {
+			// No exponer el contenido de mensajes que ya no son accesibles
+			item.Message = nil
+		} else {
+			displayName, found := userDataCache[item.Message.UserID]
+			if !found {
+				if user, err := s.UserRepo.GetUserByID(ctx, item.Message.UserID); err == nil && user != nil {
+					displayName = user.DisplayName
+				}
+				userDataCache[item.Message.UserID] = displayName
+			}
+
+			messageCopy := *item.Message
+			messageCopy.DisplayName = displayName
+			item.Message = &messageCopy
+		}
+
+		response.Bookmarks = append(response.Bookmarks, item)
+	}
+
+	return response, nil
+}
+
+// hasConversationAccess verifica si el usuario pertenece a la sala o chat directo indicado
+func (s *BookmarkService) hasConversationAccess(userID, chatType, chatID string) bool {
+	switch chatType {
+	case models.BookmarkChatTypeRoom:
+		room, err := s.RoomRepo.GetRoom(chatID)
+		if err != nil {
+			return false
+		}
+		return s.RoomRepo.HasRoomAccess(room, userID)
+	case models.BookmarkChatTypeDirect:
+		return s.DirectChatRepo.IsUserInDirectChat(chatID, userID)
+	default:
+		return false
+	}
+}
+
+// getMessage obtiene un mensaje de una sala o de un chat directo
+func (s *BookmarkService) getMessage(chatType, chatID, messageID string) (*models.Message, error) {
+	if chatType == models.BookmarkChatTypeDirect {
+		return s.MessageRepo.GetDirectMessageByID(chatID, messageID)
+	}
+	return s.MessageRepo.GetMessageByID(chatID, messageID)
+}
+
+// conversationLink construye el enlace de la API a la conversación de un mensaje guardado
+func (s *BookmarkService) conversationLink(chatType, chatID string) string {
+	baseURL := strings.TrimRight(s.Config.ServerURL, "/") + "/api/v1/chat/"
+	if chatType == models.BookmarkChatTypeDirect {
+		return baseURL + "direct/" + chatID
+	}
+	return baseURL + "rooms/" + chatID
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/pkg/contentfilter"
+	"github.com/Parchat/backend/internal/repositories"
+)
+
+// ContentFilterService maneja los filtros de contenido que revisan los mensajes antes de guardarlos.
+// Cada sala configura sus reglas y la lista por defecto; los chats directos no cifrados usan la lista
+// por defecto con la acción de DIRECT_CHAT_FILTER_ACTION
+type ContentFilterService struct {
+	RoomRepo    *repositories.RoomRepository
+	Permissions *PermissionService
+	Moderation  *ModerationService
+	Audit       *AuditService
+
+	mu      sync.Mutex
+	filters map[string]compiledRoomFilter // Filtros ya compilados, por sala
+	direct  *contentfilter.Filter
+}
+
+// compiledRoomFilter guarda el filtro compilado de una sala junto a la configuración de la que salió,
+// para recompilarlo solo cuando cambia
+type compiledRoomFilter struct {
+	settings models.ContentFilterSettings
+	filter   *contentfilter.Filter
+}
+
+// NewContentFilterService crea una nueva instancia de ContentFilterService
+func NewContentFilterService(
+	roomRepo *repositories.RoomRepository,
+	permissions *PermissionService,
+	moderation *ModerationService,
+	audit *AuditService,
+	cfg *config.Config,
+) *ContentFilterService {
+	s := &ContentFilterService{
+		RoomRepo:    roomRepo,
+		Permissions: permissions,
+		Moderation:  moderation,
+		Audit:       audit,
+		filters:     make(map[string]compiledRoomFilter),
+	}
+
+	switch cfg.DirectChatFilterAction {
+	case contentfilter.ActionReject, contentfilter.ActionMask:
+		filter, err := contentfilter.New(contentfilter.DefaultRules(cfg.DirectChatFilterAction))
+		if err != nil {
+			log.Printf("Error compiling the direct chat content filter: %v", err)
+		}
+		s.direct = filter
+	case "":
+	default:
+		log.Printf("Unsupported DIRECT_CHAT_FILTER_ACTION %q, direct chats will not be filtered", cfg.DirectChatFilterAction)
+	}
+
+	return s
+}
+
+// GetRoomContentFilter devuelve la configuración del filtro de una sala. Requiere la capacidad manage_room
+func (s *ContentFilterService) GetRoomContentFilter(roomID, userID string) (*models.ContentFilterSettings, error) {
+	room, err := s.getRoomForContentFilter(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &models.ContentFilterSettings{Rules: []models.ContentFilterRule{}}
+	if room.ContentFilter != nil {
+		settings.DefaultListAction = room.ContentFilter.DefaultListAction
+		settings.Rules = append(settings.Rules, room.ContentFilter.Rules...)
+	}
+
+	return settings, nil
+}
+
+// UpdateRoomContentFilter reemplaza la configuración del filtro de una sala. Requiere la capacidad manage_room
+func (s *ContentFilterService) UpdateRoomContentFilter(roomID, userID string, req *models.UpdateContentFilterRequest) (*models.ContentFilterSettings, error) {
+	room, err := s.getRoomForContentFilter(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.DefaultListAction {
+	case "", contentfilter.ActionReject, contentfilter.ActionMask, contentfilter.ActionFlag:
+	default:
+		return nil, fmt.Errorf("invalid content filter action")
+	}
+
+	if len(req.Rules) > models.MaxContentFilterRules {
+		return nil, fmt.Errorf("too many content filter rules")
+	}
+
+	settings := &models.ContentFilterSettings{
+		DefaultListAction: req.DefaultListAction,
+		Rules:             make([]models.ContentFilterRule, 0, len(req.Rules)),
+	}
+	for _, rule := range req.Rules {
+		normalized, err := contentfilter.Normalize(contentfilter.Rule{Type: rule.Type, Value: rule.Value, Action: rule.Action})
+		if err != nil {
+			return nil, err
+		}
+		settings.Rules = append(settings.Rules, models.ContentFilterRule{
+			Type:   normalized.Type,
+			Value:  normalized.Value,
+			Action: normalized.Action,
+		})
+	}
+
+	if err := s.RoomRepo.UpdateContentFilter(roomID, settings); err != nil {
+		return nil, err
+	}
+
+	previous := &models.ContentFilterSettings{}
+	if room.ContentFilter != nil {
+		previous = room.ContentFilter
+	}
+	if before, after := changedFields(contentFilterSettings(previous), contentFilterSettings(settings)); len(after) > 0 {
+		s.Audit.Record(&models.AuditEntry{
+			RoomID:     roomID,
+			Action:     models.AuditContentFilterUpdated,
+			ActorID:    userID,
+			TargetType: models.AuditTargetRoom,
+			TargetID:   roomID,
+			Before:     before,
+			After:      after,
+		})
+	}
+
+	return settings, nil
+}
+
+// FilterRoomMessage revisa el texto de un mensaje con el filtro de su sala
+func (s *ContentFilterService) FilterRoomMessage(room *models.Room, content string) contentfilter.Result {
+	return s.roomFilter(room).Check(content)
+}
+
+// FilterDirectMessage revisa el texto de un mensaje de un chat directo no cifrado
+func (s *ContentFilterService) FilterDirectMessage(content string) contentfilter.Result {
+	return s.direct.Check(content)
+}
+
+// FlagRoomMessage reporta en nombre del sistema un mensaje que coincide con reglas flag, con su texto
+// original, para que los moderadores lo revisen en la cola de moderación
+func (s *ContentFilterService) FlagRoomMessage(room *models.Room, message *models.Message, content string, result contentfilter.Result) error {
+	var rules []string
+	for _, match := range result.Matches {
+		if match.Rule.Action != contentfilter.ActionFlag {
+			continue
+		}
+		rule := fmt.Sprintf("%s %q", match.Rule.Type, match.Rule.Value)
+		if !contains(rules, rule) {
+			rules = append(rules, rule)
+		}
+	}
+
+	reason := "Flagged by the content filter: " + strings.Join(rules, ", ")
+	return s.Moderation.CreateSystemReport(room, message, content, reason)
+}
+
+// roomFilter devuelve el filtro compilado de una sala, o nil si no tiene filtro
+func (s *ContentFilterService) roomFilter(room *models.Room) *contentfilter.Filter {
+	if room.ContentFilter == nil || (room.ContentFilter.DefaultListAction == "" && len(room.ContentFilter.Rules) == 0) {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cached, ok := s.filters[room.ID]; ok && reflect.DeepEqual(cached.settings, *room.ContentFilter) {
+		return cached.filter
+	}
+
+	var rules []contentfilter.Rule
+	if room.ContentFilter.DefaultListAction != "" {
+		rules = contentfilter.DefaultRules(room.ContentFilter.DefaultListAction)
+	}
+	for _, rule := range room.ContentFilter.Rules {
+		rules = append(rules, contentfilter.Rule{Type: rule.Type, Value: rule.Value, Action: rule.Action})
+	}
+
+	// Las reglas se validan al guardarlas, así que un error aquí solo puede venir de datos editados a mano
+	filter, err := contentfilter.New(rules)
+	if err != nil {
+		log.Printf("Error compiling content filter of room %s: %v", room.ID, err)
+	}
+	s.filters[room.ID] = compiledRoomFilter{settings: *room.ContentFilter, filter: filter}
+
+	return filter
+}
+
+// getRoomForContentFilter carga la sala y comprueba que el usuario puede gestionar su filtro
+func (s *ContentFilterService) getRoomForContentFilter(roomID, userID string) (*models.Room, error) {
+	room, err := s.RoomRepo.GetRoom(roomID)
+	if err != nil || room.IsDeleted {
+		return nil, fmt.Errorf("room not found")
+	}
+
+	if !s.Permissions.Can(room, userID, models.CapManageRoom) {
+		return nil, fmt.Errorf("user is not allowed to manage the content filter")
+	}
+
+	return room, nil
+}
+
+// contentFilterSettings devuelve la configuración del filtro para compararla en el registro de auditoría
+func contentFilterSettings(settings *models.ContentFilterSettings) map[string]interface{} {
+	rules := append([]models.ContentFilterRule{}, settings.Rules...)
+	return map[string]interface{}{
+		"defaultListAction": settings.DefaultListAction,
+		"rules":             rules,
+	}
+}
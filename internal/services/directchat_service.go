@@ -2,6 +2,9 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Parchat/backend/internal/models"
 	"github.com/Parchat/backend/internal/repositories"
@@ -11,13 +14,22 @@ import (
 type DirectChatService struct {
 	DirectChatRepo *repositories.DirectChatRepository
 	MessageRepo    *repositories.MessageRepository
+	UserRepo       *repositories.UserRepository
+	RoomRepo       *repositories.RoomRepository
 }
 
 // NewDirectChatService crea una nueva instancia de DirectChatService
-func NewDirectChatService(directChatRepo *repositories.DirectChatRepository, messageRepo *repositories.MessageRepository) *DirectChatService {
+func NewDirectChatService(
+	directChatRepo *repositories.DirectChatRepository,
+	messageRepo *repositories.MessageRepository,
+	userRepo *repositories.UserRepository,
+	roomRepo *repositories.RoomRepository,
+) *DirectChatService {
 	return &DirectChatService{
 		DirectChatRepo: directChatRepo,
 		MessageRepo:    messageRepo,
+		UserRepo:       userRepo,
+		RoomRepo:       roomRepo,
 	}
 }
 
@@ -36,13 +48,49 @@ func (s *DirectChatService) GetUserDirectChats(userID string) ([]models.DirectCh
 	return s.DirectChatRepo.GetUserDirectChats(userID)
 }
 
-// GetUserDirectChatsWithSenderNames obtiene todos los chats directos con nombres de remitentes
-func (s *DirectChatService) GetUserDirectChatsWithSenderNames(userID string) ([]models.DirectChat, error) {
+// GetUserDirectChatsWithSenderNames obtiene los chats directos con nombres de remitentes. Con archivedOnly
+// solo se devuelven los archivados; si no, los no archivados. Las solicitudes de mensajes que el usuario
+// no ha aceptado y los chats que ha borrado no se incluyen
+func (s *DirectChatService) GetUserDirectChatsWithSenderNames(userID string, archivedOnly bool) ([]models.DirectChat, error) {
 	chats, err := s.DirectChatRepo.GetUserDirectChats(userID)
 	if err != nil {
 		return nil, err
 	}
 
+	visible := make([]models.DirectChat, 0, len(chats))
+	for _, chat := range chats {
+		if chat.IsRequestFor(userID) || chat.IsHiddenBy(userID) || chat.IsArchivedBy(userID) != archivedOnly {
+			continue
+		}
+		chat.Archived = archivedOnly
+		visible = append(visible, chat)
+	}
+
+	s.attachLastMessageSenderNames(visible)
+	return visible, nil
+}
+
+// GetMessageRequests obtiene las solicitudes de mensajes pendientes que ha recibido el usuario, incluidas
+// las invitaciones a chats de grupo sin aceptar
+func (s *DirectChatService) GetMessageRequests(userID string) ([]models.DirectChat, error) {
+	chats, err := s.DirectChatRepo.GetUserDirectChats(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]models.DirectChat, 0)
+	for _, chat := range chats {
+		if chat.IsRequestFor(userID) && (chat.IsGroup || chat.RequestStatus == models.MessageRequestPending) {
+			requests = append(requests, chat)
+		}
+	}
+
+	s.attachLastMessageSenderNames(requests)
+	return requests, nil
+}
+
+// attachLastMessageSenderNames añade a cada chat el nombre del remitente de su último mensaje
+func (s *DirectChatService) attachLastMessageSenderNames(chats []models.DirectChat) {
 	ctx := context.Background()
 	client := s.DirectChatRepo.FirestoreClient.Client
 
@@ -63,23 +111,73 @@ func (s *DirectChatService) GetUserDirectChatsWithSenderNames(userID string) ([]
 			}
 		}
 	}
-
-	return chats, nil
 }
 
-// GetDirectChatMessages obtiene los mensajes de un chat directo
-func (s *DirectChatService) GetDirectChatMessages(directChatID string, limit int) ([]models.MessageResponse, error) {
-	return s.MessageRepo.GetDirectChatMessagesSimple(directChatID, limit)
+// GetDirectChatMessages obtiene los mensajes de un chat directo, sin los de usuarios bloqueados por el usuario
+// ni los anteriores a la última vez que borró el chat
+func (s *DirectChatService) GetDirectChatMessages(directChatID, userID string, limit int) ([]models.MessageResponse, error) {
+	chat, err := s.DirectChatRepo.GetDirectChat(directChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.MessageRepo.GetDirectChatMessagesSimple(directChatID, limit, chat.ClearedBefore[userID])
+	if err != nil {
+		return nil, err
+	}
+
+	blockedIDs, err := s.UserRepo.GetBlockedUsers(context.Background(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterBlockedMessages(messages, blockedIDs), nil
 }
 
-// FindOrCreateDirectChat encuentra un chat directo entre dos usuarios o lo crea si no existe
-func (s *DirectChatService) FindOrCreateDirectChat(userID1, userID2 string) (*models.DirectChat, error) {
-	return s.DirectChatRepo.FindOrCreateDirectChat(userID1, userID2)
+// FindOrCreateDirectChat encuentra un chat directo entre dos usuarios o lo crea si no existe, e indica si es nuevo.
+// No se permite si alguno de los dos ha bloqueado al otro. Si el primer usuario no es contacto del segundo,
+// el chat nuevo queda como solicitud de mensajes pendiente de que el segundo la acepte
+func (s *DirectChatService) FindOrCreateDirectChat(userID1, userID2 string) (*models.DirectChat, bool, error) {
+	if err := s.checkNotBlocked(userID1, userID2); err != nil {
+		return nil, false, err
+	}
+
+	chat, err := s.DirectChatRepo.FindDirectChatBetween(userID1, userID2)
+	if err != nil {
+		return nil, false, err
+	}
+	if chat != nil {
+		return chat, false, nil
+	}
+
+	if err := s.checkCanStartDirectChat(userID1, []string{userID2}); err != nil {
+		return nil, false, err
+	}
+
+	isContact, err := s.isContact(userID2, userID1)
+	if err != nil {
+		return nil, false, err
+	}
+
+	newChat := &models.DirectChat{
+		UserIDs:   []string{userID1, userID2},
+		CreatedBy: userID1,
+	}
+	if !isContact {
+		newChat.RequestStatus = models.MessageRequestPending
+		newChat.RequestedBy = userID1
+	}
+
+	if err := s.DirectChatRepo.CreateDirectChat(newChat); err != nil {
+		return nil, false, err
+	}
+
+	return newChat, true, nil
 }
 
 // FindOrCreateDirectChatWithSenderName encuentra o crea un chat directo e incluye el nombre del remitente
 func (s *DirectChatService) FindOrCreateDirectChatWithSenderName(userID1, userID2 string) (*models.DirectChat, error) {
-	chat, err := s.DirectChatRepo.FindOrCreateDirectChat(userID1, userID2)
+	chat, _, err := s.FindOrCreateDirectChat(userID1, userID2)
 	if err != nil {
 		return nil, err
 	}
@@ -106,13 +204,18 @@ func (s *DirectChatService) FindOrCreateDirectChatWithSenderName(userID1, userID
 	return chat, nil
 }
 
-// GetDirectChatWithSenderName obtiene un chat directo por su ID e incluye el nombre del remitente del último mensaje
+// GetDirectChatWithSenderName obtiene un chat directo por su ID e incluye los nombres de los participantes
+// y del remitente del último mensaje
 func (s *DirectChatService) GetDirectChatWithSenderName(directChatID string) (*models.DirectChat, error) {
 	chat, err := s.DirectChatRepo.GetDirectChat(directChatID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.DirectChatRepo.AttachParticipantNames(chat, ""); err != nil {
+		return nil, err
+	}
+
 	// Añadir el displayName al último mensaje si existe
 	if chat.LastMessage != nil && chat.LastMessage.UserID != "" {
 		ctx := context.Background()
@@ -134,3 +237,475 @@ func (s *DirectChatService) GetDirectChatWithSenderName(directChatID string) (*m
 
 	return chat, nil
 }
+
+// CreateGroupDirectChat crea un chat de grupo entre el usuario y los participantes indicados. Si ya existe
+// un chat de grupo con exactamente los mismos participantes se reutiliza. Indica si el chat es nuevo.
+// No se permite si el usuario y alguno de los participantes se han bloqueado. Los participantes que no
+// tienen al usuario como contacto reciben el grupo como solicitud en su bandeja de solicitudes
+func (s *DirectChatService) CreateGroupDirectChat(userID string, req *models.CreateGroupDirectChatRequest) (*models.DirectChat, bool, error) {
+	userIDs := []string{userID}
+	for _, id := range req.UserIDs {
+		if id != "" && !contains(userIDs, id) {
+			userIDs = append(userIDs, id)
+		}
+	}
+
+	if len(userIDs) < models.MinGroupDirectChatParticipants {
+		return nil, false, fmt.Errorf("not enough participants for a group direct chat")
+	}
+	if len(userIDs) > models.MaxGroupDirectChatParticipants {
+		return nil, false, fmt.Errorf("too many participants for a group direct chat")
+	}
+
+	title, err := validateDirectChatTitle(req.Title)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Un bloqueo entre el creador y cualquier participante impide meterlos en el mismo grupo
+	for _, id := range userIDs[1:] {
+		if err := s.checkNotBlocked(userID, id); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := s.checkCanStartDirectChat(userID, userIDs[1:]); err != nil {
+		return nil, false, err
+	}
+
+	pendingInvites, err := s.pendingGroupInvites(userID, userIDs[1:])
+	if err != nil {
+		return nil, false, err
+	}
+
+	chat, created, err := s.DirectChatRepo.FindOrCreateGroupDirectChat(userID, userIDs, title, pendingInvites)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := s.DirectChatRepo.AttachParticipantNames(chat, ""); err != nil {
+		return nil, false, err
+	}
+
+	return chat, created, nil
+}
+
+// UpdateDirectChat cambia el título de un chat de grupo. Cualquier participante puede hacerlo
+func (s *DirectChatService) UpdateDirectChat(directChatID, userID string, req *models.UpdateDirectChatRequest) (*models.DirectChat, error) {
+	chat, err := s.getGroupDirectChat(directChatID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		title, err := validateDirectChatTitle(*req.Title)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.DirectChatRepo.UpdateTitle(directChatID, title); err != nil {
+			return nil, err
+		}
+		chat.Title = title
+	}
+
+	if err := s.DirectChatRepo.AttachParticipantNames(chat, ""); err != nil {
+		return nil, err
+	}
+
+	return chat, nil
+}
+
+// AddParticipant añade un usuario a un chat de grupo. Cualquier participante puede hacerlo, salvo si
+// él y el nuevo participante se han bloqueado. Si el nuevo participante no lo tiene como contacto,
+// el grupo le llega como solicitud
+func (s *DirectChatService) AddParticipant(directChatID, userID, newUserID string) (*models.DirectChat, error) {
+	if err := s.checkNotBlocked(userID, newUserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanStartDirectChat(userID, []string{newUserID}); err != nil {
+		return nil, err
+	}
+
+	pendingInvites, err := s.pendingGroupInvites(userID, []string{newUserID})
+	if err != nil {
+		return nil, err
+	}
+
+	chat, err := s.DirectChatRepo.UpdateParticipants(directChatID, func(chat *models.DirectChat) error {
+		if err := checkGroupParticipant(chat, userID); err != nil {
+			return err
+		}
+		if contains(chat.UserIDs, newUserID) {
+			return fmt.Errorf("user is already a participant")
+		}
+		if len(chat.UserIDs) >= models.MaxGroupDirectChatParticipants {
+			return fmt.Errorf("group direct chat is full")
+		}
+
+		chat.UserIDs = append(chat.UserIDs, newUserID)
+		if inviterID, ok := pendingInvites[newUserID]; ok {
+			if chat.PendingInvites == nil {
+				chat.PendingInvites = make(map[string]string)
+			}
+			chat.PendingInvites[newUserID] = inviterID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DirectChatRepo.AttachParticipantNames(chat, ""); err != nil {
+		return nil, err
+	}
+
+	return chat, nil
+}
+
+// RemoveParticipant quita a un usuario de un chat de grupo. Solo el creador del grupo puede quitar
+// a otros participantes; quitarse a uno mismo equivale a abandonar el chat
+func (s *DirectChatService) RemoveParticipant(directChatID, userID, targetID string) (*models.DirectChat, error) {
+	if targetID == userID {
+		return s.LeaveDirectChat(directChatID, userID)
+	}
+
+	chat, err := s.DirectChatRepo.UpdateParticipants(directChatID, func(chat *models.DirectChat) error {
+		if err := checkGroupParticipant(chat, userID); err != nil {
+			return err
+		}
+		if chat.CreatedBy != userID {
+			return fmt.Errorf("only the group creator can remove participants")
+		}
+		if !contains(chat.UserIDs, targetID) {
+			return fmt.Errorf("user is not a participant")
+		}
+
+		chat.UserIDs = removeString(chat.UserIDs, targetID)
+		delete(chat.PendingInvites, targetID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DirectChatRepo.AttachParticipantNames(chat, ""); err != nil {
+		return nil, err
+	}
+
+	return chat, nil
+}
+
+// LeaveDirectChat saca al usuario de un chat de grupo. Si era el creador, el siguiente participante
+// pasa a serlo; el chat se marca como eliminado cuando no queda nadie
+func (s *DirectChatService) LeaveDirectChat(directChatID, userID string) (*models.DirectChat, error) {
+	chat, err := s.DirectChatRepo.UpdateParticipants(directChatID, func(chat *models.DirectChat) error {
+		// Quien tiene la invitación pendiente también puede salir, igual que si la rechazara
+		if !chat.IsParticipant(userID) {
+			return fmt.Errorf("direct chat not found")
+		}
+		if !chat.IsGroup {
+			return fmt.Errorf("direct chat is not a group")
+		}
+
+		chat.UserIDs = removeString(chat.UserIDs, userID)
+		delete(chat.PendingInvites, userID)
+		if len(chat.UserIDs) == 0 {
+			chat.IsDeleted = true
+		} else if chat.CreatedBy == userID {
+			chat.CreatedBy = chat.UserIDs[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DirectChatRepo.AttachParticipantNames(chat, ""); err != nil {
+		return nil, err
+	}
+
+	return chat, nil
+}
+
+// getGroupDirectChat obtiene un chat de grupo verificando que el usuario participa en él
+func (s *DirectChatService) getGroupDirectChat(directChatID, userID string) (*models.DirectChat, error) {
+	chat, err := s.DirectChatRepo.GetDirectChat(directChatID)
+	if err != nil || chat.IsDeleted {
+		return nil, fmt.Errorf("direct chat not found")
+	}
+
+	if err := checkGroupParticipant(chat, userID); err != nil {
+		return nil, err
+	}
+
+	return chat, nil
+}
+
+// ArchiveDirectChat archiva o desarchiva un chat directo para el usuario. Un mensaje nuevo lo desarchiva
+func (s *DirectChatService) ArchiveDirectChat(directChatID, userID string, archived bool) error {
+	chat, err := s.DirectChatRepo.GetDirectChat(directChatID)
+	if err != nil || chat.IsDeleted || !contains(chat.UserIDs, userID) {
+		return fmt.Errorf("direct chat not found")
+	}
+
+	return s.DirectChatRepo.SetArchived(directChatID, userID, archived)
+}
+
+// DeleteDirectChatForUser borra un chat directo solo para el usuario: desaparece de su lista y su
+// historial se vacía. Los demás participantes conservan el chat y, si llega un mensaje nuevo,
+// el chat vuelve a aparecer con los mensajes posteriores al borrado
+func (s *DirectChatService) DeleteDirectChatForUser(directChatID, userID string) error {
+	chat, err := s.DirectChatRepo.GetDirectChat(directChatID)
+	if err != nil || chat.IsDeleted || !contains(chat.UserIDs, userID) {
+		return fmt.Errorf("direct chat not found")
+	}
+
+	return s.DirectChatRepo.HideForUser(directChatID, userID, time.Now())
+}
+
+// AcceptMessageRequest acepta una solicitud de mensajes recibida, incluso si antes se rechazó. En un chat
+// de grupo acepta la invitación y el usuario pasa a ser un participante más
+func (s *DirectChatService) AcceptMessageRequest(directChatID, userID string) (*models.DirectChat, error) {
+	chat, err := s.getMessageRequest(directChatID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if chat.IsGroup {
+		return s.DirectChatRepo.UpdateParticipants(directChatID, func(chat *models.DirectChat) error {
+			if _, pending := chat.PendingInvites[userID]; !pending {
+				return fmt.Errorf("message request not found")
+			}
+			delete(chat.PendingInvites, userID)
+			return nil
+		})
+	}
+
+	if err := s.DirectChatRepo.SetRequestStatus(directChatID, models.MessageRequestAccepted); err != nil {
+		return nil, err
+	}
+	chat.RequestStatus = models.MessageRequestAccepted
+
+	return chat, nil
+}
+
+// DeclineMessageRequest rechaza una solicitud de mensajes pendiente. El remitente no recibe ningún aviso,
+// pero ya no puede enviar mensajes en el chat. Rechazar la invitación a un chat de grupo saca al usuario del grupo
+func (s *DirectChatService) DeclineMessageRequest(directChatID, userID string) (*models.DirectChat, error) {
+	chat, err := s.getMessageRequest(directChatID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if chat.IsGroup {
+		return s.declineGroupInvite(directChatID, userID)
+	}
+
+	if chat.RequestStatus != models.MessageRequestPending {
+		return nil, fmt.Errorf("message request not found")
+	}
+
+	if err := s.DirectChatRepo.SetRequestStatus(directChatID, models.MessageRequestDeclined); err != nil {
+		return nil, err
+	}
+	chat.RequestStatus = models.MessageRequestDeclined
+
+	return chat, nil
+}
+
+// BlockMessageRequest rechaza una solicitud de mensajes y bloquea a quien la envió. En un chat de grupo
+// bloquea a quien añadió al usuario
+func (s *DirectChatService) BlockMessageRequest(directChatID, userID string) (*models.DirectChat, error) {
+	chat, err := s.getMessageRequest(directChatID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	senderID := chat.RequestedBy
+	if chat.IsGroup {
+		senderID = chat.PendingInvites[userID]
+		if chat, err = s.declineGroupInvite(directChatID, userID); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := s.DirectChatRepo.SetRequestStatus(directChatID, models.MessageRequestDeclined); err != nil {
+			return nil, err
+		}
+		chat.RequestStatus = models.MessageRequestDeclined
+	}
+
+	if err := s.UserRepo.SetUserBlocked(context.Background(), userID, senderID, true); err != nil {
+		return nil, err
+	}
+
+	return chat, nil
+}
+
+// declineGroupInvite saca del chat de grupo a un usuario que no ha aceptado su invitación
+func (s *DirectChatService) declineGroupInvite(directChatID, userID string) (*models.DirectChat, error) {
+	return s.DirectChatRepo.UpdateParticipants(directChatID, func(chat *models.DirectChat) error {
+		if _, pending := chat.PendingInvites[userID]; !pending {
+			return fmt.Errorf("message request not found")
+		}
+
+		chat.UserIDs = removeString(chat.UserIDs, userID)
+		delete(chat.PendingInvites, userID)
+		if len(chat.UserIDs) == 0 {
+			chat.IsDeleted = true
+		} else if chat.CreatedBy == userID {
+			chat.CreatedBy = chat.UserIDs[0]
+		}
+		return nil
+	})
+}
+
+// getMessageRequest obtiene un chat que es una solicitud de mensajes dirigida al usuario
+func (s *DirectChatService) getMessageRequest(directChatID, userID string) (*models.DirectChat, error) {
+	chat, err := s.DirectChatRepo.GetDirectChat(directChatID)
+	if err != nil || chat.IsDeleted || !contains(chat.UserIDs, userID) || !chat.IsRequestFor(userID) {
+		return nil, fmt.Errorf("message request not found")
+	}
+
+	return chat, nil
+}
+
+// isContact indica si un usuario cuenta como contacto de otro: lo tiene en su lista de contactos
+// o comparten alguna sala
+func (s *DirectChatService) isContact(ownerID, userID string) (bool, error) {
+	owner, err := s.UserRepo.GetUserByID(context.Background(), ownerID)
+	if err != nil {
+		return false, err
+	}
+	if owner != nil && owner.HasContact(userID) {
+		return true, nil
+	}
+
+	return s.RoomRepo.ShareRoom(ownerID, userID)
+}
+
+// pendingGroupInvites devuelve, de los usuarios que alguien añade a un chat de grupo, los que no lo tienen
+// como contacto y deben aceptar la invitación, junto a quien los añadió
+func (s *DirectChatService) pendingGroupInvites(inviterID string, userIDs []string) (map[string]string, error) {
+	var pending map[string]string
+	for _, id := range userIDs {
+		isContact, err := s.isContact(id, inviterID)
+		if err != nil {
+			return nil, err
+		}
+		if !isContact {
+			if pending == nil {
+				pending = make(map[string]string)
+			}
+			pending[id] = inviterID
+		}
+	}
+	return pending, nil
+}
+
+// checkNotBlocked verifica que ninguno de los dos usuarios ha bloqueado al otro
+func (s *DirectChatService) checkNotBlocked(userID1, userID2 string) error {
+	blocked, err := s.UserRepo.IsBlockedBetween(context.Background(), userID1, userID2)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return fmt.Errorf("user is blocked")
+	}
+	return nil
+}
+
+// checkCanStartDirectChat verifica que los destinatarios existen y que sus ajustes de privacidad
+// permiten que el usuario abra un chat directo con ellos
+func (s *DirectChatService) checkCanStartDirectChat(senderID string, recipientIDs []string) error {
+	users, err := s.UserRepo.GetUsersByIDs(context.Background(), recipientIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range recipientIDs {
+		user, ok := users[id]
+		if !ok || user.IsDeleted {
+			return fmt.Errorf("user not found")
+		}
+
+		allowed, err := s.allowsDirectMessagesFrom(user, senderID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return fmt.Errorf("user does not accept direct messages")
+		}
+	}
+
+	return nil
+}
+
+// CheckDirectMessagePrivacy verifica antes de cada envío que el otro participante de un chat entre dos
+// usuarios sigue aceptando sus mensajes. Los chats que abrió el destinatario o cuya solicitud aceptó
+// no se ven afectados si sus ajustes se vuelven más estrictos. En los chats de grupo la privacidad se comprueba
+// al añadir a cada participante, y quien no tiene como contacto a quien lo añadió recibe el grupo como solicitud
+func (s *DirectChatService) CheckDirectMessagePrivacy(chat *models.DirectChat, senderID string) error {
+	if chat.IsGroup || chat.RequestStatus == models.MessageRequestAccepted {
+		return nil
+	}
+
+	for _, recipientID := range chat.UserIDs {
+		if recipientID == senderID || recipientID == chat.CreatedBy {
+			continue
+		}
+
+		recipient, err := s.UserRepo.GetUserByID(context.Background(), recipientID)
+		if err != nil || recipient == nil {
+			return fmt.Errorf("user not found")
+		}
+
+		allowed, err := s.allowsDirectMessagesFrom(recipient, senderID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return fmt.Errorf("user does not accept direct messages")
+		}
+	}
+
+	return nil
+}
+
+// allowsDirectMessagesFrom indica si los ajustes de privacidad de un usuario permiten que otro le abra chats directos
+func (s *DirectChatService) allowsDirectMessagesFrom(recipient *models.User, senderID string) (bool, error) {
+	switch recipient.DMPrivacy {
+	case models.DMPrivacyNobody:
+		return false, nil
+	case models.DMPrivacyContacts:
+		return recipient.HasContact(senderID), nil
+	case models.DMPrivacySharedRooms:
+		if recipient.HasContact(senderID) {
+			return true, nil
+		}
+		return s.RoomRepo.ShareRoom(recipient.UID, senderID)
+	default:
+		return true, nil
+	}
+}
+
+// checkGroupParticipant verifica que el chat es de grupo y que el usuario participa en él con la invitación aceptada
+func checkGroupParticipant(chat *models.DirectChat, userID string) error {
+	if !chat.IsMember(userID) {
+		return fmt.Errorf("direct chat not found")
+	}
+	if !chat.IsGroup {
+		return fmt.Errorf("direct chat is not a group")
+	}
+	return nil
+}
+
+// validateDirectChatTitle limpia el título de un chat de grupo y comprueba su longitud
+func validateDirectChatTitle(title string) (string, error) {
+	title = strings.TrimSpace(title)
+	if len([]rune(title)) > models.MaxDirectChatTitleLength {
+		return "", fmt.Errorf("title is too long")
+	}
+	return title, nil
+}
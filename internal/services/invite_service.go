@@ -0,0 +1,225 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Parchat/backend/internal/config"
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/repositories"
+)
+
+const (
+	// inviteCodeLength es la longitud de los códigos de invitación
+	inviteCodeLength = 8
+	// inviteCodeAlphabet evita caracteres que se confunden entre sí (0/O, 1/I/L)
+	inviteCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+	// maxInviteCodeAttempts es el número de intentos para generar un código que no exista
+	maxInviteCodeAttempts = 5
+)
+
+// InviteService maneja la lógica de negocio de las invitaciones a salas
+type InviteService struct {
+	InviteRepo  *repositories.InviteRepository
+	RoomRepo    *repositories.RoomRepository
+	Permissions *PermissionService
+	Audit       *AuditService
+	Config      *config.Config
+}
+
+// NewInviteService crea una nueva instancia de InviteService
+func NewInviteService(
+	inviteRepo *repositories.InviteRepository,
+	roomRepo *repositories.RoomRepository,
+	permissions *PermissionService,
+	audit *AuditService,
+	cfg *config.Config,
+) *InviteService {
+	return &InviteService{
+		InviteRepo:  inviteRepo,
+		RoomRepo:    roomRepo,
+		Permissions: permissions,
+		Audit:       audit,
+		Config:      cfg,
+	}
+}
+
+// CreateInvite genera una invitación para una sala. Requiere la capacidad invite_members
+func (s *InviteService) CreateInvite(roomID, userID string, req *models.CreateInviteRequest) (*models.Invite, error) {
+	if req.ExpiresInHours < 0 || req.MaxUses < 0 {
+		return nil, fmt.Errorf("invalid invite settings")
+	}
+
+	if _, err := s.getRoomForInvites(roomID, userID); err != nil {
+		return nil, err
+	}
+
+	invite := &models.Invite{
+		RoomID:    roomID,
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		MaxUses:   req.MaxUses,
+	}
+	if req.ExpiresInHours > 0 {
+		expiresAt := invite.CreatedAt.Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		invite.ExpiresAt = &expiresAt
+	}
+
+	// Reintentar si el código generado ya existe
+	var err error
+	for attempt := 0; attempt < maxInviteCodeAttempts; attempt++ {
+		invite.Code, err = generateInviteCode()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = s.InviteRepo.CreateInvite(invite); err == nil {
+			invite.Link = s.inviteLink(invite.Code)
+			s.Audit.Record(&models.AuditEntry{
+				RoomID:     roomID,
+				Action:     models.AuditInviteCreated,
+				ActorID:    userID,
+				TargetType: models.AuditTargetInvite,
+				TargetID:   invite.Code,
+				After:      map[string]interface{}{"maxUses": invite.MaxUses, "expiresAt": invite.ExpiresAt},
+			})
+			return invite, nil
+		}
+	}
+
+	return nil, fmt.Errorf("error creating invite: %v", err)
+}
+
+// GetRoomInvites lista las invitaciones activas de una sala. Requiere la capacidad invite_members
+func (s *InviteService) GetRoomInvites(roomID, userID string) ([]models.Invite, error) {
+	if _, err := s.getRoomForInvites(roomID, userID); err != nil {
+		return nil, err
+	}
+
+	invites, err := s.InviteRepo.GetRoomInvites(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range invites {
+		invites[i].Link = s.inviteLink(invites[i].Code)
+	}
+
+	return invites, nil
+}
+
+// RevokeInvite revoca una invitación de una sala. Requiere la capacidad invite_members
+func (s *InviteService) RevokeInvite(roomID, userID, code string) error {
+	if _, err := s.getRoomForInvites(roomID, userID); err != nil {
+		return err
+	}
+
+	invite, err := s.InviteRepo.GetInvite(code)
+	if err != nil || invite.RoomID != roomID || invite.Revoked {
+		return fmt.Errorf("invite not found")
+	}
+
+	if err := s.InviteRepo.RevokeInvite(code); err != nil {
+		return err
+	}
+
+	s.Audit.Record(&models.AuditEntry{
+		RoomID:     roomID,
+		Action:     models.AuditInviteRevoked,
+		ActorID:    userID,
+		TargetType: models.AuditTargetInvite,
+		TargetID:   code,
+		Before:     map[string]interface{}{"uses": invite.Uses, "createdBy": invite.CreatedBy},
+	})
+	return nil
+}
+
+// PreviewInvite devuelve la información pública de la sala de una invitación válida
+func (s *InviteService) PreviewInvite(code string) (*models.InvitePreview, error) {
+	invite, err := s.getValidInvite(code)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := s.RoomRepo.GetRoom(invite.RoomID)
+	if err != nil || room.IsDeleted {
+		return nil, fmt.Errorf("invite not found")
+	}
+
+	return &models.InvitePreview{
+		Code:        invite.Code,
+		RoomID:      room.ID,
+		RoomName:    room.Name,
+		ImageURL:    room.ImageURL,
+		MemberCount: len(room.Members),
+		ExpiresAt:   invite.ExpiresAt,
+	}, nil
+}
+
+// JoinWithInvite une al usuario a la sala de una invitación válida y devuelve la invitación usada
+func (s *InviteService) JoinWithInvite(code, userID string) (*models.Invite, error) {
+	return s.InviteRepo.RedeemInvite(normalizeInviteCode(code), userID)
+}
+
+// getValidInvite obtiene una invitación que todavía puede usarse
+func (s *InviteService) getValidInvite(code string) (*models.Invite, error) {
+	invite, err := s.InviteRepo.GetInvite(normalizeInviteCode(code))
+	if err != nil {
+		return nil, fmt.Errorf("invite not found")
+	}
+
+	switch {
+	case invite.Revoked:
+		return nil, fmt.Errorf("invite has been revoked")
+	case invite.IsExpired(time.Now()):
+		return nil, fmt.Errorf("invite has expired")
+	case invite.IsExhausted():
+		return nil, fmt.Errorf("invite has reached its maximum uses")
+	}
+
+	return invite, nil
+}
+
+// getRoomForInvites obtiene la sala y verifica que el usuario puede gestionar sus invitaciones
+func (s *InviteService) getRoomForInvites(roomID, userID string) (*models.Room, error) {
+	room, err := s.RoomRepo.GetRoom(roomID)
+	if err != nil || room.IsDeleted {
+		return nil, fmt.Errorf("room not found")
+	}
+
+	if !s.Permissions.Can(room, userID, models.CapInviteMembers) {
+		return nil, fmt.Errorf("user is not allowed to manage invites")
+	}
+
+	return room, nil
+}
+
+// inviteLink construye el enlace para compartir una invitación
+func (s *InviteService) inviteLink(code string) string {
+	return strings.TrimRight(s.Config.ServerURL, "/") + "/api/v1/invites/" + code
+}
+
+// generateInviteCode genera un código de invitación aleatorio
+func generateInviteCode() (string, error) {
+	// rand.Int elige cada carácter de forma uniforme; con el módulo de un byte los primeros saldrían más
+	alphabetSize := big.NewInt(int64(len(inviteCodeAlphabet)))
+
+	code := make([]byte, inviteCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("error generating invite code: %v", err)
+		}
+		code[i] = inviteCodeAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
+
+// normalizeInviteCode permite escribir los códigos sin distinguir mayúsculas
+func normalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
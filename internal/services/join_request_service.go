@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/repositories"
+)
+
+// JoinRequestService maneja la lógica de negocio de las solicitudes para unirse a salas privadas
+type JoinRequestService struct {
+	JoinRequestRepo *repositories.JoinRequestRepository
+	RoomRepo        *repositories.RoomRepository
+	UserRepo        *repositories.UserRepository
+	SpaceRepo       *repositories.SpaceRepository
+	Permissions     *PermissionService
+	Audit           *AuditService
+}
+
+// NewJoinRequestService crea una nueva instancia de JoinRequestService
+func NewJoinRequestService(
+	joinRequestRepo *repositories.JoinRequestRepository,
+	roomRepo *repositories.RoomRepository,
+	userRepo *repositories.UserRepository,
+	spaceRepo *repositories.SpaceRepository,
+	permissions *PermissionService,
+	audit *AuditService,
+) *JoinRequestService {
+	return &JoinRequestService{
+		JoinRequestRepo: joinRequestRepo,
+		RoomRepo:        roomRepo,
+		UserRepo:        userRepo,
+		SpaceRepo:       spaceRepo,
+		Permissions:     permissions,
+		Audit:           audit,
+	}
+}
+
+// RequestToJoin crea una solicitud para unirse a una sala privada. Devuelve la solicitud
+// y los usuarios que pueden revisarla, para notificarles
+func (s *JoinRequestService) RequestToJoin(roomID, userID, message string) (*models.JoinRequest, []string, error) {
+	message = strings.TrimSpace(message)
+	if len(message) > models.MaxJoinRequestMessageLength {
+		return nil, nil, fmt.Errorf("join request message is too long")
+	}
+
+	room, err := s.getRoom(roomID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !room.IsPrivate {
+		return nil, nil, fmt.Errorf("room is public, join it directly")
+	}
+
+	if s.RoomRepo.HasRoomAccess(room, userID) {
+		return nil, nil, fmt.Errorf("user is already a member of the room")
+	}
+
+	if room.IsBanned(userID, time.Now()) {
+		return nil, nil, fmt.Errorf("user is banned from the room")
+	}
+
+	if err := s.checkSpaceMember(room, userID); err != nil {
+		return nil, nil, err
+	}
+
+	// Una solicitud rechazada se puede volver a enviar, pero no se duplican las pendientes
+	existing, err := s.JoinRequestRepo.GetJoinRequest(roomID, userID)
+	if err == nil && existing.Status == models.JoinRequestPending {
+		return nil, nil, fmt.Errorf("join request is already pending")
+	}
+
+	request := &models.JoinRequest{
+		UserID:    userID,
+		RoomID:    roomID,
+		Message:   message,
+		Status:    models.JoinRequestPending,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.JoinRequestRepo.SaveJoinRequest(request); err != nil {
+		return nil, nil, err
+	}
+
+	request.DisplayName = s.displayName(userID)
+	return request, s.reviewers(room), nil
+}
+
+// CancelJoinRequest elimina la solicitud pendiente del usuario
+func (s *JoinRequestService) CancelJoinRequest(roomID, userID string) error {
+	request, err := s.JoinRequestRepo.GetJoinRequest(roomID, userID)
+	if err != nil || request.Status != models.JoinRequestPending {
+		return fmt.Errorf("join request not found")
+	}
+
+	return s.JoinRequestRepo.DeleteJoinRequest(roomID, userID)
+}
+
+// GetPendingJoinRequests devuelve la cola de solicitudes pendientes. Requiere la capacidad invite_members
+func (s *JoinRequestService) GetPendingJoinRequests(roomID, actorID string) ([]models.JoinRequest, error) {
+	room, err := s.getRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.Permissions.Can(room, actorID, models.CapInviteMembers) {
+		return nil, fmt.Errorf("user is not allowed to review join requests")
+	}
+
+	requests, err := s.JoinRequestRepo.GetPendingJoinRequests(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range requests {
+		requests[i].DisplayName = s.displayName(requests[i].UserID)
+	}
+
+	return requests, nil
+}
+
+// ApproveJoinRequest añade al solicitante como miembro de la sala. Requiere la capacidad invite_members
+func (s *JoinRequestService) ApproveJoinRequest(roomID, actorID, userID string) (*models.JoinRequest, error) {
+	room, request, err := s.getPendingForReview(roomID, actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Quien dejó el espacio después de pedir la entrada ya no puede entrar en sus canales
+	if err := s.checkSpaceMember(room, userID); err != nil {
+		return nil, err
+	}
+
+	// Usar el mismo camino que una unión directa a la sala
+	if err := s.RoomRepo.AddMemberToRoom(roomID, userID); err != nil && err.Error() != "user is already a member of the room" {
+		return nil, err
+	}
+
+	return s.review(request, models.JoinRequestApproved, actorID)
+}
+
+// RejectJoinRequest rechaza una solicitud pendiente. Requiere la capacidad invite_members
+func (s *JoinRequestService) RejectJoinRequest(roomID, actorID, userID string) (*models.JoinRequest, error) {
+	_, request, err := s.getPendingForReview(roomID, actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.review(request, models.JoinRequestRejected, actorID)
+}
+
+// getPendingForReview verifica que el actor puede revisar solicitudes y que la solicitud sigue pendiente
+func (s *JoinRequestService) getPendingForReview(roomID, actorID, userID string) (*models.Room, *models.JoinRequest, error) {
+	room, err := s.getRoom(roomID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !s.Permissions.Can(room, actorID, models.CapInviteMembers) {
+		return nil, nil, fmt.Errorf("user is not allowed to review join requests")
+	}
+
+	request, err := s.JoinRequestRepo.GetJoinRequest(roomID, userID)
+	if err != nil || request.Status != models.JoinRequestPending {
+		return nil, nil, fmt.Errorf("join request not found")
+	}
+
+	return room, request, nil
+}
+
+// checkSpaceMember verifica, en los canales de un espacio, que el usuario es miembro del espacio,
+// igual que al unirse directamente
+func (s *JoinRequestService) checkSpaceMember(room *models.Room, userID string) error {
+	if room.SpaceID == "" {
+		return nil
+	}
+
+	space, err := s.SpaceRepo.GetSpace(room.SpaceID)
+	if err != nil || !space.IsMember(userID) {
+		return fmt.Errorf("user is not a member of the space")
+	}
+
+	return nil
+}
+
+// review guarda la decisión sobre una solicitud y devuelve la solicitud actualizada
+func (s *JoinRequestService) review(request *models.JoinRequest, status, reviewerID string) (*models.JoinRequest, error) {
+	if err := s.JoinRequestRepo.ReviewJoinRequest(request.RoomID, request.UserID, status, reviewerID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	request.Status = status
+	request.ReviewedBy = reviewerID
+	request.ReviewedAt = &now
+
+	action := models.AuditJoinRequestRejected
+	if status == models.JoinRequestApproved {
+		action = models.AuditJoinRequestApproved
+	}
+	s.Audit.Record(&models.AuditEntry{
+		RoomID:     request.RoomID,
+		Action:     action,
+		ActorID:    reviewerID,
+		TargetType: models.AuditTargetUser,
+		TargetID:   request.UserID,
+		Before:     map[string]interface{}{"status": models.JoinRequestPending},
+		After:      map[string]interface{}{"status": status},
+	})
+	return request, nil
+}
+
+// getRoom obtiene una sala que no ha sido eliminada
+func (s *JoinRequestService) getRoom(roomID string) (*models.Room, error) {
+	room, err := s.RoomRepo.GetRoom(roomID)
+	if err != nil || room.IsDeleted {
+		return nil, fmt.Errorf("room not found")
+	}
+
+	return room, nil
+}
+
+// reviewers devuelve los miembros de la sala que pueden aprobar solicitudes
+func (s *JoinRequestService) reviewers(room *models.Room) []string {
+	var reviewers []string
+	for _, memberID := range append([]string{room.OwnerID}, room.Members...) {
+		if !contains(reviewers, memberID) && s.Permissions.Can(room, memberID, models.CapInviteMembers) {
+			reviewers = append(reviewers, memberID)
+		}
+	}
+	return reviewers
+}
+
+// displayName obtiene el nombre visible de un usuario, o una cadena vacía si no se encuentra
+func (s *JoinRequestService) displayName(userID string) string {
+	user, err := s.UserRepo.GetUserByID(context.Background(), userID)
+	if err != nil {
+		return ""
+	}
+	return user.DisplayName
+}
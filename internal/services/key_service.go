@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/repositories"
+)
+
+// KeyService maneja las claves públicas de los dispositivos y el cifrado de extremo a extremo de los
+// chats directos. El servidor solo guarda claves públicas y reenvía texto cifrado; nunca lo descifra
+type KeyService struct {
+	KeyRepo        *repositories.KeyRepository
+	UserRepo       *repositories.UserRepository
+	DirectChatRepo *repositories.DirectChatRepository
+
+	bundleLimiter *keyBundleLimiter
+}
+
+// NewKeyService crea una nueva instancia de KeyService
+func NewKeyService(
+	keyRepo *repositories.KeyRepository,
+	userRepo *repositories.UserRepository,
+	directChatRepo *repositories.DirectChatRepository,
+) *KeyService {
+	return &KeyService{
+		KeyRepo:        keyRepo,
+		UserRepo:       userRepo,
+		DirectChatRepo: directChatRepo,
+		bundleLimiter:  newKeyBundleLimiter(),
+	}
+}
+
+// PublishDeviceKeys publica o reemplaza las claves de un dispositivo del usuario. Devuelve el motivo del
+// cambio que hay que avisar a los demás, o una cadena vacía si solo se rotó la clave previa firmada
+func (s *KeyService) PublishDeviceKeys(userID, deviceID string, req *models.PublishDeviceKeysRequest) (*models.DeviceKeys, string, error) {
+	if deviceID == "" || req.IdentityKey == "" || req.SignedPreKey.PublicKey == "" || req.SignedPreKey.Signature == "" {
+		return nil, "", fmt.Errorf("invalid device keys")
+	}
+	if err := validatePreKeys(req.OneTimePreKeys); err != nil {
+		return nil, "", err
+	}
+
+	existing, err := s.KeyRepo.GetDeviceKeys(userID, deviceID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	device := &models.DeviceKeys{
+		DeviceID:          deviceID,
+		UserID:            userID,
+		IdentityKey:       req.IdentityKey,
+		SignedPreKey:      req.SignedPreKey,
+		IdentityChangedAt: now,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	var reason string
+	if existing == nil {
+		devices, err := s.KeyRepo.GetUserDevices(userID)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(devices) >= models.MaxDevicesPerUser {
+			return nil, "", fmt.Errorf("too many devices")
+		}
+		reason = models.KeyChangeDeviceAdded
+	} else {
+		device.CreatedAt = existing.CreatedAt
+		if existing.IdentityKey == req.IdentityKey {
+			device.IdentityChangedAt = existing.IdentityChangedAt
+		} else {
+			reason = models.KeyChangeIdentityChanged
+		}
+	}
+
+	if err := s.KeyRepo.SaveDeviceKeys(device); err != nil {
+		return nil, "", err
+	}
+	// Las claves de un solo uso anteriores se firmaron con la identidad vieja y ya no sirven
+	if reason == models.KeyChangeIdentityChanged {
+		if err := s.KeyRepo.DeleteOneTimePreKeys(userID, deviceID); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := s.KeyRepo.AddOneTimePreKeys(userID, deviceID, req.OneTimePreKeys); err != nil {
+		return nil, "", err
+	}
+
+	device.RemainingPreKeys, err = s.KeyRepo.CountOneTimePreKeys(userID, deviceID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return device, reason, nil
+}
+
+// UploadPreKeys añade claves de un solo uso a un dispositivo ya publicado
+func (s *KeyService) UploadPreKeys(userID, deviceID string, req *models.UploadPreKeysRequest) (*models.DeviceKeys, error) {
+	if len(req.OneTimePreKeys) == 0 {
+		return nil, fmt.Errorf("invalid device keys")
+	}
+	if err := validatePreKeys(req.OneTimePreKeys); err != nil {
+		return nil, err
+	}
+
+	device, err := s.KeyRepo.GetDeviceKeys(userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device == nil {
+		return nil, fmt.Errorf("device not found")
+	}
+
+	if err := s.KeyRepo.AddOneTimePreKeys(userID, deviceID, req.OneTimePreKeys); err != nil {
+		return nil, err
+	}
+	if err := s.KeyRepo.TouchDevice(userID, deviceID); err != nil {
+		return nil, err
+	}
+
+	device.UpdatedAt = time.Now()
+	device.RemainingPreKeys, err = s.KeyRepo.CountOneTimePreKeys(userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+// GetUserDevices lista los dispositivos del usuario con las claves de un solo uso que les quedan
+func (s *KeyService) GetUserDevices(userID string) ([]models.DeviceKeys, error) {
+	devices, err := s.KeyRepo.GetUserDevices(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range devices {
+		devices[i].RemainingPreKeys, err = s.KeyRepo.CountOneTimePreKeys(userID, devices[i].DeviceID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return devices, nil
+}
+
+// DeleteDevice borra las claves de un dispositivo del usuario
+func (s *KeyService) DeleteDevice(userID, deviceID string) error {
+	device, err := s.KeyRepo.GetDeviceKeys(userID, deviceID)
+	if err != nil {
+		return err
+	}
+	if device == nil {
+		return fmt.Errorf("device not found")
+	}
+
+	return s.KeyRepo.DeleteDevice(userID, deviceID)
+}
+
+// GetKeyBundle devuelve los paquetes de claves de todos los dispositivos de un usuario. Cada llamada
+// consume una clave de un solo uso por dispositivo, si le quedan
+func (s *KeyService) GetKeyBundle(requesterID, targetID string) (*models.KeyBundleResponse, error) {
+	ctx := context.Background()
+
+	target, err := s.UserRepo.GetUserByID(ctx, targetID)
+	if err != nil || target == nil || target.IsDeleted {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if requesterID != targetID {
+		blocked, err := s.UserRepo.IsBlockedBetween(ctx, requesterID, targetID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, fmt.Errorf("user is blocked")
+		}
+	}
+
+	if !s.bundleLimiter.allow(requesterID, targetID) {
+		return nil, fmt.Errorf("too many key bundle requests")
+	}
+
+	devices, err := s.KeyRepo.GetUserDevices(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		return nil, fmt.Errorf("user has no device keys")
+	}
+
+	bundle := &models.KeyBundleResponse{
+		UserID:  targetID,
+		Devices: make([]models.DeviceKeyBundle, 0, len(devices)),
+	}
+	for _, device := range devices {
+		preKey, err := s.KeyRepo.ClaimOneTimePreKey(targetID, device.DeviceID)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.Devices = append(bundle.Devices, models.DeviceKeyBundle{
+			DeviceID:          device.DeviceID,
+			IdentityKey:       device.IdentityKey,
+			SignedPreKey:      device.SignedPreKey,
+			OneTimePreKey:     preKey,
+			IdentityChangedAt: device.IdentityChangedAt,
+		})
+	}
+
+	return bundle, nil
+}
+
+// GetKeyChangePeers devuelve los usuarios que comparten algún chat directo con el usuario y que deben
+// enterarse de que sus claves han cambiado
+func (s *KeyService) GetKeyChangePeers(userID string) ([]string, error) {
+	chats, err := s.DirectChatRepo.GetUserDirectChats(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var peers []string
+	for _, chat := range chats {
+		for _, participantID := range chat.UserIDs {
+			if participantID != userID && !contains(peers, participantID) {
+				peers = append(peers, participantID)
+			}
+		}
+	}
+
+	return peers, nil
+}
+
+// EnableDirectChatEncryption activa el cifrado de extremo a extremo de un chat directo. Todos los
+// participantes deben haber publicado las claves de algún dispositivo. Devuelve false si ya estaba activo
+func (s *KeyService) EnableDirectChatEncryption(directChatID, userID string) (*models.DirectChat, bool, error) {
+	chat, err := s.DirectChatRepo.GetDirectChat(directChatID)
+	if err != nil || chat.IsDeleted || !chat.IsMember(userID) {
+		return nil, false, fmt.Errorf("direct chat not found")
+	}
+
+	if chat.Encrypted {
+		return chat, false, nil
+	}
+
+	for _, participantID := range chat.UserIDs {
+		devices, err := s.KeyRepo.GetUserDevices(participantID)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(devices) == 0 {
+			return nil, false, fmt.Errorf("all participants must publish device keys first")
+		}
+	}
+
+	if err := s.DirectChatRepo.EnableEncryption(directChatID); err != nil {
+		return nil, false, err
+	}
+	chat.Encrypted = true
+	chat.UpdatedAt = time.Now()
+
+	return chat, true, nil
+}
+
+// ValidateEncryptedMessage verifica que un mensaje de un chat cifrado solo trae texto cifrado y que
+// todos sus sobres van dirigidos a participantes del chat. El contenido de los sobres no se inspecciona
+func (s *KeyService) ValidateEncryptedMessage(chat *models.DirectChat, message *models.Message) error {
+	if message.Content != "" || len(message.Envelopes) == 0 {
+		return fmt.Errorf("encrypted chats only accept encrypted messages")
+	}
+
+	for _, envelope := range message.Envelopes {
+		if envelope.DeviceID == "" || envelope.Ciphertext == "" || !contains(chat.UserIDs, envelope.RecipientID) {
+			return fmt.Errorf("invalid encrypted envelope")
+		}
+	}
+
+	return nil
+}
+
+// validatePreKeys verifica un lote de claves de un solo uso
+func validatePreKeys(keys []models.OneTimePreKey) error {
+	if len(keys) > models.MaxOneTimePreKeysPerUpload {
+		return fmt.Errorf("too many prekeys")
+	}
+
+	for _, key := range keys {
+		if key.PublicKey == "" {
+			return fmt.Errorf("invalid device keys")
+		}
+	}
+
+	return nil
+}
+
+// keyBundlePruneThreshold es el número de entradas a partir del cual se descartan las ventanas ya cerradas
+const keyBundlePruneThreshold = 10000
+
+// keyBundleLimiter cuenta los paquetes de claves que cada usuario pide de otro dentro de una ventana de tiempo
+type keyBundleLimiter struct {
+	mu      sync.Mutex
+	windows map[string]keyBundleWindow // clave: requesterID + "/" + targetID
+}
+
+// keyBundleWindow es una ventana de peticiones de un usuario a otro
+type keyBundleWindow struct {
+	start time.Time
+	count int
+}
+
+// newKeyBundleLimiter crea un limitador vacío
+func newKeyBundleLimiter() *keyBundleLimiter {
+	return &keyBundleLimiter{
+		windows: make(map[string]keyBundleWindow),
+	}
+}
+
+// allow cuenta una petición del paquete de claves de targetID por requesterID, y devuelve false si ya
+// se alcanzó el límite de la ventana actual
+func (l *keyBundleLimiter) allow(requesterID, targetID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	key := requesterID + "/" + targetID
+	window, ok := l.windows[key]
+	if !ok || now.Sub(window.start) >= models.KeyBundleRequestWindow {
+		if len(l.windows) >= keyBundlePruneThreshold {
+			for k, w := range l.windows {
+				if now.Sub(w.start) >= models.KeyBundleRequestWindow {
+					delete(l.windows, k)
+				}
+			}
+		}
+		window = keyBundleWindow{start: now}
+	}
+
+	if window.count >= models.MaxKeyBundleRequests {
+		return false
+	}
+
+	window.count++
+	l.windows[key] = window
+	return true
+}
@@ -10,18 +10,29 @@ import (
 	"github.com/google/uuid"
 )
 
-const (
-	// MaxReportsBeforeBan is the threshold of reports a user can receive before being banned from a room
-	MaxReportsBeforeBan = 3
-)
-
 // ModerationService handles operations related to content moderation and user reports
 type ModerationService struct {
 	reportRepo  *repositories.ReportRepository
 	messageRepo *repositories.MessageRepository
 	roomRepo    *repositories.RoomRepository
 	userRepo    *repositories.UserRepository
-	roomService *RoomService
+	permissions *PermissionService
+	sanctions   *SanctionService
+	audit       *AuditService
+}
+
+// Moderation queue pages
+const (
+	defaultReportQueueLimit = 50
+	maxReportQueueLimit     = 100
+	maxModerationActions    = 100
+)
+
+// ResolutionResult is the outcome of resolving reports with a moderation action
+type ResolutionResult struct {
+	Action    *models.ModerationAction
+	Sanction  *models.RoomSanction // Mute or ban issued by the action
+	WasMember bool                 // The ban removed the user from the room
 }
 
 // NewModerationService creates a new instance of ModerationService
@@ -30,14 +41,18 @@ func NewModerationService(
 	messageRepo *repositories.MessageRepository,
 	roomRepo *repositories.RoomRepository,
 	userRepo *repositories.UserRepository,
-	roomService *RoomService,
+	permissions *PermissionService,
+	sanctions *SanctionService,
+	audit *AuditService,
 ) *ModerationService {
 	return &ModerationService{
 		reportRepo:  reportRepo,
 		messageRepo: messageRepo,
 		roomRepo:    roomRepo,
 		userRepo:    userRepo,
-		roomService: roomService,
+		permissions: permissions,
+		sanctions:   sanctions,
+		audit:       audit,
 	}
 }
 
@@ -54,17 +69,18 @@ func (s *ModerationService) ReportMessage(reporterID, roomID, messageID, reason
 		return fmt.Errorf("users cannot report their own messages")
 	}
 
+	room, err := s.roomRepo.GetRoom(roomID)
+	if err != nil {
+		return fmt.Errorf("room not found: %v", err)
+	}
+
 	// Check if the reporter is banned in the room
-	if !s.CanUserSendMessageInRoom(roomID, reporterID) {
+	if !s.permissions.Can(room, reporterID, models.CapSendMessages) {
 		return fmt.Errorf("banned users cannot report messages")
 	}
 
-	// Check if the reported message is from an admin or owner
-	isAdminOrOwner, err := s.roomService.IsUserAdminOrOwner(roomID, message.UserID)
-	if err != nil {
-		return fmt.Errorf("error checking user privileges: %v", err)
-	}
-	if isAdminOrOwner {
+	// Messages from users who can moderate the room cannot be reported
+	if s.permissions.IsStaff(room, message.UserID) {
 		return fmt.Errorf("messages from admins or room owner cannot be reported")
 	}
 
@@ -78,6 +94,19 @@ func (s *ModerationService) ReportMessage(reporterID, roomID, messageID, reason
 		return fmt.Errorf("user has already reported this message")
 	}
 
+	// Weigh the report by the reporter's account age and track record
+	ctx := context.Background()
+	reporter, err := s.userRepo.GetUserByID(ctx, reporterID)
+	if err != nil || reporter == nil {
+		return fmt.Errorf("reporter not found")
+	}
+
+	now := time.Now()
+	accountAge := time.Duration(0)
+	if !reporter.CreatedAt.IsZero() {
+		accountAge = now.Sub(reporter.CreatedAt)
+	}
+
 	// Create a report record
 	report := &models.Report{
 		ID:         uuid.New().String(),
@@ -86,7 +115,11 @@ func (s *ModerationService) ReportMessage(reporterID, roomID, messageID, reason
 		ReportedID: message.UserID, // The user who sent the message
 		ReporterID: reporterID,     // The user making the report
 		Reason:     reason,
-		CreatedAt:  time.Now(),
+		CreatedAt:  now,
+		Weight:     models.ReportWeight(accountAge, reporter.ReportRecord),
+		// Keep a copy of the message so moderators can review it even if it is deleted
+		MessageContent: message.Content,
+		Status:         models.ReportStatusOpen,
 	}
 
 	// Save the report
@@ -94,25 +127,74 @@ func (s *ModerationService) ReportMessage(reporterID, roomID, messageID, reason
 		return fmt.Errorf("failed to create report: %v", err)
 	}
 
-	// Get current reported users for the room
-	room, err := s.roomRepo.GetRoom(roomID)
+	// Add the report to the reported user's score in the room
+	err = s.reportRepo.AddWeightedReport(roomID, message.UserID, models.WeightedReport{
+		ReportID:  report.ID,
+		Weight:    report.Weight,
+		CreatedAt: report.CreatedAt,
+	})
 	if err != nil {
-		return fmt.Errorf("room not found: %v", err)
+		return fmt.Errorf("failed to update room report scores: %v", err)
 	}
 
-	// Initialize reportedUsers map if it doesn't exist
-	if room.ReportedUsers == nil {
-		room.ReportedUsers = make(map[string]int)
+	if err := s.userRepo.RecordReportFiled(ctx, reporterID); err != nil {
+		return fmt.Errorf("failed to update reporter record: %v", err)
 	}
 
-	// Increment report count for the user
-	room.ReportedUsers[message.UserID]++
+	s.audit.Record(&models.AuditEntry{
+		RoomID:     roomID,
+		Action:     models.AuditReportCreated,
+		ActorID:    reporterID,
+		TargetType: models.AuditTargetMessage,
+		TargetID:   messageID,
+		After: map[string]interface{}{
+			"reportId":   report.ID,
+			"reportedId": report.ReportedID,
+			"weight":     report.Weight,
+		},
+		Reason: reason,
+	})
 
-	// Update the room with the new reported users
-	if err := s.reportRepo.UpdateRoomReportedUsers(roomID, room.ReportedUsers); err != nil {
-		return fmt.Errorf("failed to update room reported users: %v", err)
+	return nil
+}
+
+// CreateSystemReport files a report on behalf of the system for a message flagged by the content filter.
+// It goes to the moderation queue like any other report but has no weight, so it never restricts the sender
+// by itself. Messages from users who can moderate the room are not reported
+func (s *ModerationService) CreateSystemReport(room *models.Room, message *models.Message, content, reason string) error {
+	if s.permissions.IsStaff(room, message.UserID) {
+		return nil
 	}
 
+	report := &models.Report{
+		ID:             uuid.New().String(),
+		MessageID:      message.ID,
+		RoomID:         room.ID,
+		ReportedID:     message.UserID,
+		Reason:         reason,
+		CreatedAt:      time.Now(),
+		MessageContent: content,
+		Status:         models.ReportStatusOpen,
+		System:         true,
+	}
+
+	if err := s.reportRepo.CreateReport(report); err != nil {
+		return fmt.Errorf("failed to create report: %v", err)
+	}
+
+	s.audit.Record(&models.AuditEntry{
+		RoomID:     room.ID,
+		Action:     models.AuditReportCreated,
+		TargetType: models.AuditTargetMessage,
+		TargetID:   message.ID,
+		After: map[string]interface{}{
+			"reportId":   report.ID,
+			"reportedId": report.ReportedID,
+			"system":     true,
+		},
+		Reason: reason,
+	})
+
 	return nil
 }
 
@@ -130,77 +212,356 @@ func (s *ModerationService) GetBannedUsersInRoom(roomID string) (*models.BannedU
 	}
 
 	// If no reported users, return empty list
-	if len(room.ReportedUsers) == 0 {
+	if len(room.ReportScores) == 0 {
 		return response, nil
 	}
 
 	// Fetch user details for each reported user
-	for userID, reportCount := range room.ReportedUsers {
-		// Only include users who have reached or exceeded the threshold
-		if reportCount >= MaxReportsBeforeBan {
-			// Get user details
-			ctx := context.Background()
-			user, err := s.userRepo.GetUserByID(ctx, userID)
-			if err != nil {
-				// Skip this user if we can't get their details
-				continue
-			} // Add to response
-			response.Users = append(response.Users, models.BannedUserResponse{
-				UserID:      userID,
-				DisplayName: user.DisplayName,
-				ReportCount: reportCount,
-			})
+	now := time.Now()
+	for userID := range room.ReportScores {
+		// Only include users whose weighted reports reach the room's threshold
+		if !room.IsOverReportThreshold(userID, now) {
+			continue
+		}
+
+		// Get user details
+		ctx := context.Background()
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil || user == nil {
+			// Skip this user if we can't get their details
+			continue
 		}
+
+		score, count := room.ReportScore(userID, now)
+		response.Users = append(response.Users, models.BannedUserResponse{
+			UserID:      userID,
+			DisplayName: user.DisplayName,
+			ReportCount: count,
+			ReportScore: score,
+		})
 	}
 
 	return response, nil
 }
 
-// ClearReportsForUser clears all reports for a specific user in a room
-func (s *ModerationService) ClearReportsForUser(roomID, userID string) error {
-	// Delete the reports from the reports collection
-	if err := s.reportRepo.DeleteReportsForUserInRoom(roomID, userID); err != nil {
-		return fmt.Errorf("failed to delete reports: %v", err)
-	}
-
-	// Get the room to update the reported users map
+// ClearReportsForUser dismisses all open reports for a specific user in a room and clears their score.
+// The cleared reports count as dismissed in the track record of the users who filed them
+func (s *ModerationService) ClearReportsForUser(roomID, actorID, userID, reason string) error {
 	room, err := s.roomRepo.GetRoom(roomID)
 	if err != nil {
 		return fmt.Errorf("room not found: %v", err)
 	}
+	score, _ := room.ReportScore(userID, time.Now())
 
-	// If no reported users, nothing to do
-	if room.ReportedUsers == nil {
-		return nil
+	reports, err := s.reportRepo.GetOpenReportsForUserInRoom(roomID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get reports: %v", err)
 	}
 
-	// Remove the user from the reported users map
-	delete(room.ReportedUsers, userID)
+	var reportIDs []string
+	if len(reports) > 0 {
+		action := newModerationAction(roomID, actorID, userID, models.ModerationActionDismiss, reason, reports)
+		reportIDs = action.ReportIDs
+		if err := s.reportRepo.ResolveReports(action, models.ReportStatusDismissed); err != nil {
+			return fmt.Errorf("failed to dismiss reports: %v", err)
+		}
+	}
+
+	// Remove the whole score from the room, including reports migrated from the old counters
+	if err := s.reportRepo.ClearReportScore(roomID, userID); err != nil {
+		return fmt.Errorf("failed to update room report scores: %v", err)
+	}
 
-	// Update the room with the new reported users
-	if err := s.reportRepo.UpdateRoomReportedUsers(roomID, room.ReportedUsers); err != nil {
-		return fmt.Errorf("failed to update room reported users: %v", err)
+	if err := s.userRepo.RecordReportsDismissed(context.Background(), countByReporter(reports)); err != nil {
+		return fmt.Errorf("failed to update reporter records: %v", err)
 	}
 
+	s.audit.Record(&models.AuditEntry{
+		RoomID:     roomID,
+		Action:     models.AuditReportsCleared,
+		ActorID:    actorID,
+		TargetType: models.AuditTargetUser,
+		TargetID:   userID,
+		Before:     map[string]interface{}{"reportScore": score, "openReportIds": reportIDs},
+		After:      map[string]interface{}{"reportScore": 0},
+		Reason:     reason,
+	})
+
 	return nil
 }
 
-// CanUserSendMessageInRoom checks if a user can send messages in a room based on report count
+// GetReportQueue returns a page of the reports in a room with a given status, oldest first, with the
+// reported message and the names of the reporter and the reported user
+func (s *ModerationService) GetReportQueue(roomID, actorID, status string, limit int, cursor string) (*models.PaginatedReportsResponse, error) {
+	if _, err := s.getRoomForModeration(roomID, actorID); err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		status = models.ReportStatusOpen
+	}
+	if status != models.ReportStatusOpen && status != models.ReportStatusDismissed && status != models.ReportStatusActioned {
+		return nil, fmt.Errorf("invalid report status")
+	}
+
+	if limit <= 0 {
+		limit = defaultReportQueueLimit
+	}
+	if limit > maxReportQueueLimit {
+		limit = maxReportQueueLimit
+	}
+
+	reports, nextCursor, err := s.reportRepo.GetRoomReports(roomID, status, limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs []string
+	for _, report := range reports {
+		userIDs = append(userIDs, report.ReportedID)
+		if report.ReporterID != "" {
+			userIDs = append(userIDs, report.ReporterID)
+		}
+	}
+	users, err := s.userRepo.GetUsersByIDs(context.Background(), userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range reports {
+		if user, ok := users[reports[i].ReporterID]; ok {
+			reports[i].ReporterName = user.DisplayName
+		}
+		if user, ok := users[reports[i].ReportedID]; ok {
+			reports[i].ReportedName = user.DisplayName
+		}
+
+		// Reports filed before the content was copied read it from the message, if it still has it
+		if reports[i].MessageContent == "" {
+			if message, err := s.messageRepo.GetMessageByID(roomID, reports[i].MessageID); err == nil {
+				reports[i].MessageContent = message.Content
+			}
+		}
+	}
+
+	return &models.PaginatedReportsResponse{
+		Reports:    reports,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	}, nil
+}
+
+// ResolveReports resolves open reports against a user with a moderation action. Dismissing counts
+// against the reporters' track record; the other actions mark the reports as actioned. Mutes and bans
+// also require the moderate_members capability and deleting the message delete_messages
+func (s *ModerationService) ResolveReports(roomID, actorID string, req *models.ResolveReportsRequest) (*ResolutionResult, error) {
+	room, err := s.getRoomForModeration(roomID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.ReportIDs) == 0 {
+		return nil, fmt.Errorf("no reports to resolve")
+	}
+	if len(req.ReportIDs) > models.MaxReportsPerResolution {
+		return nil, fmt.Errorf("too many reports")
+	}
+
+	switch req.Action {
+	case models.ModerationActionDismiss, models.ModerationActionDeleteMessage, models.ModerationActionWarn,
+		models.ModerationActionMute, models.ModerationActionBan:
+	default:
+		return nil, fmt.Errorf("invalid moderation action")
+	}
+
+	var reportIDs []string
+	for _, reportID := range req.ReportIDs {
+		if !contains(reportIDs, reportID) {
+			reportIDs = append(reportIDs, reportID)
+		}
+	}
+
+	reports, err := s.reportRepo.GetReportsByIDs(reportIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) != len(reportIDs) {
+		return nil, fmt.Errorf("report not found")
+	}
+
+	targetID := reports[0].ReportedID
+	messageID := reports[0].MessageID
+	for _, report := range reports {
+		if report.RoomID != roomID {
+			return nil, fmt.Errorf("report not found")
+		}
+		if report.Status != "" && report.Status != models.ReportStatusOpen {
+			return nil, fmt.Errorf("report already resolved")
+		}
+		if report.ReportedID != targetID {
+			return nil, fmt.Errorf("reports must be against the same user")
+		}
+		if req.Action == models.ModerationActionDeleteMessage && report.MessageID != messageID {
+			return nil, fmt.Errorf("reports must be about the same message")
+		}
+	}
+
+	action := newModerationAction(roomID, actorID, targetID, req.Action, req.Reason, reports)
+	result := &ResolutionResult{Action: action}
+	status := models.ReportStatusActioned
+
+	switch req.Action {
+	case models.ModerationActionDismiss:
+		status = models.ReportStatusDismissed
+
+	case models.ModerationActionDeleteMessage:
+		if !s.permissions.Can(room, actorID, models.CapDeleteMessages) {
+			return nil, fmt.Errorf("user is not allowed to delete this message")
+		}
+		message, err := s.messageRepo.GetMessageByID(roomID, messageID)
+		if err != nil {
+			return nil, fmt.Errorf("message not found")
+		}
+		if !message.IsDeleted {
+			if err := s.messageRepo.SoftDeleteMessage(roomID, messageID); err != nil {
+				return nil, err
+			}
+			action.DeletedMessageIDs = []string{messageID}
+			s.audit.Record(&models.AuditEntry{
+				RoomID:     roomID,
+				Action:     models.AuditMessageDeleted,
+				ActorID:    actorID,
+				TargetType: models.AuditTargetMessage,
+				TargetID:   messageID,
+				Before:     map[string]interface{}{"authorId": message.UserID, "content": message.Content},
+				Reason:     req.Reason,
+			})
+		}
+
+	case models.ModerationActionMute:
+		sanction, err := s.sanctions.MuteMember(roomID, actorID, targetID, &models.MuteMemberRequest{
+			DurationSeconds: req.DurationSeconds,
+			Reason:          req.Reason,
+		})
+		if err != nil {
+			return nil, err
+		}
+		action.SanctionID = sanction.ID
+		result.Sanction = sanction
+
+	case models.ModerationActionBan:
+		ban, err := s.sanctions.BanUser(roomID, actorID, targetID, &models.BanMemberRequest{
+			DurationSeconds:     req.DurationSeconds,
+			Reason:              req.Reason,
+			DeleteMessagesHours: req.DeleteMessagesHours,
+		})
+		if ban != nil {
+			// The messages deleted before a failure must still be announced
+			action.DeletedMessageIDs = ban.DeletedMessageIDs
+		}
+		if err != nil {
+			return result, err
+		}
+		action.SanctionID = ban.Sanction.ID
+		result.Sanction = ban.Sanction
+		result.WasMember = ban.WasMember
+	}
+
+	if err := s.reportRepo.ResolveReports(action, status); err != nil {
+		return nil, err
+	}
+
+	if status == models.ReportStatusDismissed {
+		if err := s.userRepo.RecordReportsDismissed(context.Background(), countByReporter(reports)); err != nil {
+			return nil, fmt.Errorf("failed to update reporter records: %v", err)
+		}
+	}
+
+	s.audit.Record(&models.AuditEntry{
+		RoomID:     roomID,
+		Action:     models.AuditReportsResolved,
+		ActorID:    actorID,
+		TargetType: models.AuditTargetUser,
+		TargetID:   targetID,
+		Before:     map[string]interface{}{"status": models.ReportStatusOpen},
+		After: map[string]interface{}{
+			"status":     status,
+			"action":     action.Type,
+			"actionId":   action.ID,
+			"reportIds":  action.ReportIDs,
+			"sanctionId": action.SanctionID,
+		},
+		Reason: req.Reason,
+	})
+
+	return result, nil
+}
+
+// GetModerationActions returns the latest moderation actions of a room, newest first
+func (s *ModerationService) GetModerationActions(roomID, actorID string) ([]models.ModerationAction, error) {
+	if _, err := s.getRoomForModeration(roomID, actorID); err != nil {
+		return nil, err
+	}
+
+	return s.reportRepo.GetModerationActions(roomID, maxModerationActions)
+}
+
+// MigrateReports marks the reports created before reports had a status as open
+func (s *ModerationService) MigrateReports() error {
+	return s.reportRepo.MigrateReports()
+}
+
+// CanUserSendMessageInRoom checks if a user can send messages to the main timeline of a room based on their role and report count
 func (s *ModerationService) CanUserSendMessageInRoom(roomID, userID string) bool {
-	// Get the room
+	_, err := s.permissions.CheckSendMessage(roomID, userID, "")
+	return err == nil
+}
+
+// CanManageReports checks if a user can review and clear reports in a room
+func (s *ModerationService) CanManageReports(roomID, userID string) (bool, error) {
+	return s.permissions.CanInRoom(roomID, userID, models.CapManageReports)
+}
+
+// getRoomForModeration loads the room and checks that the user can manage its reports
+func (s *ModerationService) getRoomForModeration(roomID, actorID string) (*models.Room, error) {
 	room, err := s.roomRepo.GetRoom(roomID)
-	if err != nil {
-		// If we can't get the room, default to allowing the message
-		return true
+	if err != nil || room.IsDeleted {
+		return nil, fmt.Errorf("room not found")
 	}
 
-	// If no reported users or user not in reported users, they can send messages
-	if room.ReportedUsers == nil {
-		return true
+	if !s.permissions.Can(room, actorID, models.CapManageReports) {
+		return nil, fmt.Errorf("user is not allowed to manage reports")
 	}
 
-	reportCount, exists := room.ReportedUsers[userID]
+	return room, nil
+}
+
+// newModerationAction creates a moderation action that resolves the given reports
+func newModerationAction(roomID, actorID, targetID, actionType, reason string, reports []models.Report) *models.ModerationAction {
+	reportIDs := make([]string, 0, len(reports))
+	for _, report := range reports {
+		reportIDs = append(reportIDs, report.ID)
+	}
+
+	return &models.ModerationAction{
+		ID:        uuid.New().String(),
+		RoomID:    roomID,
+		Type:      actionType,
+		ActorID:   actorID,
+		TargetID:  targetID,
+		ReportIDs: reportIDs,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+}
 
-	// If the user has fewer reports than the threshold or isn't reported, they can send messages
-	return !exists || reportCount < MaxReportsBeforeBan
+// countByReporter counts the reports filed by each reporter. System reports have no reporter
+func countByReporter(reports []models.Report) map[string]int {
+	counts := make(map[string]int)
+	for _, report := range reports {
+		if report.ReporterID != "" {
+			counts[report.ReporterID]++
+		}
+	}
+	return counts
 }
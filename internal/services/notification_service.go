@@ -0,0 +1,220 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/repositories"
+)
+
+// NotificationRecipient es un usuario que debe recibir una notificación y cómo entregársela
+type NotificationRecipient struct {
+	UserID    string
+	Mentioned bool
+	Sound     bool
+	Push      bool // Los envíos push solo deben hacerse a los destinatarios que lo tengan activo
+}
+
+// NotificationService maneja las preferencias de notificación y decide quién recibe cada notificación.
+// Todas las vías de entrega (eventos WebSocket por usuario y envíos push) deben pasar por Recipients
+type NotificationService struct {
+	NotificationRepo *repositories.NotificationRepository
+	RoomRepo         *repositories.RoomRepository
+	DirectChatRepo   *repositories.DirectChatRepository
+}
+
+// NewNotificationService crea una nueva instancia de NotificationService
+func NewNotificationService(
+	notificationRepo *repositories.NotificationRepository,
+	roomRepo *repositories.RoomRepository,
+	directChatRepo *repositories.DirectChatRepository,
+) *NotificationService {
+	return &NotificationService{
+		NotificationRepo: notificationRepo,
+		RoomRepo:         roomRepo,
+		DirectChatRepo:   directChatRepo,
+	}
+}
+
+// GetUserPreferences devuelve las preferencias que el usuario ha configurado en todas sus conversaciones
+func (s *NotificationService) GetUserPreferences(userID string) ([]models.NotificationPreference, error) {
+	return s.NotificationRepo.GetUserPreferences(userID)
+}
+
+// GetPreference devuelve las preferencias del usuario para una conversación a la que pertenece
+func (s *NotificationService) GetPreference(userID, conversationType, conversationID string) (*models.NotificationPreference, error) {
+	if err := s.checkConversationAccess(userID, conversationType, conversationID); err != nil {
+		return nil, err
+	}
+
+	return s.getPreference(userID, conversationType, conversationID)
+}
+
+// UpdatePreference cambia las preferencias del usuario para una conversación a la que pertenece
+func (s *NotificationService) UpdatePreference(
+	userID, conversationType, conversationID string,
+	req *models.UpdateNotificationPreferenceRequest,
+) (*models.NotificationPreference, error) {
+	if err := s.checkConversationAccess(userID, conversationType, conversationID); err != nil {
+		return nil, err
+	}
+
+	pref, err := s.getPreference(userID, conversationType, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Silenciar o reactivar sin fecha quita cualquier fecha de fin anterior
+	if req.Muted != nil {
+		pref.Muted = *req.Muted
+		pref.MutedUntil = nil
+	}
+	if req.MutedUntil != nil {
+		if !req.MutedUntil.After(time.Now()) {
+			return nil, fmt.Errorf("mute end must be in the future")
+		}
+		pref.Muted = true
+		pref.MutedUntil = req.MutedUntil
+	}
+	if req.MentionsOnly != nil {
+		pref.MentionsOnly = *req.MentionsOnly
+	}
+	if req.Sound != nil {
+		pref.Sound = *req.Sound
+	}
+	if req.Push != nil {
+		pref.Push = *req.Push
+	}
+	pref.UpdatedAt = time.Now()
+
+	if err := s.NotificationRepo.SavePreference(userID, pref); err != nil {
+		return nil, err
+	}
+
+	return pref, nil
+}
+
+// AttachRoomPreferences añade a cada sala las preferencias de notificación del usuario
+func (s *NotificationService) AttachRoomPreferences(userID string, rooms []models.Room) error {
+	prefs, err := s.preferencesByKey(userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range rooms {
+		rooms[i].NotificationPreferences = preferenceOrDefault(prefs, models.ConversationTypeRoom, rooms[i].ID)
+	}
+
+	return nil
+}
+
+// AttachDirectChatPreferences añade a cada chat directo las preferencias de notificación del usuario
+func (s *NotificationService) AttachDirectChatPreferences(userID string, chats []models.DirectChat) error {
+	prefs, err := s.preferencesByKey(userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range chats {
+		chats[i].NotificationPreferences = preferenceOrDefault(prefs, models.ConversationTypeDirect, chats[i].ID)
+	}
+
+	return nil
+}
+
+// Recipients filtra los usuarios que deben recibir una notificación de una conversación según sus
+// preferencias: se descartan las conversaciones silenciadas y, en modo solo menciones, los eventos
+// que no mencionan al usuario
+func (s *NotificationService) Recipients(
+	conversationType, conversationID string,
+	userIDs []string,
+	mentions []string,
+) ([]NotificationRecipient, error) {
+	prefs, err := s.NotificationRepo.GetPreferencesForUsers(userIDs, conversationType, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	recipients := make([]NotificationRecipient, 0, len(userIDs))
+	for _, userID := range userIDs {
+		pref, ok := prefs[userID]
+		if !ok {
+			defaults := models.DefaultNotificationPreference(conversationType, conversationID)
+			pref = &defaults
+		}
+
+		mentioned := contains(mentions, userID)
+		if !pref.ShouldNotify(now, mentioned) {
+			continue
+		}
+
+		recipients = append(recipients, NotificationRecipient{
+			UserID:    userID,
+			Mentioned: mentioned,
+			Sound:     pref.Sound,
+			Push:      pref.Push,
+		})
+	}
+
+	return recipients, nil
+}
+
+// getPreference obtiene las preferencias guardadas o las de por defecto
+func (s *NotificationService) getPreference(userID, conversationType, conversationID string) (*models.NotificationPreference, error) {
+	prefs, err := s.NotificationRepo.GetPreferencesForUsers([]string{userID}, conversationType, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pref, ok := prefs[userID]; ok {
+		return pref, nil
+	}
+
+	defaults := models.DefaultNotificationPreference(conversationType, conversationID)
+	return &defaults, nil
+}
+
+// preferencesByKey indexa las preferencias del usuario por tipo e ID de conversación
+func (s *NotificationService) preferencesByKey(userID string) (map[string]models.NotificationPreference, error) {
+	prefs, err := s.NotificationRepo.GetUserPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]models.NotificationPreference, len(prefs))
+	for _, pref := range prefs {
+		byKey[models.NotificationPreferenceKey(pref.ConversationType, pref.ConversationID)] = pref
+	}
+
+	return byKey, nil
+}
+
+// preferenceOrDefault devuelve las preferencias de una conversación o las de por defecto
+func preferenceOrDefault(prefs map[string]models.NotificationPreference, conversationType, conversationID string) *models.NotificationPreference {
+	pref, ok := prefs[models.NotificationPreferenceKey(conversationType, conversationID)]
+	if !ok {
+		pref = models.DefaultNotificationPreference(conversationType, conversationID)
+	}
+	return &pref
+}
+
+// checkConversationAccess verifica que el usuario pertenece a la conversación
+func (s *NotificationService) checkConversationAccess(userID, conversationType, conversationID string) error {
+	switch conversationType {
+	case models.ConversationTypeRoom:
+		room, err := s.RoomRepo.GetRoom(conversationID)
+		if err != nil || room.IsDeleted || !s.RoomRepo.HasRoomAccess(room, userID) {
+			return fmt.Errorf("conversation not found")
+		}
+	case models.ConversationTypeDirect:
+		if !s.DirectChatRepo.IsUserInDirectChat(conversationID, userID) {
+			return fmt.Errorf("conversation not found")
+		}
+	default:
+		return fmt.Errorf("invalid conversation type")
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/repositories"
+)
+
+// PermissionService comprueba en un único sitio qué puede hacer un usuario dentro de una sala.
+// Los handlers, el servicio de moderación y el hub de WebSocket pasan todos por él
+type PermissionService struct {
+	roomRepo *repositories.RoomRepository
+}
+
+// NewPermissionService crea una nueva instancia de PermissionService
+func NewPermissionService(roomRepo *repositories.RoomRepository) *PermissionService {
+	return &PermissionService{
+		roomRepo: roomRepo,
+	}
+}
+
+// Can verifica si el rol del usuario en la sala le da una capacidad.
+// Los usuarios baneados no tienen ninguna, y los silenciados o restringidos por reportes no pueden enviar mensajes
+func (s *PermissionService) Can(room *models.Room, userID string, capability models.Capability) bool {
+	if room == nil || room.IsDeleted {
+		return false
+	}
+
+	now := time.Now()
+	if room.IsBanned(userID, now) || !room.HasCapability(userID, capability) {
+		return false
+	}
+
+	if capability == models.CapSendMessages && (room.IsMuted(userID, now) || s.IsRestricted(room, userID)) {
+		return false
+	}
+
+	return true
+}
+
+// CanInRoom carga la sala y verifica si el usuario tiene una capacidad en ella
+func (s *PermissionService) CanInRoom(roomID, userID string, capability models.Capability) (bool, error) {
+	room, err := s.roomRepo.GetRoom(roomID)
+	if err != nil {
+		return false, fmt.Errorf("error getting room: %v", err)
+	}
+
+	return s.Can(room, userID, capability), nil
+}
+
+// CheckSendMessage devuelve la sala si el usuario puede enviar un mensaje en ella, o un error con el motivo.
+// threadID es el mensaje raíz cuando el mensaje responde en un hilo, o vacío para el timeline principal
+func (s *PermissionService) CheckSendMessage(roomID, userID, threadID string) (*models.Room, error) {
+	room, err := s.roomRepo.GetRoom(roomID)
+	if err != nil || room.IsDeleted || !room.HasCapability(userID, models.CapSendMessages) ||
+		room.IsBanned(userID, time.Now()) {
+		return nil, fmt.Errorf("No permission to send messages to this room")
+	}
+
+	if room.IsMuted(userID, time.Now()) {
+		if expiresAt := room.Mutes[userID].ExpiresAt; expiresAt != nil {
+			return nil, fmt.Errorf("You have been muted in this room until %s", expiresAt.Format(time.RFC3339))
+		}
+		return nil, fmt.Errorf("You have been muted in this room")
+	}
+
+	if s.IsRestricted(room, userID) {
+		return nil, fmt.Errorf("You have been banned from sending messages in this room due to reports")
+	}
+
+	// En las salas de solo anuncios el resto de miembros solo puede responder en hilos, y solo si la sala lo permite
+	if room.AnnouncementOnly && !room.HasCapability(userID, models.CapPostAnnouncements) &&
+		(threadID == "" || !room.AllowThreadReplies) {
+		return nil, fmt.Errorf("Only admins can post in this announcement-only room")
+	}
+
+	return room, nil
+}
+
+// IsRestricted verifica si los reportes ponderados contra un usuario dentro de la ventana de la sala alcanzan su umbral
+func (s *PermissionService) IsRestricted(room *models.Room, userID string) bool {
+	return room.IsOverReportThreshold(userID, time.Now())
+}
+
+// IsStaff verifica si el usuario puede moderar la sala. Los mensajes del equipo de moderación no se pueden reportar
+func (s *PermissionService) IsStaff(room *models.Room, userID string) bool {
+	return s.Can(room, userID, models.CapManageReports)
+}
+
+// CanManageRole verifica si el usuario puede asignar o modificar un rol. Salvo el propietario,
+// nadie puede repartir capacidades que no tiene
+func (s *PermissionService) CanManageRole(room *models.Room, actorID string, role models.RoomRole) bool {
+	if !s.Can(room, actorID, models.CapManageRoles) {
+		return false
+	}
+
+	if room.OwnerID == actorID {
+		return true
+	}
+
+	for _, capability := range role.Capabilities {
+		if !room.HasCapability(actorID, capability) {
+			return false
+		}
+	}
+
+	return true
+}
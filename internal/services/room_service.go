@@ -1,24 +1,66 @@
 package services
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/Parchat/backend/internal/models"
 	"github.com/Parchat/backend/internal/repositories"
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// ownershipTransferTTL es el tiempo que tiene el destinatario para aceptar una transferencia de propiedad
+const ownershipTransferTTL = 7 * 24 * time.Hour
+
+const (
+	// defaultMembersLimit es el tamaño de página por defecto del directorio de miembros
+	defaultMembersLimit = 50
+	// maxMembersLimit es el tamaño de página máximo del directorio de miembros
+	maxMembersLimit = 100
+	// defaultDiscoveryLimit es el tamaño de página por defecto del descubrimiento de salas
+	defaultDiscoveryLimit = 20
+	// maxDiscoveryLimit es el tamaño de página máximo del descubrimiento de salas
+	maxDiscoveryLimit = 50
+)
+
+// roleNamePattern define los nombres válidos para los roles propios de una sala
+var roleNamePattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
 // RoomService maneja la lógica de negocio relacionada con salas de chat
 type RoomService struct {
 	RoomRepo    *repositories.RoomRepository
 	MessageRepo *repositories.MessageRepository
+	UserRepo    *repositories.UserRepository
+	SpaceRepo   *repositories.SpaceRepository
+	Permissions *PermissionService
+	Audit       *AuditService
 }
 
 // NewRoomService crea una nueva instancia de RoomService
-func NewRoomService(roomRepo *repositories.RoomRepository, messageRepo *repositories.MessageRepository) *RoomService {
+func NewRoomService(
+	roomRepo *repositories.RoomRepository,
+	messageRepo *repositories.MessageRepository,
+	userRepo *repositories.UserRepository,
+	spaceRepo *repositories.SpaceRepository,
+	permissions *PermissionService,
+	audit *AuditService,
+) *RoomService {
 	return &RoomService{
 		RoomRepo:    roomRepo,
 		MessageRepo: messageRepo,
+		UserRepo:    userRepo,
+		SpaceRepo:   spaceRepo,
+		Permissions: permissions,
+		Audit:       audit,
 	}
 }
 
@@ -38,12 +80,193 @@ func (s *RoomService) CreateRoom(room *models.Room) error {
 		room.Members = append(room.Members, room.OwnerID)
 	}
 
+	// Registrar la antigüedad de los admins iniciales y asignar los roles predefinidos
+	now := time.Now()
+	room.AdminSince = make(map[string]time.Time)
+	room.MemberRoles = make(map[string]string)
+	room.JoinedAt = make(map[string]time.Time)
+	for _, memberID := range room.Members {
+		room.MemberRoles[memberID] = models.RoleMember
+		room.JoinedAt[memberID] = now
+	}
+	for _, adminID := range room.Admins {
+		room.AdminSince[adminID] = now
+		room.MemberRoles[adminID] = models.RoleAdmin
+	}
+	room.MemberRoles[room.OwnerID] = models.RoleOwner
+	room.Roles = nil
+	room.OwnershipTransfer = nil
+
+	if err := validateSlowMode(room.SlowModeSeconds); err != nil {
+		return err
+	}
+
+	// Sin valor se usan el umbral y la ventana de reportes por defecto
+	if room.ReportThreshold != 0 {
+		if err := validateReportThreshold(room.ReportThreshold); err != nil {
+			return err
+		}
+	}
+	if room.ReportWindowHours != 0 {
+		if err := validateReportWindow(room.ReportWindowHours); err != nil {
+			return err
+		}
+	}
+
+	// Preparar los datos del descubrimiento
+	categories, err := normalizeCategories(room.Categories)
+	if err != nil {
+		return err
+	}
+	room.Categories = categories
+	room.MemberCount = len(room.Members)
+	room.RefreshSearchTokens()
+
 	return s.RoomRepo.CreateRoom(room)
 }
 
-// GetRoom obtiene una sala por su ID
+// validateSlowMode comprueba que el intervalo del modo lento está entre 0 (desactivado) y el máximo
+func validateSlowMode(seconds int) error {
+	if seconds < 0 || seconds > models.MaxSlowModeSeconds {
+		return fmt.Errorf("invalid slow mode interval")
+	}
+	return nil
+}
+
+// validateReportThreshold comprueba que el umbral de reportes de una sala está dentro de los límites
+func validateReportThreshold(threshold float64) error {
+	if threshold < models.MinReportThreshold || threshold > models.MaxReportThreshold {
+		return fmt.Errorf("invalid report threshold")
+	}
+	return nil
+}
+
+// validateReportWindow comprueba que la ventana de reportes de una sala está dentro de los límites
+func validateReportWindow(hours int) error {
+	if hours < 1 || hours > models.MaxReportWindowHours {
+		return fmt.Errorf("invalid report window")
+	}
+	return nil
+}
+
+// GetRoom obtiene una sala por su ID. Las salas eliminadas se tratan como inexistentes
 func (s *RoomService) GetRoom(roomID string) (*models.Room, error) {
-	return s.RoomRepo.GetRoom(roomID)
+	room, err := s.RoomRepo.GetRoom(roomID)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("room not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if room.IsDeleted {
+		return nil, fmt.Errorf("room not found")
+	}
+
+	return room, nil
+}
+
+// UpdateRoom actualiza la configuración de una sala. Requiere la capacidad manage_room
+func (s *RoomService) UpdateRoom(roomID, userID string, req *models.UpdateRoomRequest) (*models.Room, error) {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.Permissions.Can(room, userID, models.CapManageRoom) {
+		return nil, fmt.Errorf("user is not allowed to update the room")
+	}
+
+	previous := roomSettings(room)
+
+	// Aplicar solo los campos enviados
+	if req.Name != nil {
+		room.Name = *req.Name
+	}
+	if req.Description != nil {
+		room.Description = *req.Description
+	}
+	if req.ImageURL != nil {
+		room.ImageURL = *req.ImageURL
+	}
+	if req.IsPrivate != nil {
+		room.IsPrivate = *req.IsPrivate
+	}
+	if req.Categories != nil {
+		categories, err := normalizeCategories(*req.Categories)
+		if err != nil {
+			return nil, err
+		}
+		room.Categories = categories
+	}
+	if req.SlowModeSeconds != nil {
+		if err := validateSlowMode(*req.SlowModeSeconds); err != nil {
+			return nil, err
+		}
+		room.SlowModeSeconds = *req.SlowModeSeconds
+	}
+	if req.AnnouncementOnly != nil {
+		room.AnnouncementOnly = *req.AnnouncementOnly
+	}
+	if req.AllowThreadReplies != nil {
+		room.AllowThreadReplies = *req.AllowThreadReplies
+	}
+	if req.ReportThreshold != nil {
+		if err := validateReportThreshold(*req.ReportThreshold); err != nil {
+			return nil, err
+		}
+		room.ReportThreshold = *req.ReportThreshold
+	}
+	if req.ReportWindowHours != nil {
+		if err := validateReportWindow(*req.ReportWindowHours); err != nil {
+			return nil, err
+		}
+		room.ReportWindowHours = *req.ReportWindowHours
+	}
+	room.RefreshSearchTokens()
+
+	if err := s.RoomRepo.UpdateRoomSettings(room); err != nil {
+		return nil, err
+	}
+
+	if before, after := changedFields(previous, roomSettings(room)); len(after) > 0 {
+		s.Audit.Record(&models.AuditEntry{
+			RoomID:     roomID,
+			Action:     models.AuditRoomUpdated,
+			ActorID:    userID,
+			TargetType: models.AuditTargetRoom,
+			TargetID:   roomID,
+			Before:     before,
+			After:      after,
+		})
+	}
+
+	return room, nil
+}
+
+// DeleteRoom elimina (soft-delete) una sala. Solo el propietario puede hacerlo
+func (s *RoomService) DeleteRoom(roomID, userID string) error {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+
+	if room.OwnerID != userID {
+		return fmt.Errorf("only the room owner can delete the room")
+	}
+
+	if err := s.RoomRepo.SoftDeleteRoom(roomID); err != nil {
+		return err
+	}
+
+	s.Audit.Record(&models.AuditEntry{
+		RoomID:     roomID,
+		Action:     models.AuditRoomDeleted,
+		ActorID:    userID,
+		TargetType: models.AuditTargetRoom,
+		TargetID:   roomID,
+	})
+	return nil
 }
 
 // GetUserRooms obtiene todas las salas a las que pertenece un usuario
@@ -51,53 +274,764 @@ func (s *RoomService) GetUserRooms(userID string) ([]models.Room, error) {
 	return s.RoomRepo.GetUserRooms(userID)
 }
 
-// GetRoomMessages obtiene los mensajes de una sala con paginación
-func (s *RoomService) GetRoomMessages(roomID string, limit int, cursor string) ([]models.MessageResponse, string, error) {
-	return s.MessageRepo.GetRoomMessages(roomID, limit, cursor)
+// GetRoomMessages obtiene los mensajes de una sala con paginación, sin los de usuarios bloqueados por el usuario.
+// Las páginas pueden traer menos mensajes que el límite, pero el cursor sigue siendo válido
+func (s *RoomService) GetRoomMessages(roomID, userID string, limit int, cursor string) ([]models.MessageResponse, string, error) {
+	messages, nextCursor, err := s.MessageRepo.GetRoomMessages(roomID, limit, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	messages, err = s.hideBlockedMessages(userID, messages)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return messages, nextCursor, nil
+}
+
+// GetThreadReplies obtiene el mensaje raíz de un hilo y sus respuestas
+func (s *RoomService) GetThreadReplies(roomID, messageID, userID string, limit int) (*models.Message, []models.Message, error) {
+	root, err := s.MessageRepo.GetMessageByID(roomID, messageID)
+	if err != nil || root.ThreadID != "" {
+		return nil, nil, fmt.Errorf("message not found")
+	}
+
+	replies, err := s.MessageRepo.GetThreadReplies(roomID, messageID, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ocultar las respuestas de los usuarios bloqueados; el mensaje raíz se mantiene para dar contexto al hilo
+	blockedIDs, err := s.UserRepo.GetBlockedUsers(context.Background(), userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	visible := make([]models.Message, 0, len(replies))
+	for _, reply := range replies {
+		if !contains(blockedIDs, reply.UserID) {
+			visible = append(visible, reply)
+		}
+	}
+
+	return root, visible, nil
+}
+
+// GetRoomMessagesSimple obtiene los mensajes de una sala sin paginación, sin los de usuarios bloqueados por el usuario
+func (s *RoomService) GetRoomMessagesSimple(roomID, userID string, limit int) ([]models.MessageResponse, error) {
+	messages, err := s.MessageRepo.GetRoomMessagesSimple(roomID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.hideBlockedMessages(userID, messages)
 }
 
-// GetRoomMessagesSimple obtiene los mensajes de una sala sin paginación
-func (s *RoomService) GetRoomMessagesSimple(roomID string, limit int) ([]models.MessageResponse, error) {
-	return s.MessageRepo.GetRoomMessagesSimple(roomID, limit)
+// hideBlockedMessages quita los mensajes de los usuarios que el usuario ha bloqueado
+func (s *RoomService) hideBlockedMessages(userID string, messages []models.MessageResponse) ([]models.MessageResponse, error) {
+	blockedIDs, err := s.UserRepo.GetBlockedUsers(context.Background(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterBlockedMessages(messages, blockedIDs), nil
 }
 
-// GetAllRooms obtiene todas las salas ordenadas por fecha de actualización
-func (s *RoomService) GetAllRooms() ([]models.Room, error) {
-	return s.RoomRepo.GetAllRooms()
+// DiscoverRooms obtiene una página de salas públicas con búsqueda, categoría y orden
+func (s *RoomService) DiscoverRooms(q models.RoomDiscoveryQuery) (*models.PaginatedRoomsResponse, error) {
+	q.Search = strings.TrimSpace(q.Search)
+	q.Category = models.NormalizeCategory(q.Category)
+
+	if q.Sort == "" {
+		q.Sort = models.RoomSortActivity
+	}
+	if q.Sort != models.RoomSortActivity && q.Sort != models.RoomSortMembers {
+		return nil, fmt.Errorf("invalid sort")
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = defaultDiscoveryLimit
+	}
+	if q.Limit > maxDiscoveryLimit {
+		q.Limit = maxDiscoveryLimit
+	}
+
+	rooms, nextCursor, err := s.RoomRepo.DiscoverRooms(q)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]models.RoomSummary, 0, len(rooms))
+	for i := range rooms {
+		summaries = append(summaries, rooms[i].Summary())
+	}
+
+	return &models.PaginatedRoomsResponse{
+		Rooms:      summaries,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	}, nil
 }
 
-// JoinRoom permite a un usuario unirse a una sala si tiene permiso
+// JoinRoom permite a un usuario unirse a una sala pública. A las salas privadas solo se entra con una invitación
+// y a los canales de un espacio solo pueden unirse sus miembros
 func (s *RoomService) JoinRoom(roomID string, userID string) error {
-	// Verificar si el usuario puede unirse a la sala
-	// canJoin := s.RoomRepo.CanJoinRoomWebSocket(roomID, userID)
-	// if !canJoin {
-	// 	return fmt.Errorf("user is not allowed to join this room")
-	// }
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+
+	if room.IsPrivate && !s.RoomRepo.HasRoomAccess(room, userID) {
+		return fmt.Errorf("user is not allowed to join this room")
+	}
+
+	if room.IsBanned(userID, time.Now()) {
+		return fmt.Errorf("user is banned from the room")
+	}
+
+	if room.SpaceID != "" {
+		space, err := s.SpaceRepo.GetSpace(room.SpaceID)
+		if err != nil || !space.IsMember(userID) {
+			return fmt.Errorf("user is not allowed to join this room")
+		}
+	}
 
 	// Añadir usuario a la sala
 	return s.RoomRepo.AddMemberToRoom(roomID, userID)
 }
 
-// IsUserAdminOrOwner checks if a user is an admin or owner of a room
-func (s *RoomService) IsUserAdminOrOwner(roomID, userID string) (bool, error) {
-	room, err := s.RoomRepo.GetRoom(roomID)
+// CanViewRoom verifica que el usuario puede leer el contenido de una sala.
+// Las salas privadas solo son visibles para sus miembros
+func (s *RoomService) CanViewRoom(roomID, userID string) error {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+
+	if room.IsPrivate && !s.RoomRepo.HasRoomAccess(room, userID) {
+		return fmt.Errorf("user is not a member of the room")
+	}
+
+	if room.IsBanned(userID, time.Now()) {
+		return fmt.Errorf("user is banned from the room")
+	}
+
+	return nil
+}
+
+// LeaveRoom permite a un usuario abandonar una sala. El propietario no puede abandonarla
+func (s *RoomService) LeaveRoom(roomID string, userID string) error {
+	room, err := s.GetRoom(roomID)
 	if err != nil {
-		return false, fmt.Errorf("error getting room: %v", err)
+		return err
+	}
+
+	if !s.RoomRepo.HasRoomAccess(room, userID) {
+		return fmt.Errorf("user is not a member of the room")
 	}
 
-	// Check if user is the owner
 	if room.OwnerID == userID {
-		return true, nil
+		return fmt.Errorf("room owner cannot leave the room")
+	}
+
+	// Los admins del espacio tienen acceso a todos sus canales sin ser miembros; para salir dejan el espacio
+	if !contains(room.Members, userID) && !contains(room.Admins, userID) {
+		return fmt.Errorf("space admins cannot leave channels of their space")
+	}
+
+	return s.RoomRepo.RemoveMemberFromRoom(roomID, userID)
+}
+
+// RemoveMember expulsa a un miembro de una sala. Requiere la capacidad remove_members;
+// solo el propietario puede expulsar a quienes también la tienen y nadie puede expulsar al propietario
+func (s *RoomService) RemoveMember(roomID, actorID, targetID string) error {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+
+	if !s.Permissions.Can(room, actorID, models.CapRemoveMembers) {
+		return fmt.Errorf("user is not allowed to remove members")
+	}
+
+	if actorID == targetID {
+		return fmt.Errorf("use leave to exit the room")
+	}
+
+	if !s.RoomRepo.HasRoomAccess(room, targetID) {
+		return fmt.Errorf("user is not a member of the room")
+	}
+
+	if targetID == room.OwnerID {
+		return fmt.Errorf("the room owner cannot be removed")
+	}
+
+	// Solo el propietario puede expulsar a quienes también pueden expulsar miembros
+	if actorID != room.OwnerID && room.HasCapability(targetID, models.CapRemoveMembers) {
+		return fmt.Errorf("only the room owner can remove moderators")
+	}
+
+	if err := s.RoomRepo.RemoveMemberFromRoom(roomID, targetID); err != nil {
+		return err
+	}
+
+	s.Audit.Record(&models.AuditEntry{
+		RoomID:     roomID,
+		Action:     models.AuditMemberRemoved,
+		ActorID:    actorID,
+		TargetType: models.AuditTargetUser,
+		TargetID:   targetID,
+		Before:     map[string]interface{}{"role": room.RoleOf(targetID)},
+	})
+	return nil
+}
+
+// memberCursor es la posición del último miembro devuelto en el directorio
+type memberCursor struct {
+	JoinedAt time.Time `json:"j"`
+	UserID   string    `json:"id"`
+}
+
+// GetRoomMembers devuelve una página del directorio de miembros ordenada por fecha de unión.
+// Las salas privadas solo pueden consultarlas sus miembros. El estado de conexión lo completa el handler
+func (s *RoomService) GetRoomMembers(roomID, userID, role string, limit int, cursor string) (*models.PaginatedRoomMembersResponse, error) {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if room.IsPrivate && !s.RoomRepo.HasRoomAccess(room, userID) {
+		return nil, fmt.Errorf("user is not a member of the room")
+	}
+
+	if role != "" {
+		if _, ok := room.RoleDefinition(role); !ok {
+			return nil, fmt.Errorf("role not found")
+		}
+	}
+
+	if limit <= 0 {
+		limit = defaultMembersLimit
+	}
+	if limit > maxMembersLimit {
+		limit = maxMembersLimit
+	}
+
+	var after *memberCursor
+	if cursor != "" {
+		data, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || json.Unmarshal(data, &after) != nil || after == nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+	}
+
+	// Los miembros sin fecha de unión se ordenan con la fecha de creación de la sala
+	joinedAt := func(memberID string) time.Time {
+		if t, ok := room.JoinedAt[memberID]; ok {
+			return t
+		}
+		return room.CreatedAt
+	}
+	before := func(aTime time.Time, aID string, bTime time.Time, bID string) bool {
+		if !aTime.Equal(bTime) {
+			return aTime.Before(bTime)
+		}
+		return aID < bID
+	}
+
+	var memberIDs []string
+	for _, memberID := range append([]string{room.OwnerID}, room.Members...) {
+		if contains(memberIDs, memberID) || room.RoleOf(memberID) == "" {
+			continue
+		}
+		if role != "" && room.RoleOf(memberID) != role {
+			continue
+		}
+		if after != nil && !before(after.JoinedAt, after.UserID, joinedAt(memberID), memberID) {
+			continue
+		}
+		memberIDs = append(memberIDs, memberID)
+	}
+
+	sort.Slice(memberIDs, func(i, j int) bool {
+		return before(joinedAt(memberIDs[i]), memberIDs[i], joinedAt(memberIDs[j]), memberIDs[j])
+	})
+
+	response := &models.PaginatedRoomMembersResponse{Members: []models.RoomMember{}}
+	if len(memberIDs) > limit {
+		memberIDs = memberIDs[:limit]
+		last := memberIDs[limit-1]
+		data, _ := json.Marshal(memberCursor{JoinedAt: joinedAt(last), UserID: last})
+		response.NextCursor = base64.RawURLEncoding.EncodeToString(data)
+		response.HasMore = true
+	}
+
+	users, err := s.UserRepo.GetUsersByIDs(context.Background(), memberIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error getting members: %v", err)
+	}
+
+	// Los reportes de cada miembro solo los ven quienes los gestionan; los demás solo saben si está restringido
+	canSeeReports := s.Permissions.Can(room, userID, models.CapManageReports)
+
+	now := time.Now()
+	for _, memberID := range memberIDs {
+		member := models.RoomMember{
+			UserID:     memberID,
+			Role:       room.RoleOf(memberID),
+			Restricted: s.Permissions.IsRestricted(room, memberID),
+		}
+		if canSeeReports {
+			score, count := room.ReportScore(memberID, now)
+			member.ReportScore, member.ReportCount = &score, &count
+		}
+		if t, ok := room.JoinedAt[memberID]; ok {
+			member.JoinedAt = &t
+		}
+		if user, ok := users[memberID]; ok {
+			member.DisplayName = user.DisplayName
+			member.PhotoURL = user.PhotoURL
+		}
+		response.Members = append(response.Members, member)
+	}
+
+	return response, nil
+}
+
+// GetRoomRoles devuelve los roles efectivos de una sala. Solo sus miembros pueden consultarlos
+func (s *RoomService) GetRoomRoles(roomID, userID string) (map[string]models.RoomRole, error) {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.RoomRepo.HasRoomAccess(room, userID) {
+		return nil, fmt.Errorf("user is not a member of the room")
+	}
+
+	return room.EffectiveRoles(), nil
+}
+
+// SaveRoomRole crea o redefine un rol de la sala. El rol "owner" no se puede modificar
+func (s *RoomService) SaveRoomRole(roomID, actorID, roleName string, capabilities []models.Capability) (*models.RoomRole, error) {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if roleName == models.RoleOwner {
+		return nil, fmt.Errorf("the owner role cannot be modified")
+	}
+
+	if !roleNamePattern.MatchString(roleName) {
+		return nil, fmt.Errorf("invalid role name")
+	}
+
+	role := models.RoomRole{Name: roleName, Capabilities: []models.Capability{}}
+	for _, capability := range capabilities {
+		if !models.IsValidCapability(capability) {
+			return nil, fmt.Errorf("invalid capability: %s", capability)
+		}
+		if !role.Has(capability) {
+			role.Capabilities = append(role.Capabilities, capability)
+		}
+	}
+
+	// Quien edita un rol existente debe poder gestionar tanto sus capacidades actuales como las nuevas
+	current, exists := room.RoleDefinition(roleName)
+	if !s.Permissions.CanManageRole(room, actorID, role) || (exists && !s.Permissions.CanManageRole(room, actorID, current)) {
+		return nil, fmt.Errorf("user is not allowed to manage this role")
+	}
+
+	if err := s.RoomRepo.SaveRoomRole(roomID, role); err != nil {
+		return nil, err
+	}
+
+	var before map[string]interface{}
+	if exists {
+		before = map[string]interface{}{"capabilities": current.Capabilities}
+	}
+	s.recordRoleChange(roomID, models.AuditRoleUpdated, actorID, models.AuditTargetRole, roleName,
+		before, map[string]interface{}{"capabilities": role.Capabilities})
+	return &role, nil
+}
+
+// DeleteRoomRole elimina un rol propio de la sala, o restablece la definición predefinida de "admin" o "member".
+// Los miembros con un rol eliminado pasan a ser "member". Devuelve los usuarios afectados
+func (s *RoomService) DeleteRoomRole(roomID, actorID, roleName string) ([]string, error) {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if roleName == models.RoleOwner {
+		return nil, fmt.Errorf("the owner role cannot be modified")
+	}
+
+	current, exists := room.Roles[roleName]
+	if !exists {
+		return nil, fmt.Errorf("role not found")
+	}
+
+	if !s.Permissions.CanManageRole(room, actorID, current) {
+		return nil, fmt.Errorf("user is not allowed to manage this role")
+	}
+
+	// Los roles predefinidos vuelven a su definición por defecto y conservan a sus miembros
+	var affected []string
+	if _, builtIn := models.DefaultRoomRoles()[roleName]; !builtIn {
+		for userID, role := range room.MemberRoles {
+			if role == roleName {
+				affected = append(affected, userID)
+			}
+		}
+	}
+
+	if err := s.RoomRepo.DeleteRoomRole(roomID, roleName, affected); err != nil {
+		return nil, err
+	}
+
+	// Los roles predefinidos vuelven a su definición por defecto
+	var after map[string]interface{}
+	if defaultRole, builtIn := models.DefaultRoomRoles()[roleName]; builtIn {
+		after = map[string]interface{}{"capabilities": defaultRole.Capabilities}
+	}
+	s.recordRoleChange(roomID, models.AuditRoleDeleted, actorID, models.AuditTargetRole, roleName,
+		map[string]interface{}{"capabilities": current.Capabilities}, after)
+	return affected, nil
+}
+
+// AssignRole cambia el rol de un miembro de la sala. El rol de propietario solo cambia mediante transferencia
+func (s *RoomService) AssignRole(roomID, actorID, targetID, roleName string) error {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+
+	if !s.Permissions.Can(room, actorID, models.CapManageRoles) {
+		return fmt.Errorf("user is not allowed to manage roles")
+	}
+
+	currentRole := room.RoleOf(targetID)
+	if currentRole == "" {
+		return fmt.Errorf("user is not a member of the room")
+	}
+
+	if currentRole == models.RoleOwner {
+		return fmt.Errorf("the room owner's role cannot be changed")
+	}
+
+	if roleName == models.RoleOwner {
+		return fmt.Errorf("ownership can only be transferred")
+	}
+
+	newRole, ok := room.RoleDefinition(roleName)
+	if !ok {
+		return fmt.Errorf("role not found")
+	}
+
+	if currentRole == roleName {
+		return fmt.Errorf("user already has this role")
+	}
+
+	// No se puede otorgar ni retirar un rol con capacidades que el actor no tiene
+	current, _ := room.RoleDefinition(currentRole)
+	if !s.Permissions.CanManageRole(room, actorID, newRole) || !s.Permissions.CanManageRole(room, actorID, current) {
+		return fmt.Errorf("user is not allowed to manage this role")
+	}
+
+	if err := s.RoomRepo.SetMemberRole(roomID, targetID, roleName); err != nil {
+		return err
+	}
+
+	s.recordRoleChange(roomID, models.AuditRoleAssigned, actorID, models.AuditTargetUser, targetID,
+		map[string]interface{}{"role": currentRole}, map[string]interface{}{"role": roleName})
+	return nil
+}
+
+// DemoteAdmin devuelve a un admin al rol "member"
+func (s *RoomService) DemoteAdmin(roomID, actorID, targetID string) error {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+
+	if room.RoleOf(targetID) != models.RoleAdmin {
+		return fmt.Errorf("user is not an admin of the room")
+	}
+
+	return s.AssignRole(roomID, actorID, targetID, models.RoleMember)
+}
+
+// RequestOwnershipTransfer inicia la transferencia de propiedad a otro miembro,
+// que debe confirmarla con AcceptOwnershipTransfer
+func (s *RoomService) RequestOwnershipTransfer(roomID, actorID, targetID string) (*models.OwnershipTransfer, error) {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if room.OwnerID != actorID {
+		return nil, fmt.Errorf("only the room owner can transfer ownership")
+	}
+
+	if targetID == actorID {
+		return nil, fmt.Errorf("user already owns the room")
+	}
+
+	if !s.RoomRepo.HasRoomAccess(room, targetID) {
+		return nil, fmt.Errorf("user is not a member of the room")
 	}
 
-	// Check if user is an admin
+	transfer := &models.OwnershipTransfer{
+		FromUserID:  actorID,
+		ToUserID:    targetID,
+		RequestedAt: time.Now(),
+	}
+
+	if err := s.RoomRepo.SetOwnershipTransfer(roomID, transfer); err != nil {
+		return nil, err
+	}
+
+	s.recordRoleChange(roomID, models.AuditOwnershipTransferRequested, actorID, models.AuditTargetUser, targetID,
+		nil, map[string]interface{}{"pendingOwnerId": targetID})
+	return transfer, nil
+}
+
+// AcceptOwnershipTransfer confirma una transferencia de propiedad pendiente dirigida al usuario
+func (s *RoomService) AcceptOwnershipTransfer(roomID, userID string) (*models.OwnershipTransfer, error) {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := room.OwnershipTransfer
+	if transfer == nil || transfer.ToUserID != userID {
+		return nil, fmt.Errorf("no pending ownership transfer for this user")
+	}
+
+	// La transferencia caduca o deja de ser válida si el propietario cambió mientras tanto
+	if time.Since(transfer.RequestedAt) > ownershipTransferTTL || transfer.FromUserID != room.OwnerID {
+		if err := s.RoomRepo.SetOwnershipTransfer(roomID, nil); err != nil {
+			log.Printf("Error clearing stale ownership transfer in room %s: %v", roomID, err)
+		}
+		return nil, fmt.Errorf("ownership transfer has expired")
+	}
+
+	if !s.RoomRepo.HasRoomAccess(room, userID) {
+		return nil, fmt.Errorf("user is not a member of the room")
+	}
+
+	if err := s.RoomRepo.TransferOwnership(roomID, userID, transfer.FromUserID); err != nil {
+		return nil, err
+	}
+
+	s.recordRoleChange(roomID, models.AuditOwnershipTransferred, userID, models.AuditTargetUser, userID,
+		map[string]interface{}{"ownerId": transfer.FromUserID}, map[string]interface{}{"ownerId": userID})
+	return transfer, nil
+}
+
+// CancelOwnershipTransfer cancela una transferencia pendiente. Puede hacerlo el propietario o rechazarla el destinatario
+func (s *RoomService) CancelOwnershipTransfer(roomID, userID string) (*models.OwnershipTransfer, error) {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := room.OwnershipTransfer
+	if transfer == nil {
+		return nil, fmt.Errorf("no pending ownership transfer for this user")
+	}
+
+	if userID != room.OwnerID && userID != transfer.ToUserID {
+		return nil, fmt.Errorf("only the room owner or the recipient can cancel the transfer")
+	}
+
+	if err := s.RoomRepo.SetOwnershipTransfer(roomID, nil); err != nil {
+		return nil, err
+	}
+
+	s.recordRoleChange(roomID, models.AuditOwnershipTransferCancelled, userID, models.AuditTargetUser, transfer.ToUserID,
+		map[string]interface{}{"pendingOwnerId": transfer.ToUserID}, nil)
+	return transfer, nil
+}
+
+// OwnershipChange describe un cambio automático de propietario de una sala
+type OwnershipChange struct {
+	RoomID          string `json:"roomId"`
+	PreviousOwnerID string `json:"previousOwnerId"`
+	NewOwnerID      string `json:"newOwnerId,omitempty"` // Vacío si la sala se eliminó por no tener sucesor
+}
+
+// HandleOwnerAccountDeletion traspasa las salas de un usuario eliminado al admin más antiguo de cada una.
+// Si no hay admins se elige al miembro más antiguo, y si la sala queda vacía se elimina. Si alguna sala
+// falla devuelve un error junto a los cambios ya hechos; repetirla solo procesa las salas que sigue teniendo
+func (s *RoomService) HandleOwnerAccountDeletion(userID string) ([]OwnershipChange, error) {
+	rooms, err := s.RoomRepo.GetRoomsOwnedBy(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []OwnershipChange
+	failed := 0
+	for _, room := range rooms {
+		change := OwnershipChange{RoomID: room.ID, PreviousOwnerID: userID}
+
+		successorID := successorFor(&room, userID)
+		if successorID == "" {
+			if err := s.RoomRepo.SoftDeleteRoom(room.ID); err != nil {
+				log.Printf("Error deleting orphaned room %s: %v", room.ID, err)
+				failed++
+				continue
+			}
+			s.Audit.Record(&models.AuditEntry{
+				RoomID:     room.ID,
+				Action:     models.AuditRoomDeleted,
+				TargetType: models.AuditTargetRoom,
+				TargetID:   room.ID,
+				Reason:     "owner account deleted",
+			})
+			changes = append(changes, change)
+			continue
+		}
+
+		if err := s.RoomRepo.TransferOwnership(room.ID, successorID, userID); err != nil {
+			log.Printf("Error transferring ownership of room %s: %v", room.ID, err)
+			failed++
+			continue
+		}
+
+		// El propietario eliminado deja de pertenecer a la sala
+		if err := s.RoomRepo.RemoveMemberFromRoom(room.ID, userID); err != nil {
+			log.Printf("Error removing deleted owner from room %s: %v", room.ID, err)
+		}
+
+		s.Audit.Record(&models.AuditEntry{
+			RoomID:     room.ID,
+			Action:     models.AuditOwnershipInheritedOnDeletion,
+			TargetType: models.AuditTargetUser,
+			TargetID:   successorID,
+			Before:     map[string]interface{}{"ownerId": userID},
+			After:      map[string]interface{}{"ownerId": successorID},
+			Reason:     "owner account deleted",
+		})
+
+		change.NewOwnerID = successorID
+		changes = append(changes, change)
+	}
+
+	if failed > 0 {
+		return changes, fmt.Errorf("could not hand off %d of %d rooms", failed, len(rooms))
+	}
+
+	return changes, nil
+}
+
+// successorFor elige al admin más antiguo (distinto del propietario saliente) o, si no hay, al miembro más antiguo.
+// Los admins sin fecha registrada son anteriores al registro, por lo que se consideran los más antiguos
+// y se ordenan según su posición en la lista de admins
+func successorFor(room *models.Room, leavingID string) string {
+	var candidates []string
 	for _, adminID := range room.Admins {
-		if adminID == userID {
-			return true, nil
+		if adminID != leavingID {
+			candidates = append(candidates, adminID)
+		}
+	}
+
+	if len(candidates) > 0 {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			since1, ok1 := room.AdminSince[candidates[i]]
+			since2, ok2 := room.AdminSince[candidates[j]]
+			if ok1 != ok2 {
+				return !ok1
+			}
+			return since1.Before(since2)
+		})
+		return candidates[0]
+	}
+
+	// Los miembros se añaden al final de la lista, por lo que el primero es el más antiguo
+	for _, memberID := range room.Members {
+		if memberID != leavingID {
+			return memberID
 		}
 	}
 
-	return false, nil
+	return ""
+}
+
+// recordRoleChange registra en la auditoría un cambio de roles o de propietario
+func (s *RoomService) recordRoleChange(roomID, action, actorID, targetType, targetID string, before, after map[string]interface{}) {
+	s.Audit.Record(&models.AuditEntry{
+		RoomID:     roomID,
+		Action:     action,
+		ActorID:    actorID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// DeleteMessage elimina un mensaje de la sala. El autor puede eliminar sus mensajes y
+// los roles con la capacidad delete_messages pueden eliminar los de cualquiera
+func (s *RoomService) DeleteMessage(roomID, actorID, messageID string) error {
+	room, err := s.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+
+	message, err := s.MessageRepo.GetMessageByID(roomID, messageID)
+	if err != nil || message.IsDeleted {
+		return fmt.Errorf("message not found")
+	}
+
+	if message.UserID != actorID && !s.Permissions.Can(room, actorID, models.CapDeleteMessages) {
+		return fmt.Errorf("user is not allowed to delete this message")
+	}
+
+	if err := s.MessageRepo.SoftDeleteMessage(roomID, messageID); err != nil {
+		return err
+	}
+
+	// Solo se audita cuando un moderador borra el mensaje de otro
+	if message.UserID != actorID {
+		s.Audit.Record(&models.AuditEntry{
+			RoomID:     roomID,
+			Action:     models.AuditMessageDeleted,
+			ActorID:    actorID,
+			TargetType: models.AuditTargetMessage,
+			TargetID:   messageID,
+			Before:     map[string]interface{}{"authorId": message.UserID, "content": message.Content},
+		})
+	}
+	return nil
+}
+
+// MigrateRooms completa los roles y los datos de descubrimiento de las salas existentes
+func (s *RoomService) MigrateRooms() error {
+	return s.RoomRepo.MigrateRooms()
+}
+
+// normalizeCategories valida las categorías de una sala y las convierte a su forma canónica sin repetidos
+func normalizeCategories(categories []string) ([]string, error) {
+	normalized := []string{}
+	for _, category := range categories {
+		category = models.NormalizeCategory(category)
+		if category == "" || contains(normalized, category) {
+			continue
+		}
+		if len([]rune(category)) > models.MaxRoomCategoryLength {
+			return nil, fmt.Errorf("category is too long")
+		}
+		normalized = append(normalized, category)
+	}
+
+	if len(normalized) > models.MaxRoomCategories {
+		return nil, fmt.Errorf("too many categories")
+	}
+
+	return normalized, nil
 }
 
 // Helper para verificar si un slice contiene un valor
@@ -109,3 +1043,29 @@ func contains(slice []string, value string) bool {
 	}
 	return false
 }
+
+// Helper para quitar un valor de un slice
+func removeString(slice []string, value string) []string {
+	result := make([]string, 0, len(slice))
+	for _, item := range slice {
+		if item != value {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Helper para quitar de una lista los mensajes de ciertos usuarios
+func filterBlockedMessages(messages []models.MessageResponse, blockedIDs []string) []models.MessageResponse {
+	if len(blockedIDs) == 0 {
+		return messages
+	}
+
+	visible := make([]models.MessageResponse, 0, len(messages))
+	for _, message := range messages {
+		if !contains(blockedIDs, message.UserID) {
+			visible = append(visible, message)
+		}
+	}
+	return visible
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/repositories"
+)
+
+// RoomStatsService construye las estadísticas de actividad de las salas a partir de sus contadores diarios
+type RoomStatsService struct {
+	StatsRepo   *repositories.RoomStatsRepository
+	RoomRepo    *repositories.RoomRepository
+	UserRepo    *repositories.UserRepository
+	Permissions *PermissionService
+}
+
+// NewRoomStatsService crea una nueva instancia de RoomStatsService
+func NewRoomStatsService(
+	statsRepo *repositories.RoomStatsRepository,
+	roomRepo *repositories.RoomRepository,
+	userRepo *repositories.UserRepository,
+	permissions *PermissionService,
+) *RoomStatsService {
+	return &RoomStatsService{
+		StatsRepo:   statsRepo,
+		RoomRepo:    roomRepo,
+		UserRepo:    userRepo,
+		Permissions: permissions,
+	}
+}
+
+// GetRoomStats devuelve las estadísticas de los últimos días de una sala. Requiere la capacidad manage_room
+func (s *RoomStatsService) GetRoomStats(roomID, userID string, days int) (*models.RoomStatsResponse, error) {
+	room, err := s.RoomRepo.GetRoom(roomID)
+	if err != nil || room.IsDeleted {
+		return nil, fmt.Errorf("room not found")
+	}
+
+	if !s.Permissions.Can(room, userID, models.CapManageRoom) {
+		return nil, fmt.Errorf("user is not allowed to view room stats")
+	}
+
+	if days <= 0 {
+		days = models.DefaultStatsDays
+	}
+	if days > models.MaxStatsDays {
+		days = models.MaxStatsDays
+	}
+
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -(days - 1))
+
+	daily, err := s.StatsRepo.GetDailyStats(roomID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byDate := make(map[string]models.RoomDailyStats, len(daily))
+	for _, day := range daily {
+		byDate[day.Date] = day
+	}
+
+	response := &models.RoomStatsResponse{
+		RoomID:              roomID,
+		From:                from.Format(models.StatsDateLayout),
+		To:                  to.Format(models.StatsDateLayout),
+		MessagesPerDay:      make([]models.DailyCount, 0, days),
+		ActiveSendersPerDay: make([]models.DailyCount, 0, days),
+		MemberGrowth:        make([]models.MemberGrowthPoint, 0, days),
+		ReportsPerDay:       make([]models.DailyCount, 0, days),
+		GeneratedAt:         now,
+	}
+
+	// Recorrer todos los días del periodo, incluidos los que no tienen actividad
+	senders := make(map[string]int)
+	hours := make(map[int]int)
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		key := date.Format(models.StatsDateLayout)
+		day := byDate[key]
+
+		response.MessagesPerDay = append(response.MessagesPerDay, models.DailyCount{Date: key, Count: day.Messages})
+		response.ActiveSendersPerDay = append(response.ActiveSendersPerDay, models.DailyCount{Date: key, Count: len(day.Senders)})
+		response.ReportsPerDay = append(response.ReportsPerDay, models.DailyCount{Date: key, Count: day.Reports})
+		response.MemberGrowth = append(response.MemberGrowth, models.MemberGrowthPoint{
+			Date:   key,
+			Joined: day.MembersJoined,
+			Left:   day.MembersLeft,
+		})
+
+		for senderID, count := range day.Senders {
+			senders[senderID] += count
+		}
+		for hour, count := range day.Hours {
+			if h, err := strconv.Atoi(hour); err == nil {
+				hours[h] += count
+			}
+		}
+	}
+
+	// El número de miembros se reconstruye hacia atrás a partir del contador actual de la sala
+	members := room.MemberCount
+	for i := len(response.MemberGrowth) - 1; i >= 0; i-- {
+		point := &response.MemberGrowth[i]
+		point.Members = members
+		members -= point.Joined - point.Left
+		if members < 0 {
+			members = 0
+		}
+	}
+
+	response.PeakHours = peakHours(hours)
+
+	response.TopPosters, err = s.topPosters(senders)
+	if err != nil {
+		return nil, err
+	}
+
+	response.TotalReports, err = s.StatsRepo.CountRoomReports(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// topPosters ordena a los usuarios por número de mensajes y devuelve los primeros con su nombre visible
+func (s *RoomStatsService) topPosters(senders map[string]int) ([]models.TopPoster, error) {
+	posters := make([]models.TopPoster, 0, len(senders))
+	for userID, count := range senders {
+		posters = append(posters, models.TopPoster{UserID: userID, Messages: count})
+	}
+
+	sort.Slice(posters, func(i, j int) bool {
+		if posters[i].Messages != posters[j].Messages {
+			return posters[i].Messages > posters[j].Messages
+		}
+		return posters[i].UserID < posters[j].UserID
+	})
+
+	if len(posters) > models.TopPostersLimit {
+		posters = posters[:models.TopPostersLimit]
+	}
+
+	userIDs := make([]string, 0, len(posters))
+	for _, poster := range posters {
+		userIDs = append(userIDs, poster.UserID)
+	}
+
+	users, err := s.UserRepo.GetUsersByIDs(context.Background(), userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error getting users: %v", err)
+	}
+
+	for i := range posters {
+		if user, ok := users[posters[i].UserID]; ok {
+			posters[i].DisplayName = user.DisplayName
+		}
+	}
+
+	return posters, nil
+}
+
+// peakHours devuelve las horas con actividad ordenadas de más a menos mensajes
+func peakHours(hours map[int]int) []models.HourCount {
+	peaks := make([]models.HourCount, 0, len(hours))
+	for hour, count := range hours {
+		if count > 0 {
+			peaks = append(peaks, models.HourCount{Hour: hour, Messages: count})
+		}
+	}
+
+	sort.Slice(peaks, func(i, j int) bool {
+		if peaks[i].Messages != peaks[j].Messages {
+			return peaks[i].Messages > peaks[j].Messages
+		}
+		return peaks[i].Hour < peaks[j].Hour
+	})
+
+	return peaks
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// maxSanctionHistory es el número máximo de sanciones que se devuelven del historial de una sala
+const maxSanctionHistory = 100
+
+// BanResult es el resultado de banear a un usuario de una sala
+type BanResult struct {
+	Sanction          *models.RoomSanction
+	WasMember         bool     // El usuario era miembro y se le sacó de la sala
+	DeletedMessageIDs []string // Mensajes recientes del usuario que se borraron
+}
+
+// SanctionService maneja los silencios y baneos temporales o permanentes que imponen los moderadores.
+// Requieren la capacidad moderate_members
+type SanctionService struct {
+	SanctionRepo *repositories.SanctionRepository
+	RoomRepo     *repositories.RoomRepository
+	MessageRepo  *repositories.MessageRepository
+	UserRepo     *repositories.UserRepository
+	Permissions  *PermissionService
+	Audit        *AuditService
+}
+
+// NewSanctionService crea una nueva instancia de SanctionService
+func NewSanctionService(
+	sanctionRepo *repositories.SanctionRepository,
+	roomRepo *repositories.RoomRepository,
+	messageRepo *repositories.MessageRepository,
+	userRepo *repositories.UserRepository,
+	permissions *PermissionService,
+	audit *AuditService,
+) *SanctionService {
+	return &SanctionService{
+		SanctionRepo: sanctionRepo,
+		RoomRepo:     roomRepo,
+		MessageRepo:  messageRepo,
+		UserRepo:     userRepo,
+		Permissions:  permissions,
+		Audit:        audit,
+	}
+}
+
+// MuteMember silencia a un miembro de la sala durante un tiempo. Un nuevo silencio reemplaza al anterior
+func (s *SanctionService) MuteMember(roomID, actorID, targetID string, req *models.MuteMemberRequest) (*models.RoomSanction, error) {
+	room, err := s.getRoomForSanction(roomID, actorID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.RoomRepo.HasRoomAccess(room, targetID) {
+		return nil, fmt.Errorf("user is not a member of the room")
+	}
+
+	if req.DurationSeconds < 1 || req.DurationSeconds > models.MaxMuteSeconds {
+		return nil, fmt.Errorf("invalid mute duration")
+	}
+
+	sanction := newSanction(roomID, actorID, targetID, models.SanctionMute, req.Reason, req.DurationSeconds)
+	if err := s.SanctionRepo.CreateSanction(sanction); err != nil {
+		return nil, err
+	}
+
+	s.recordSanction(models.AuditMemberMuted, actorID, previousSanction(room.Mutes, targetID), sanction, nil)
+	return sanction, nil
+}
+
+// UnmuteMember levanta el silencio de un miembro
+func (s *SanctionService) UnmuteMember(roomID, actorID, targetID string) (*models.RoomSanction, error) {
+	return s.liftSanction(roomID, actorID, targetID, models.SanctionMute, "user is not muted")
+}
+
+// BanUser banea a un usuario de la sala, para siempre o durante un tiempo. Si es miembro se le saca de la
+// sala y, si se pide, se borran sus mensajes de las últimas horas
+func (s *SanctionService) BanUser(roomID, actorID, targetID string, req *models.BanMemberRequest) (*BanResult, error) {
+	room, err := s.getRoomForSanction(roomID, actorID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DurationSeconds < 0 || req.DurationSeconds > models.MaxBanSeconds {
+		return nil, fmt.Errorf("invalid ban duration")
+	}
+	if req.DeleteMessagesHours < 0 || req.DeleteMessagesHours > models.MaxBanDeleteMessagesHours {
+		return nil, fmt.Errorf("invalid message deletion window")
+	}
+
+	target, err := s.UserRepo.GetUserByID(context.Background(), targetID)
+	if err != nil || target == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	sanction := newSanction(roomID, actorID, targetID, models.SanctionBan, req.Reason, req.DurationSeconds)
+	if err := s.SanctionRepo.CreateSanction(sanction); err != nil {
+		return nil, err
+	}
+
+	result := &BanResult{Sanction: sanction}
+	defer func() {
+		s.recordSanction(models.AuditMemberBanned, actorID, previousSanction(room.Bans, targetID), sanction, map[string]interface{}{
+			"removedFromRoom": result.WasMember,
+			"deletedMessages": len(result.DeletedMessageIDs),
+		})
+	}()
+
+	if contains(room.Members, targetID) {
+		if err := s.RoomRepo.RemoveMemberFromRoom(roomID, targetID); err != nil {
+			return nil, err
+		}
+		result.WasMember = true
+	}
+
+	if req.DeleteMessagesHours > 0 {
+		since := sanction.CreatedAt.Add(-time.Duration(req.DeleteMessagesHours) * time.Hour)
+		deleted, err := s.MessageRepo.SoftDeleteUserMessagesSince(roomID, targetID, since)
+		result.DeletedMessageIDs = deleted
+		if err != nil {
+			return result, fmt.Errorf("error deleting messages: %v", err)
+		}
+	}
+
+	return result, nil
+}
+
+// UnbanUser levanta el baneo de un usuario. No vuelve a añadirlo a la sala
+func (s *SanctionService) UnbanUser(roomID, actorID, targetID string) (*models.RoomSanction, error) {
+	return s.liftSanction(roomID, actorID, targetID, models.SanctionBan, "user is not banned")
+}
+
+// GetRoomSanctions devuelve el historial de sanciones de la sala, de la más reciente a la más antigua
+func (s *SanctionService) GetRoomSanctions(roomID, actorID string) ([]models.RoomSanction, error) {
+	room, err := s.RoomRepo.GetRoom(roomID)
+	if err != nil || room.IsDeleted {
+		return nil, fmt.Errorf("room not found")
+	}
+
+	if !s.Permissions.Can(room, actorID, models.CapModerateMembers) {
+		return nil, fmt.Errorf("user is not allowed to moderate members")
+	}
+
+	sanctions, err := s.SanctionRepo.GetRoomSanctions(roomID, maxSanctionHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs []string
+	for _, sanction := range sanctions {
+		userIDs = append(userIDs, sanction.UserID)
+	}
+	users, err := s.UserRepo.GetUsersByIDs(context.Background(), userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range sanctions {
+		if user, ok := users[sanctions[i].UserID]; ok {
+			sanctions[i].DisplayName = user.DisplayName
+		}
+		// Las sanciones caducadas que el proceso aún no ha desactivado se muestran ya inactivas
+		sanctions[i].Active = sanctions[i].IsActiveAt(now)
+	}
+
+	return sanctions, nil
+}
+
+// ExpireSanctions desactiva las sanciones caducadas de todas las salas y devuelve las que se quitaron
+// de su sala, para avisar a sus miembros
+func (s *SanctionService) ExpireSanctions() ([]models.RoomSanction, error) {
+	sanctions, err := s.SanctionRepo.GetExpiredSanctions(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []models.RoomSanction
+	for _, sanction := range sanctions {
+		removed, err := s.SanctionRepo.ExpireSanction(sanction)
+		if err != nil {
+			log.Printf("Error expiring sanction %s in room %s: %v", sanction.ID, sanction.RoomID, err)
+			continue
+		}
+		if removed {
+			expired = append(expired, sanction)
+			s.Audit.Record(&models.AuditEntry{
+				RoomID:     sanction.RoomID,
+				Action:     models.AuditSanctionExpired,
+				TargetType: models.AuditTargetUser,
+				TargetID:   sanction.UserID,
+				Before:     sanctionState(&sanction),
+			})
+		}
+	}
+
+	return expired, nil
+}
+
+// getRoomForSanction carga la sala y verifica que el moderador puede sancionar al usuario. Nadie puede
+// sancionarse a sí mismo ni al propietario, y solo el propietario puede sancionar a otros moderadores
+func (s *SanctionService) getRoomForSanction(roomID, actorID, targetID string) (*models.Room, error) {
+	room, err := s.RoomRepo.GetRoom(roomID)
+	if err != nil || room.IsDeleted {
+		return nil, fmt.Errorf("room not found")
+	}
+
+	if !s.Permissions.Can(room, actorID, models.CapModerateMembers) {
+		return nil, fmt.Errorf("user is not allowed to moderate members")
+	}
+
+	if actorID == targetID {
+		return nil, fmt.Errorf("users cannot sanction themselves")
+	}
+
+	if targetID == room.OwnerID {
+		return nil, fmt.Errorf("the room owner cannot be sanctioned")
+	}
+
+	if actorID != room.OwnerID && room.HasCapability(targetID, models.CapModerateMembers) {
+		return nil, fmt.Errorf("only the room owner can sanction moderators")
+	}
+
+	return room, nil
+}
+
+// liftSanction levanta la sanción activa de un tipo de un usuario
+func (s *SanctionService) liftSanction(roomID, actorID, targetID, sanctionType, notFound string) (*models.RoomSanction, error) {
+	room, err := s.RoomRepo.GetRoom(roomID)
+	if err != nil || room.IsDeleted {
+		return nil, fmt.Errorf("room not found")
+	}
+
+	if !s.Permissions.Can(room, actorID, models.CapModerateMembers) {
+		return nil, fmt.Errorf("user is not allowed to moderate members")
+	}
+
+	lifted, err := s.SanctionRepo.LiftSanction(roomID, targetID, sanctionType, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if lifted == nil {
+		return nil, fmt.Errorf("%s", notFound)
+	}
+
+	action := models.AuditMemberUnmuted
+	if sanctionType == models.SanctionBan {
+		action = models.AuditMemberUnbanned
+	}
+	s.Audit.Record(&models.AuditEntry{
+		RoomID:     roomID,
+		Action:     action,
+		ActorID:    actorID,
+		TargetType: models.AuditTargetUser,
+		TargetID:   targetID,
+		Before:     sanctionState(lifted),
+	})
+
+	return lifted, nil
+}
+
+// newSanction crea una sanción activa. Una duración de 0 no caduca
+func newSanction(roomID, actorID, targetID, sanctionType, reason string, durationSeconds int) *models.RoomSanction {
+	now := time.Now()
+	sanction := &models.RoomSanction{
+		ID:        uuid.New().String(),
+		RoomID:    roomID,
+		UserID:    targetID,
+		Type:      sanctionType,
+		ActorID:   actorID,
+		Reason:    reason,
+		CreatedAt: now,
+		Active:    true,
+	}
+	if durationSeconds > 0 {
+		expiresAt := now.Add(time.Duration(durationSeconds) * time.Second)
+		sanction.ExpiresAt = &expiresAt
+	}
+	return sanction
+}
+
+// recordSanction registra en la auditoría un silencio o baneo nuevo, con la sanción a la que reemplaza
+func (s *SanctionService) recordSanction(action, actorID string, previous, sanction *models.RoomSanction, extra map[string]interface{}) {
+	after := sanctionState(sanction)
+	for key, value := range extra {
+		after[key] = value
+	}
+
+	var before map[string]interface{}
+	if previous != nil {
+		before = sanctionState(previous)
+	}
+
+	s.Audit.Record(&models.AuditEntry{
+		RoomID:     sanction.RoomID,
+		Action:     action,
+		ActorID:    actorID,
+		TargetType: models.AuditTargetUser,
+		TargetID:   sanction.UserID,
+		Before:     before,
+		After:      after,
+		Reason:     sanction.Reason,
+	})
+}
+
+// previousSanction devuelve la sanción activa de un usuario que una nueva va a reemplazar
+func previousSanction(sanctions map[string]models.RoomSanction, userID string) *models.RoomSanction {
+	if previous, ok := sanctions[userID]; ok && previous.IsActiveAt(time.Now()) {
+		return &previous
+	}
+	return nil
+}
+
+// sanctionState describe una sanción para la auditoría
+func sanctionState(sanction *models.RoomSanction) map[string]interface{} {
+	return map[string]interface{}{
+		"sanctionId": sanction.ID,
+		"type":       sanction.Type,
+		"expiresAt":  sanction.ExpiresAt,
+	}
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Parchat/backend/internal/models"
+	"github.com/Parchat/backend/internal/repositories"
+)
+
+const (
+	// defaultSpacesLimit es el número de espacios por defecto del listado
+	defaultSpacesLimit = 20
+	// maxSpacesLimit es el número máximo de espacios del listado
+	maxSpacesLimit = 50
+)
+
+// SpaceService maneja la lógica de negocio de los espacios y sus canales
+type SpaceService struct {
+	SpaceRepo   *repositories.SpaceRepository
+	RoomRepo    *repositories.RoomRepository
+	RoomService *RoomService
+	Audit       *AuditService
+}
+
+// NewSpaceService crea una nueva instancia de SpaceService
+func NewSpaceService(
+	spaceRepo *repositories.SpaceRepository,
+	roomRepo *repositories.RoomRepository,
+	roomService *RoomService,
+	audit *AuditService,
+) *SpaceService {
+	return &SpaceService{
+		SpaceRepo:   spaceRepo,
+		RoomRepo:    roomRepo,
+		RoomService: roomService,
+		Audit:       audit,
+	}
+}
+
+// CreateSpace crea un espacio con el usuario como propietario
+func (s *SpaceService) CreateSpace(ownerID string, req *models.CreateSpaceRequest) (*models.Space, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("space name is required")
+	}
+
+	space := &models.Space{
+		Name:        name,
+		Description: req.Description,
+		ImageURL:    req.ImageURL,
+		OwnerID:     ownerID,
+		Admins:      []string{ownerID},
+		Members:     []string{ownerID},
+		MemberCount: 1,
+		Channels:    []string{},
+	}
+
+	if err := s.SpaceRepo.CreateSpace(space); err != nil {
+		return nil, err
+	}
+
+	return space, nil
+}
+
+// GetSpace obtiene un espacio que no ha sido eliminado
+func (s *SpaceService) GetSpace(spaceID string) (*models.Space, error) {
+	space, err := s.SpaceRepo.GetSpace(spaceID)
+	if err != nil || space.IsDeleted {
+		return nil, fmt.Errorf("space not found")
+	}
+
+	return space, nil
+}
+
+// GetSpaceDetails obtiene un espacio junto con los canales que el usuario puede ver:
+// los públicos y los privados a los que tiene acceso
+func (s *SpaceService) GetSpaceDetails(spaceID, userID string) (*models.SpaceResponse, error) {
+	space, err := s.GetSpace(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := s.RoomRepo.GetSpaceChannels(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := []models.RoomSummary{}
+	for i := range channels {
+		if !channels[i].IsPrivate || s.RoomRepo.HasRoomAccess(&channels[i], userID) {
+			visible = append(visible, channels[i].Summary())
+		}
+	}
+
+	return &models.SpaceResponse{
+		Space:        *space,
+		ChannelRooms: visible,
+		Role:         space.RoleOf(userID),
+	}, nil
+}
+
+// ListSpaces devuelve los espacios con más miembros
+func (s *SpaceService) ListSpaces(limit int) ([]models.SpaceSummary, error) {
+	if limit <= 0 {
+		limit = defaultSpacesLimit
+	}
+	if limit > maxSpacesLimit {
+		limit = maxSpacesLimit
+	}
+
+	spaces, err := s.SpaceRepo.ListSpaces(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return summarizeSpaces(spaces), nil
+}
+
+// GetUserSpaces devuelve los espacios a los que pertenece un usuario
+func (s *SpaceService) GetUserSpaces(userID string) ([]models.SpaceSummary, error) {
+	spaces, err := s.SpaceRepo.GetUserSpaces(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return summarizeSpaces(spaces), nil
+}
+
+// JoinSpace une al usuario al espacio y a todos sus canales públicos
+func (s *SpaceService) JoinSpace(spaceID, userID string) error {
+	if err := s.SpaceRepo.AddMemberToSpace(spaceID, userID); err != nil {
+		return err
+	}
+
+	channels, err := s.RoomRepo.GetSpaceChannels(spaceID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, channel := range channels {
+		// Los canales de los que el usuario está baneado se saltan
+		if channel.IsPrivate || contains(channel.Members, userID) || channel.IsBanned(userID, now) {
+			continue
+		}
+		if err := s.RoomRepo.AddMemberToRoom(channel.ID, userID); err != nil && err.Error() != "user is already a member of the room" {
+			log.Printf("Error adding user %s to channel %s of space %s: %v", userID, channel.ID, spaceID, err)
+		}
+	}
+
+	return nil
+}
+
+// LeaveSpace saca al usuario del espacio y de todos sus canales. El propietario no puede abandonarlo
+func (s *SpaceService) LeaveSpace(spaceID, userID string) error {
+	space, err := s.GetSpace(spaceID)
+	if err != nil {
+		return err
+	}
+
+	if space.OwnerID == userID {
+		return fmt.Errorf("space owner cannot leave the space")
+	}
+
+	if !space.IsMember(userID) {
+		return fmt.Errorf("user is not a member of the space")
+	}
+
+	if err := s.SpaceRepo.RemoveMemberFromSpace(spaceID, userID); err != nil {
+		return err
+	}
+
+	channels, err := s.RoomRepo.GetSpaceChannels(spaceID)
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		if contains(channel.SpaceAdmins, userID) {
+			if err := s.RoomRepo.SetSpaceAdmin(channel.ID, userID, false); err != nil {
+				log.Printf("Error removing space admin %s from channel %s: %v", userID, channel.ID, err)
+			}
+		}
+		if channel.OwnerID != userID && contains(channel.Members, userID) {
+			if err := s.RoomRepo.RemoveMemberFromRoom(channel.ID, userID); err != nil {
+				log.Printf("Error removing user %s from channel %s of space %s: %v", userID, channel.ID, spaceID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CreateChannel crea una sala dentro del espacio. Solo los admins del espacio pueden crear canales.
+// Los canales públicos empiezan con todos los miembros del espacio y los privados solo con los
+// usuarios indicados que pertenezcan al espacio
+func (s *SpaceService) CreateChannel(spaceID, actorID string, req *models.CreateRoomRequest) (*models.Room, error) {
+	space, err := s.GetSpace(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !space.IsAdmin(actorID) {
+		return nil, fmt.Errorf("user is not allowed to manage the space")
+	}
+
+	members := []string{actorID}
+	if req.IsPrivate {
+		for _, userID := range req.UserIDs {
+			if space.IsMember(userID) && !contains(members, userID) {
+				members = append(members, userID)
+			}
+		}
+	} else {
+		for _, userID := range space.Members {
+			if !contains(members, userID) {
+				members = append(members, userID)
+			}
+		}
+	}
+
+	// El propietario del espacio lo es también de todos sus canales
+	room := &models.Room{
+		Name:        req.Name,
+		Description: req.Description,
+		IsPrivate:   req.IsPrivate,
+		Categories:  req.Categories,
+		OwnerID:     space.OwnerID,
+		Members:     members,
+		SpaceID:     spaceID,
+		SpaceAdmins: space.Admins,
+	}
+
+	if err := s.RoomService.CreateRoom(room); err != nil {
+		return nil, err
+	}
+
+	if err := s.SpaceRepo.AddChannel(spaceID, room.ID); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// SetMemberRole cambia el rol de un miembro del espacio entre "admin" y "member" y lo replica
+// en todos los canales. Solo el propietario del espacio puede hacerlo
+func (s *SpaceService) SetMemberRole(spaceID, actorID, targetID, role string) error {
+	if role != models.RoleAdmin && role != models.RoleMember {
+		return fmt.Errorf("invalid role")
+	}
+
+	space, err := s.GetSpace(spaceID)
+	if err != nil {
+		return err
+	}
+
+	if space.OwnerID != actorID {
+		return fmt.Errorf("only the space owner can change roles")
+	}
+
+	if space.OwnerID == targetID {
+		return fmt.Errorf("cannot change the role of the space owner")
+	}
+
+	if !space.IsMember(targetID) {
+		return fmt.Errorf("user is not a member of the space")
+	}
+
+	currentRole := space.RoleOf(targetID)
+	isAdmin := role == models.RoleAdmin
+	if err := s.SpaceRepo.SetSpaceAdmin(spaceID, targetID, isAdmin); err != nil {
+		return err
+	}
+
+	channels, err := s.RoomRepo.GetSpaceChannels(spaceID)
+	if err != nil {
+		return err
+	}
+
+	// El registro de auditoría es por sala, así que el cambio se anota en cada canal afectado
+	for _, channel := range channels {
+		if err := s.RoomRepo.SetSpaceAdmin(channel.ID, targetID, isAdmin); err != nil {
+			log.Printf("Error updating space admin %s in channel %s: %v", targetID, channel.ID, err)
+			continue
+		}
+		if currentRole != role {
+			s.Audit.Record(&models.AuditEntry{
+				RoomID:     channel.ID,
+				Action:     models.AuditSpaceRoleAssigned,
+				ActorID:    actorID,
+				TargetType: models.AuditTargetUser,
+				TargetID:   targetID,
+				Before:     map[string]interface{}{"spaceId": spaceID, "spaceRole": currentRole},
+				After:      map[string]interface{}{"spaceId": spaceID, "spaceRole": role},
+			})
+		}
+	}
+
+	return nil
+}
+
+// summarizeSpaces convierte los espacios en su información de listado
+func summarizeSpaces(spaces []models.Space) []models.SpaceSummary {
+	summaries := make([]models.SpaceSummary, 0, len(spaces))
+	for i := range spaces {
+		summaries = append(summaries, spaces[i].Summary())
+	}
+	return summaries
+}
@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	"fmt"
 
+	"firebase.google.com/go/v4/auth"
 	"github.com/Parchat/backend/internal/config"
 	"github.com/Parchat/backend/internal/models"
 	"github.com/Parchat/backend/internal/repositories"
@@ -11,13 +13,15 @@ import (
 type UserService struct {
 	UserRepo     *repositories.UserRepository
 	FirebaseAuth *config.FirebaseAuth
+	RoomService  *RoomService
 }
 
 // NewUserService crea una nueva instancia de UserService
-func NewUserService(userRepo *repositories.UserRepository, firebaseAuth *config.FirebaseAuth) *UserService {
+func NewUserService(userRepo *repositories.UserRepository, firebaseAuth *config.FirebaseAuth, roomService *RoomService) *UserService {
 	return &UserService{
 		UserRepo:     userRepo,
 		FirebaseAuth: firebaseAuth,
+		RoomService:  roomService,
 	}
 }
 
@@ -56,3 +60,150 @@ func (s *UserService) EnsureUserExists(ctx context.Context, authUser *models.Use
 	}
 	return authUser, nil
 }
+
+// DeleteAccount elimina la cuenta de un usuario: traspasa la propiedad de sus salas, la marca como
+// eliminada y deshabilita su acceso. Devuelve los cambios de propietario realizados. Cada paso se puede
+// repetir, y el acceso se deshabilita al final para que el usuario pueda reintentarlo si algo falla antes
+func (s *UserService) DeleteAccount(ctx context.Context, userID string) ([]OwnershipChange, error) {
+	changes, err := s.RoomService.HandleOwnerAccountDeletion(userID)
+	if err != nil {
+		return changes, err
+	}
+
+	if err := s.UserRepo.MarkUserDeleted(ctx, userID); err != nil {
+		return changes, err
+	}
+
+	// Deshabilitar en Firebase Auth para impedir nuevos inicios de sesión
+	if _, err := s.FirebaseAuth.Client.UpdateUser(ctx, userID, (&auth.UserToUpdate{}).Disabled(true)); err != nil {
+		return changes, err
+	}
+
+	return changes, nil
+}
+
+// BlockUser bloquea a un usuario: no podrá abrir chats directos con el usuario ni enviarle mensajes
+// directos, y sus mensajes en las salas dejan de mostrarse al usuario
+func (s *UserService) BlockUser(ctx context.Context, userID, targetID string) error {
+	if userID == targetID {
+		return fmt.Errorf("cannot block yourself")
+	}
+
+	target, err := s.UserRepo.GetUserByID(ctx, targetID)
+	if err != nil || target == nil {
+		return fmt.Errorf("user not found")
+	}
+
+	return s.UserRepo.SetUserBlocked(ctx, userID, targetID, true)
+}
+
+// UnblockUser desbloquea a un usuario
+func (s *UserService) UnblockUser(ctx context.Context, userID, targetID string) error {
+	return s.UserRepo.SetUserBlocked(ctx, userID, targetID, false)
+}
+
+// GetBlockedUserIDs obtiene los IDs de los usuarios bloqueados por un usuario
+func (s *UserService) GetBlockedUserIDs(ctx context.Context, userID string) ([]string, error) {
+	return s.UserRepo.GetBlockedUsers(ctx, userID)
+}
+
+// GetBlockedUsers obtiene los usuarios bloqueados por un usuario con sus nombres
+func (s *UserService) GetBlockedUsers(ctx context.Context, userID string) ([]models.BlockedUser, error) {
+	blockedIDs, err := s.UserRepo.GetBlockedUsers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.UserRepo.GetUsersByIDs(ctx, blockedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked := make([]models.BlockedUser, 0, len(blockedIDs))
+	for _, id := range blockedIDs {
+		entry := models.BlockedUser{UserID: id, DisplayName: "Usuario Desconocido"}
+		if user, ok := users[id]; ok {
+			entry.DisplayName = user.DisplayName
+			entry.PhotoURL = user.PhotoURL
+		}
+		blocked = append(blocked, entry)
+	}
+
+	return blocked, nil
+}
+
+// AddContact añade un usuario a los contactos del usuario: podrá abrirle chats directos sin pasar por las solicitudes de mensajes
+func (s *UserService) AddContact(ctx context.Context, userID, contactID string) error {
+	if userID == contactID {
+		return fmt.Errorf("cannot add yourself as a contact")
+	}
+
+	contact, err := s.UserRepo.GetUserByID(ctx, contactID)
+	if err != nil || contact == nil || contact.IsDeleted {
+		return fmt.Errorf("user not found")
+	}
+
+	return s.UserRepo.SetContact(ctx, userID, contactID, true)
+}
+
+// RemoveContact quita un usuario de los contactos del usuario
+func (s *UserService) RemoveContact(ctx context.Context, userID, contactID string) error {
+	return s.UserRepo.SetContact(ctx, userID, contactID, false)
+}
+
+// GetContacts obtiene los contactos de un usuario con sus nombres
+func (s *UserService) GetContacts(ctx context.Context, userID string) ([]models.Contact, error) {
+	user, err := s.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return []models.Contact{}, nil
+	}
+
+	users, err := s.UserRepo.GetUsersByIDs(ctx, user.Contacts)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := make([]models.Contact, 0, len(user.Contacts))
+	for _, id := range user.Contacts {
+		entry := models.Contact{UserID: id, DisplayName: "Usuario Desconocido"}
+		if contact, ok := users[id]; ok {
+			entry.DisplayName = contact.DisplayName
+			entry.PhotoURL = contact.PhotoURL
+		}
+		contacts = append(contacts, entry)
+	}
+
+	return contacts, nil
+}
+
+// GetPrivacySettings obtiene los ajustes de privacidad de un usuario
+func (s *UserService) GetPrivacySettings(ctx context.Context, userID string) (*models.PrivacySettings, error) {
+	user, err := s.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &models.PrivacySettings{DMPrivacy: models.DMPrivacyEveryone}
+	if user != nil && user.DMPrivacy != "" {
+		settings.DMPrivacy = user.DMPrivacy
+	}
+
+	return settings, nil
+}
+
+// UpdatePrivacySettings cambia los ajustes de privacidad de un usuario. Los chats directos existentes
+// siguen siendo legibles, pero los nuevos envíos se validan con los ajustes nuevos
+func (s *UserService) UpdatePrivacySettings(ctx context.Context, userID string, req *models.UpdatePrivacySettingsRequest) (*models.PrivacySettings, error) {
+	if !models.IsValidDMPrivacy(req.DMPrivacy) {
+		return nil, fmt.Errorf("invalid dm privacy")
+	}
+
+	if err := s.UserRepo.UpdateDMPrivacy(ctx, userID, req.DMPrivacy); err != nil {
+		return nil, err
+	}
+
+	return &models.PrivacySettings{DMPrivacy: req.DMPrivacy}, nil
+}